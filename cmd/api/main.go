@@ -1,20 +1,83 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"time"
 
 	"github.com/evrintobing17/loan-billing-system/config"
+	accountingHttp "github.com/evrintobing17/loan-billing-system/internal/accounting/handler/http"
+	accountingRepo "github.com/evrintobing17/loan-billing-system/internal/accounting/repository"
+	accountingUsecase "github.com/evrintobing17/loan-billing-system/internal/accounting/usecase"
+	accrualHttp "github.com/evrintobing17/loan-billing-system/internal/accrual/handler/http"
+	accrualRepo "github.com/evrintobing17/loan-billing-system/internal/accrual/repository"
+	accrualUsecase "github.com/evrintobing17/loan-billing-system/internal/accrual/usecase"
+	applicationHttp "github.com/evrintobing17/loan-billing-system/internal/application/handler/http"
+	applicationRepo "github.com/evrintobing17/loan-billing-system/internal/application/repository"
+	applicationUsecase "github.com/evrintobing17/loan-billing-system/internal/application/usecase"
+	borrowerHttp "github.com/evrintobing17/loan-billing-system/internal/borrower/handler/http"
+	borrowerRepo "github.com/evrintobing17/loan-billing-system/internal/borrower/repository"
+	borrowerUsecase "github.com/evrintobing17/loan-billing-system/internal/borrower/usecase"
+	collateralHttp "github.com/evrintobing17/loan-billing-system/internal/collateral/handler/http"
+	collateralRepo "github.com/evrintobing17/loan-billing-system/internal/collateral/repository"
+	collateralUsecase "github.com/evrintobing17/loan-billing-system/internal/collateral/usecase"
+	creditRepo "github.com/evrintobing17/loan-billing-system/internal/credit/repository"
+	creditRules "github.com/evrintobing17/loan-billing-system/internal/credit/rules"
+	creditUsecase "github.com/evrintobing17/loan-billing-system/internal/credit/usecase"
+	directDebitHttp "github.com/evrintobing17/loan-billing-system/internal/directdebit/handler/http"
+	directDebitRepo "github.com/evrintobing17/loan-billing-system/internal/directdebit/repository"
+	directDebitUsecase "github.com/evrintobing17/loan-billing-system/internal/directdebit/usecase"
+	disbursementHttp "github.com/evrintobing17/loan-billing-system/internal/disbursement/handler/http"
+	disbursementRepo "github.com/evrintobing17/loan-billing-system/internal/disbursement/repository"
+	disbursementUsecase "github.com/evrintobing17/loan-billing-system/internal/disbursement/usecase"
+	eventStreamHttp "github.com/evrintobing17/loan-billing-system/internal/eventstream/handler/http"
+	eventStreamPublisher "github.com/evrintobing17/loan-billing-system/internal/eventstream/publisher"
+	eventStreamRepo "github.com/evrintobing17/loan-billing-system/internal/eventstream/repository"
+	eventStreamUsecase "github.com/evrintobing17/loan-billing-system/internal/eventstream/usecase"
 	loanHttp "github.com/evrintobing17/loan-billing-system/internal/loan/handler/http"
 	loanRepo "github.com/evrintobing17/loan-billing-system/internal/loan/repository"
 	loanUsecase "github.com/evrintobing17/loan-billing-system/internal/loan/usecase"
+	loanImportHttp "github.com/evrintobing17/loan-billing-system/internal/loanimport/handler/http"
+	loanImportRepo "github.com/evrintobing17/loan-billing-system/internal/loanimport/repository"
+	loanImportUsecase "github.com/evrintobing17/loan-billing-system/internal/loanimport/usecase"
+	notificationChannel "github.com/evrintobing17/loan-billing-system/internal/notification/channel"
+	notificationHttp "github.com/evrintobing17/loan-billing-system/internal/notification/handler/http"
+	notificationRepo "github.com/evrintobing17/loan-billing-system/internal/notification/repository"
+	notificationUsecase "github.com/evrintobing17/loan-billing-system/internal/notification/usecase"
 	paymentHttp "github.com/evrintobing17/loan-billing-system/internal/payment/handler/http"
 	paymentRepo "github.com/evrintobing17/loan-billing-system/internal/payment/repository"
 	paymentUsecase "github.com/evrintobing17/loan-billing-system/internal/payment/usecase"
-	"github.com/evrintobing17/loan-billing-system/pkg/idempotency"
+	productHttp "github.com/evrintobing17/loan-billing-system/internal/product/handler/http"
+	productRepo "github.com/evrintobing17/loan-billing-system/internal/product/repository"
+	productUsecase "github.com/evrintobing17/loan-billing-system/internal/product/usecase"
+	statementHttp "github.com/evrintobing17/loan-billing-system/internal/statement/handler/http"
+	statementMatcher "github.com/evrintobing17/loan-billing-system/internal/statement/matcher"
+	statementRepo "github.com/evrintobing17/loan-billing-system/internal/statement/repository"
+	statementUsecase "github.com/evrintobing17/loan-billing-system/internal/statement/usecase"
+	subscriptionHttp "github.com/evrintobing17/loan-billing-system/internal/subscription/handler/http"
+	subscriptionRepo "github.com/evrintobing17/loan-billing-system/internal/subscription/repository"
+	subscriptionUsecase "github.com/evrintobing17/loan-billing-system/internal/subscription/usecase"
+	topUpHttp "github.com/evrintobing17/loan-billing-system/internal/topup/handler/http"
+	topUpRepo "github.com/evrintobing17/loan-billing-system/internal/topup/repository"
+	topUpUsecase "github.com/evrintobing17/loan-billing-system/internal/topup/usecase"
+	"github.com/evrintobing17/loan-billing-system/internal/webhook"
+	webhookHttp "github.com/evrintobing17/loan-billing-system/internal/webhook/handler/http"
+	webhookProvider "github.com/evrintobing17/loan-billing-system/internal/webhook/provider"
+	webhookRepo "github.com/evrintobing17/loan-billing-system/internal/webhook/repository"
+	webhookUsecase "github.com/evrintobing17/loan-billing-system/internal/webhook/usecase"
+	writeOffHttp "github.com/evrintobing17/loan-billing-system/internal/writeoff/handler/http"
+	writeOffRepo "github.com/evrintobing17/loan-billing-system/internal/writeoff/repository"
+	writeOffUsecase "github.com/evrintobing17/loan-billing-system/internal/writeoff/usecase"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+	"github.com/evrintobing17/loan-billing-system/pkg/directdebit"
+	"github.com/evrintobing17/loan-billing-system/pkg/middleware"
 	redisClient "github.com/evrintobing17/loan-billing-system/pkg/redis"
 	"github.com/joho/godotenv"
 
 	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+	"github.com/evrintobing17/loan-billing-system/pkg/virtualaccount"
 
 	_ "github.com/evrintobing17/loan-billing-system/docs" // swagger docs
 
@@ -32,6 +95,29 @@ func main() {
 	_ = godotenv.Load()
 	cfg := config.Load()
 
+	loc, err := time.LoadLocation(cfg.BusinessTimezone)
+	if err != nil {
+		log.Fatal("Invalid BUSINESS_TIMEZONE:", err)
+	}
+	if !virtualaccount.ValidPrefix(cfg.VirtualAccountPrefix) {
+		log.Fatalf("Invalid VIRTUAL_ACCOUNT_PREFIX: must be 1 to %d digits", virtualaccount.MaxPrefixLength)
+	}
+	clk := clock.New(loc)
+	channels, err := notificationChannel.Build(cfg.NotificationChannels, notificationChannel.Config{
+		SMTPHost:      cfg.SMTPHost,
+		SMTPPort:      cfg.SMTPPort,
+		SMTPUsername:  cfg.SMTPUsername,
+		SMTPPassword:  cfg.SMTPPassword,
+		SMTPFrom:      cfg.SMTPFrom,
+		SMSGatewayURL: cfg.SMSGatewayURL,
+		SMSAPIKey:     cfg.SMSAPIKey,
+		SMSSender:     cfg.SMSSender,
+		LogFile:       cfg.NotificationLogFile,
+	})
+	if err != nil {
+		log.Fatal("Invalid NOTIFICATION_CHANNELS:", err)
+	}
+
 	// PostgreSQL connection
 	db, err := postgres.NewConnection(cfg)
 	if err != nil {
@@ -39,6 +125,17 @@ func main() {
 	}
 	defer db.Close()
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		code := runMigrate(db, os.Args[2:])
+		db.Close()
+		os.Exit(code)
+	}
+	if cfg.MigrateOnStart {
+		if err := migrateOnStart(db); err != nil {
+			log.Fatal("Failed to migrate DB:", err)
+		}
+	}
+
 	// Redis connection
 	rdb, err := redisClient.NewClientWithRetry(cfg.RedisAddr, 5)
 	if err != nil {
@@ -48,31 +145,219 @@ func main() {
 	// Initialize repositories
 	lRepo := loanRepo.NewLoanRepository(db)
 	pRepo := paymentRepo.NewPaymentRepository(db)
-
-	// Idempotency store
-	idempStore := idempotency.NewRedisStore(rdb)
+	prodRepo := productRepo.NewProductRepository(db)
+	dRepo := disbursementRepo.NewDisbursementRepository(db)
+	aRepo := accountingRepo.NewAccountingRepository(db)
+	accRepo := accrualRepo.NewAccrualRepository(db)
+	woRepo := writeOffRepo.NewWriteOffRepository(db)
+	tuRepo := topUpRepo.NewTopUpRepository(db)
+	colRepo := collateralRepo.NewCollateralRepository(db)
+	appRepo := applicationRepo.NewApplicationRepository(db)
+	crRepo := creditRepo.NewCreditRepository(db)
+	bRepo := borrowerRepo.NewBorrowerRepository(db)
+	liRepo := loanImportRepo.NewLoanImportRepository(db)
+	stRepo := statementRepo.NewStatementRepository(db)
+	whRepo := webhookRepo.NewWebhookRepository(db)
+	ddRepo := directDebitRepo.NewDirectDebitRepository(db)
+	nRepo := notificationRepo.NewNotificationRepository(db)
+	evRepo := eventStreamRepo.NewEventRepository(db)
+	subRepo := subscriptionRepo.NewSubscriptionRepository(db)
+	txManager := postgres.NewTransactor(db)
 
 	// Use cases
-	loanUC := loanUsecase.NewLoanUseCase(lRepo)
-	paymentUC := paymentUsecase.NewPaymentUseCase(pRepo, lRepo, idempStore)
+	productUC := productUsecase.NewProductUseCase(prodRepo)
+	accountingUC := accountingUsecase.NewAccountingUseCase(aRepo, clk)
+	loanUC := loanUsecase.NewLoanUseCase(lRepo, prodRepo, bRepo, accountingUC, txManager, clk, models.ExposureLimits{
+		MaxOutstanding:  cfg.BorrowerMaxOutstanding,
+		MaxActiveLoans:  cfg.BorrowerMaxActiveLoans,
+		BlockDelinquent: cfg.BorrowerBlockDelinquent,
+	}, cfg.VirtualAccountPrefix)
+	notificationUC := notificationUsecase.NewNotificationUseCase(nRepo, lRepo, loanUC, clk, channels,
+		cfg.NotificationReminderDays, cfg.NotificationMaxAttempts)
+	paymentUC := paymentUsecase.NewPaymentUseCase(pRepo, lRepo, accountingUC, txManager, clk, notificationUC)
+	disbursementUC := disbursementUsecase.NewDisbursementUseCase(dRepo, lRepo, accountingUC, txManager, clk)
+	accrualUC := accrualUsecase.NewAccrualUseCase(accRepo, lRepo, loanUC, accountingUC, txManager, clk, cfg.NonAccrualDPD)
+	writeOffUC := writeOffUsecase.NewWriteOffUseCase(woRepo, lRepo, loanUC, accountingUC, txManager, clk)
+	topUpUC := topUpUsecase.NewTopUpUseCase(tuRepo, lRepo, loanUC, paymentUC, disbursementUC, txManager, clk)
+	collateralUC := collateralUsecase.NewCollateralUseCase(colRepo, lRepo, loanUC, txManager)
+	creditEngine := creditRules.NewEngine(creditRules.Config{
+		MaxPrincipal:      cfg.CreditMaxPrincipal,
+		MinTermWeeks:      cfg.CreditMinTermWeeks,
+		MaxTermWeeks:      cfg.CreditMaxTermWeeks,
+		MaxExposure:       cfg.CreditMaxExposure,
+		DeclineDelinquent: cfg.CreditDeclineDelinquent,
+		MaxDaysPastDue:    cfg.CreditMaxDaysPastDue,
+		MaxDaysPaidLate:   cfg.CreditMaxDaysPaidLate,
+		DeclineWrittenOff: cfg.CreditDeclineWrittenOff,
+		ReferRestructured: cfg.CreditReferRestructured,
+	})
+	creditUC := creditUsecase.NewCreditUseCase(crRepo, loanUC, creditEngine)
+	applicationUC := applicationUsecase.NewApplicationUseCase(appRepo, loanUC, creditUC, txManager, cfg.ApprovalLimits)
+	borrowerUC := borrowerUsecase.NewBorrowerUseCase(bRepo, loanUC)
+	loanImportUC := loanImportUsecase.NewLoanImportUseCase(liRepo, loanUC, disbursementUC, paymentUC, txManager)
+	statementUC := statementUsecase.NewStatementUseCase(stRepo, lRepo, paymentUC, cfg.LoanCurrency, clk,
+		statementMatcher.NewVirtualAccountMatcher(lRepo),
+		statementMatcher.NewReferenceMatcher(),
+	)
+	webhookUC := webhookUsecase.NewWebhookUseCase(whRepo, paymentUC, clk, map[string]webhook.Provider{
+		"generic": webhookProvider.NewGeneric(),
+		"stripe":  webhookProvider.NewStripe(),
+	}, cfg.WebhookSecrets, cfg.WebhookTolerance, cfg.WebhookMaxBackdateDays, cfg.LoanCurrency)
+	subscriptionUC := subscriptionUsecase.NewSubscriptionUseCase(subRepo, clk, cfg.SubscriptionTimeout,
+		cfg.SubscriptionMaxAttempts, cfg.SubscriptionSecretGrace)
+	eventStreamUC := eventStreamUsecase.NewEventStreamUseCase(evRepo, eventStreamPublisher.NewMulti(
+		eventStreamPublisher.NewRedisStream(rdb, cfg.EventStream, cfg.EventStreamMaxLen),
+		subscriptionUC,
+	), clk)
+	directDebitUC := directDebitUsecase.NewDirectDebitUseCase(ddRepo, lRepo, paymentUC, txManager, clk, directdebit.Creditor{
+		Name:     cfg.CreditorName,
+		Account:  cfg.CreditorAccount,
+		Agent:    cfg.CreditorAgent,
+		SchemeID: cfg.CreditorSchemeID,
+		Currency: cfg.CollectionCurrency,
+	}, models.CollectionPolicy{
+		MaxAttempts:  cfg.CollectionMaxAttempts,
+		RetryDays:    cfg.CollectionRetryDays,
+		SuspendAfter: cfg.CollectionSuspendAfter,
+	})
 
 	// Handlers
 	loanHandler := loanHttp.NewLoanHandler(loanUC)
 	paymentHandler := paymentHttp.NewPaymentHandler(paymentUC)
+	productHandler := productHttp.NewProductHandler(productUC)
+	disbursementHandler := disbursementHttp.NewDisbursementHandler(disbursementUC)
+	accountingHandler := accountingHttp.NewAccountingHandler(accountingUC)
+	accrualHandler := accrualHttp.NewAccrualHandler(accrualUC)
+	writeOffHandler := writeOffHttp.NewWriteOffHandler(writeOffUC)
+	topUpHandler := topUpHttp.NewTopUpHandler(topUpUC)
+	collateralHandler := collateralHttp.NewCollateralHandler(collateralUC)
+	applicationHandler := applicationHttp.NewApplicationHandler(applicationUC)
+	borrowerHandler := borrowerHttp.NewBorrowerHandler(borrowerUC)
+	loanImportHandler := loanImportHttp.NewLoanImportHandler(loanImportUC)
+	statementHandler := statementHttp.NewStatementHandler(statementUC)
+	webhookHandler := webhookHttp.NewWebhookHandler(webhookUC)
+	directDebitHandler := directDebitHttp.NewDirectDebitHandler(directDebitUC)
+	notificationHandler := notificationHttp.NewNotificationHandler(notificationUC)
+	eventStreamHandler := eventStreamHttp.NewEventStreamHandler(eventStreamUC)
+	subscriptionHandler := subscriptionHttp.NewSubscriptionHandler(subscriptionUC)
+
+	// Background workers
+	runEvery(context.Background(), "notification dispatch", cfg.NotificationDispatchInterval, func(ctx context.Context) error {
+		_, err := notificationUC.Dispatch(ctx)
+		return err
+	})
+	runEvery(context.Background(), "event relay", cfg.EventRelayInterval, func(ctx context.Context) error {
+		_, err := eventStreamUC.Relay(ctx)
+		return err
+	})
+	runEvery(context.Background(), "webhook delivery", cfg.SubscriptionDispatchInterval, func(ctx context.Context) error {
+		_, err := subscriptionUC.Dispatch(ctx)
+		return err
+	})
 
 	// Gin engine
 	r := gin.Default()
+	r.Use(middleware.Errors())
 
 	// Swagger
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// API routes
 	v1 := r.Group("/api/v1")
+	v1.Use(middleware.AsOfDate(cfg.AdminAPIKey), middleware.Identify(cfg.UserKeys))
+	admin := middleware.RequireAdmin(cfg.AdminAPIKey)
+	userKey := middleware.RequireUserKey()
 	{
-		v1.POST("/loans", loanHandler.CreateLoan)
+		v1.POST("/loans", admin, loanHandler.CreateLoan)
+		v1.POST("/applications", applicationHandler.Submit)
+		v1.GET("/applications", applicationHandler.ListApplications)
+		v1.GET("/applications/:id", applicationHandler.GetApplication)
+		v1.GET("/applications/:id/credit-decisions", applicationHandler.GetCreditDecisions)
+		v1.POST("/applications/:id/review", admin, applicationHandler.StartReview)
+		v1.POST("/applications/:id/approve", admin, userKey, applicationHandler.Approve)
+		v1.POST("/applications/:id/reject", admin, userKey, applicationHandler.Reject)
+		v1.GET("/borrowers/:borrowerId/exposure", borrowerHandler.GetExposure)
+		v1.GET("/borrowers/:borrowerId/limits", borrowerHandler.GetLimit)
+		v1.PUT("/borrowers/:borrowerId/limits", admin, borrowerHandler.SetLimit)
+		v1.DELETE("/borrowers/:borrowerId/limits", admin, borrowerHandler.DeleteLimit)
+		v1.GET("/borrowers/:borrowerId/contact", admin, notificationHandler.GetContact)
+		v1.PUT("/borrowers/:borrowerId/contact", admin, notificationHandler.SetContact)
+		v1.POST("/notifications/run", admin, notificationHandler.Run)
+		v1.POST("/notifications/dispatch", admin, notificationHandler.Dispatch)
+		v1.GET("/notifications", admin, notificationHandler.List)
+		v1.GET("/notifications/:id", admin, notificationHandler.Get)
+		v1.POST("/notifications/:id/resend", admin, notificationHandler.Resend)
+		v1.GET("/events", admin, eventStreamHandler.ListEvents)
+		v1.POST("/events/relay", admin, eventStreamHandler.Relay)
+		v1.POST("/subscriptions", admin, subscriptionHandler.Create)
+		v1.GET("/subscriptions", admin, subscriptionHandler.List)
+		v1.GET("/subscriptions/:id", admin, subscriptionHandler.Get)
+		v1.PUT("/subscriptions/:id", admin, subscriptionHandler.Update)
+		v1.DELETE("/subscriptions/:id", admin, subscriptionHandler.Deactivate)
+		v1.POST("/subscriptions/:id/rotate-secret", admin, subscriptionHandler.RotateSecret)
+		v1.POST("/webhook-deliveries/dispatch", admin, subscriptionHandler.Dispatch)
+		v1.GET("/webhook-deliveries", admin, subscriptionHandler.ListDeliveries)
+		v1.GET("/webhook-deliveries/:id", admin, subscriptionHandler.GetDelivery)
+		v1.POST("/webhook-deliveries/:id/redeliver", admin, subscriptionHandler.Redeliver)
+		v1.POST("/imports/loans", admin, loanImportHandler.StartImport)
+		v1.GET("/imports/loans/:id", admin, loanImportHandler.GetJob)
+		v1.POST("/loans/quote", loanHandler.QuoteLoan)
+		v1.GET("/loans/:id", loanHandler.GetLoan)
 		v1.GET("/loans/:id/outstanding", loanHandler.GetOutstanding)
 		v1.GET("/loans/:id/delinquent", loanHandler.IsDelinquent)
+		v1.GET("/loans/:id/schedule", loanHandler.GetSchedule)
+		v1.POST("/loans/:id/restructure", admin, loanHandler.RestructureLoan)
+		v1.GET("/loans/:id/restructures", loanHandler.GetRestructures)
+		v1.POST("/loans/:id/top-up", admin, topUpHandler.TopUpLoan)
+		v1.GET("/loans/:id/top-ups", topUpHandler.GetTopUps)
+		v1.POST("/loans/:id/collateral", admin, collateralHandler.AddCollateral)
+		v1.GET("/loans/:id/collateral", collateralHandler.ListCollateral)
+		v1.GET("/loans/:id/collateral/:collateralId", collateralHandler.GetCollateral)
+		v1.PUT("/loans/:id/collateral/:collateralId", admin, collateralHandler.UpdateCollateral)
+		v1.DELETE("/loans/:id/collateral/:collateralId", admin, collateralHandler.DeleteCollateral)
+		v1.POST("/loans/:id/guarantors", admin, collateralHandler.AddGuarantor)
+		v1.GET("/loans/:id/guarantors", collateralHandler.ListGuarantors)
+		v1.GET("/loans/:id/guarantors/:guarantorId", collateralHandler.GetGuarantor)
+		v1.PUT("/loans/:id/guarantors/:guarantorId", admin, collateralHandler.UpdateGuarantor)
+		v1.DELETE("/loans/:id/guarantors/:guarantorId", admin, collateralHandler.DeleteGuarantor)
+		v1.GET("/loans/:id/ltv", collateralHandler.GetLoanToValue)
 		v1.POST("/loans/:id/payments", paymentHandler.MakePayment)
+		v1.GET("/loans/:id/payments", paymentHandler.ListPayments)
+		v1.POST("/payments/:id/reverse", admin, paymentHandler.ReversePayment)
+		v1.POST("/loans/:id/mandates", admin, directDebitHandler.CreateMandate)
+		v1.GET("/loans/:id/mandates", admin, directDebitHandler.ListMandates)
+		v1.POST("/mandates/:id/cancel", admin, directDebitHandler.CancelMandate)
+		v1.POST("/mandates/:id/reactivate", admin, directDebitHandler.ReactivateMandate)
+		v1.GET("/direct-debit/dunning", admin, directDebitHandler.ListDunning)
+		v1.POST("/direct-debit/runs", admin, directDebitHandler.CreateRun)
+		v1.GET("/direct-debit/runs/:id", admin, directDebitHandler.GetRun)
+		v1.GET("/direct-debit/runs/:id/file", admin, directDebitHandler.ExportRun)
+		v1.POST("/direct-debit/runs/:id/results", admin, directDebitHandler.ImportResults)
+		v1.GET("/virtual-accounts/:number", loanHandler.GetByVirtualAccount)
+		v1.POST("/virtual-accounts/:number/payments", paymentHandler.PayVirtualAccount)
+		v1.POST("/loans/:id/disbursements", admin, disbursementHandler.Disburse)
+		v1.GET("/loans/:id/disbursements", disbursementHandler.ListDisbursements)
+		v1.POST("/products", admin, productHandler.CreateProduct)
+		v1.GET("/products", productHandler.ListProducts)
+		v1.GET("/products/:code", productHandler.GetProduct)
+		v1.GET("/loans/:id/journal-entries", admin, accountingHandler.GetLoanEntries)
+		v1.GET("/accounting/trial-balance", admin, accountingHandler.TrialBalance)
+		v1.POST("/accounting/journal-entries/:id/reverse", admin, accountingHandler.ReverseEntry)
+		v1.POST("/statements", admin, statementHandler.ImportStatement)
+		v1.GET("/statements/:id", admin, statementHandler.GetStatement)
+		v1.GET("/reconciliation/queue", admin, statementHandler.ListQueue)
+		v1.POST("/reconciliation/entries/:id/resolve", admin, statementHandler.ResolveEntry)
+		v1.POST("/reconciliation/entries/:id/dismiss", admin, statementHandler.DismissEntry)
+		v1.POST("/webhooks/payments/:provider", webhookHandler.ReceivePayment)
+		v1.GET("/webhooks/events", admin, webhookHandler.ListEvents)
+		v1.GET("/webhooks/events/:id", admin, webhookHandler.GetEvent)
+		v1.POST("/webhooks/events/:id/replay", admin, webhookHandler.ReplayEvent)
+		v1.POST("/accruals/run", admin, accrualHandler.RunAccrual)
+		v1.GET("/loans/:id/accruals", accrualHandler.GetLoanAccruals)
+		v1.POST("/loans/:id/write-off", admin, writeOffHandler.RequestWriteOff)
+		v1.POST("/loans/:id/write-off/approve", admin, userKey, writeOffHandler.ApproveWriteOff)
+		v1.POST("/loans/:id/write-off/reject", admin, userKey, writeOffHandler.RejectWriteOff)
+		v1.GET("/loans/:id/write-offs", writeOffHandler.ListWriteOffs)
 	}
 
 	r.Run(":" + cfg.Port)
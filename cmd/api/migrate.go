@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/evrintobing17/loan-billing-system/migrations"
+	"github.com/evrintobing17/loan-billing-system/pkg/migrate"
+)
+
+const migrateUsage = `usage: billing-api migrate <command>
+
+commands:
+  up              apply all pending migrations
+  down [n]        roll back the last n migrations (default 1)
+  status          list migrations and when they were applied
+  baseline <v>    mark migrations up to version v as applied without running them`
+
+// runMigrate implements the migrate subcommand and returns the exit code.
+func runMigrate(db *sql.DB, args []string) int {
+	m, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		printMigrations("applied", applied)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				fmt.Fprintln(os.Stderr, "down takes a positive number of steps")
+				return 2
+			}
+		}
+		reverted, err := m.Down(ctx, steps)
+		printMigrations("rolled back", reverted)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%03d_%-30s %s\n", s.Version, s.Name, applied)
+		}
+	case "baseline":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "baseline takes a migration version")
+			return 2
+		}
+		if err := m.Baseline(ctx, version); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	return 0
+}
+
+// migrateOnStart applies pending migrations before the API starts serving.
+func migrateOnStart(db *sql.DB) error {
+	m, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+	applied, err := m.Up(context.Background())
+	printMigrations("applied", applied)
+	return err
+}
+
+func printMigrations(verb string, ms []migrate.Migration) {
+	for _, mig := range ms {
+		fmt.Printf("%s %03d_%s\n", verb, mig.Version, mig.Name)
+	}
+}
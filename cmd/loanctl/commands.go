@@ -0,0 +1,412 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+	ddfile "github.com/evrintobing17/loan-billing-system/pkg/directdebit"
+)
+
+type command func(ctx context.Context, svc *services, out *output, args []string) error
+
+var commands = map[string]command{
+	"create-loan":             createLoan,
+	"schedule":                schedule,
+	"payments":                listPayments,
+	"pay":                     pay,
+	"reverse-payment":         reversePayment,
+	"sweep":                   sweep,
+	"history":                 history,
+	"import":                  importLoans,
+	"assign-virtual-accounts": assignVirtualAccounts,
+	"collect":                 collect,
+	"collection-results":      collectionResults,
+	"notify":                  notify,
+	"relay-events":            relayEvents,
+	"deliver-webhooks":        deliverWebhooks,
+}
+
+func createLoan(ctx context.Context, svc *services, out *output, args []string) error {
+	fs := newFlagSet("create-loan")
+	borrower := fs.String("borrower", "", "borrower ID")
+	principal := fs.Float64("principal", 0, "loan principal")
+	rate := fs.Float64("rate", 0, "annual flat interest rate")
+	weeks := fs.Int("weeks", 0, "term in weeks")
+	start := fs.String("start", "", "start date, YYYY-MM-DD (default today)")
+	product := fs.String("product", "", "loan product code")
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	if *principal <= 0 || *rate <= 0 || *weeks <= 0 {
+		return usageError("create-loan needs a positive -principal, -rate and -weeks")
+	}
+
+	terms := models.LoanTerms{
+		BorrowerID:   *borrower,
+		Principal:    *principal,
+		InterestRate: *rate,
+		TermWeeks:    *weeks,
+		StartDate:    clock.Today(ctx, svc.clock),
+		ProductCode:  *product,
+	}
+	if *start != "" {
+		date, err := time.Parse("2006-01-02", *start)
+		if err != nil {
+			return usageError("invalid -start date, use YYYY-MM-DD")
+		}
+		terms.StartDate = date
+	}
+	l, err := svc.loan.CreateLoan(ctx, terms)
+	if err != nil {
+		return err
+	}
+	return out.print(l, func(w io.Writer) { writeLoan(w, l) })
+}
+
+func schedule(ctx context.Context, svc *services, out *output, args []string) error {
+	loanID, rest, err := idArg("schedule", "loan-id", args)
+	if err != nil {
+		return err
+	}
+	fs := newFlagSet("schedule")
+	version := fs.Int("version", 0, "schedule version (default current)")
+	if err := fs.Parse(rest); err != nil {
+		return usageError(err.Error())
+	}
+
+	s, err := svc.loan.GetSchedule(ctx, loanID, *version)
+	if err != nil {
+		return err
+	}
+	return out.print(s, func(w io.Writer) { writeSchedule(w, s) })
+}
+
+func listPayments(ctx context.Context, svc *services, out *output, args []string) error {
+	loanID, _, err := idArg("payments", "loan-id", args)
+	if err != nil {
+		return err
+	}
+	payments, err := svc.payment.ListPayments(ctx, loanID)
+	if err != nil {
+		return err
+	}
+	return out.print(payments, func(w io.Writer) { writePayments(w, payments) })
+}
+
+func pay(ctx context.Context, svc *services, out *output, args []string) error {
+	loanID, rest, err := idArg("pay", "loan-id", args)
+	if err != nil {
+		return err
+	}
+	fs := newFlagSet("pay")
+	amount := fs.Float64("amount", 0, "payment amount")
+	key := fs.String("key", "", "idempotency key")
+	if err := fs.Parse(rest); err != nil {
+		return usageError(err.Error())
+	}
+	if *amount <= 0 || *key == "" {
+		return usageError("pay needs a positive -amount and a -key")
+	}
+
+	// A repeated key succeeds without paying again and shows the original
+	// payment.
+	p, err := svc.payment.MakePayment(ctx, loanID, *amount, *key)
+	if err != nil {
+		return err
+	}
+	return out.print(p, func(w io.Writer) { writePayments(w, []models.Payment{*p}) })
+}
+
+func reversePayment(ctx context.Context, svc *services, out *output, args []string) error {
+	paymentID, rest, err := idArg("reverse-payment", "payment-id", args)
+	if err != nil {
+		return err
+	}
+	fs := newFlagSet("reverse-payment")
+	reason := fs.String("reason", "", "reason for the reversal")
+	if err := fs.Parse(rest); err != nil {
+		return usageError(err.Error())
+	}
+
+	p, err := svc.payment.ReversePayment(ctx, paymentID, *reason)
+	if err != nil {
+		return err
+	}
+	return out.print(p, func(w io.Writer) { writePayments(w, []models.Payment{*p}) })
+}
+
+func sweep(ctx context.Context, svc *services, out *output, args []string) error {
+	fs := newFlagSet("sweep")
+	date := fs.String("date", "", "business date, YYYY-MM-DD (default today)")
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	var businessDate time.Time
+	if *date != "" {
+		var err error
+		if businessDate, err = time.Parse("2006-01-02", *date); err != nil {
+			return usageError("invalid -date, use YYYY-MM-DD")
+		}
+	}
+
+	s, err := svc.loan.SweepDelinquency(ctx, businessDate)
+	if err != nil {
+		return err
+	}
+	return out.print(s, func(w io.Writer) { writeSweep(w, s) })
+}
+
+func assignVirtualAccounts(ctx context.Context, svc *services, out *output, args []string) error {
+	if len(args) > 0 {
+		return usageError("assign-virtual-accounts takes no arguments")
+	}
+	n, err := svc.loan.AssignVirtualAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	return out.print(map[string]int{"assigned": n}, func(w io.Writer) {
+		fmt.Fprintf(w, "Assigned virtual accounts to %d loans\n", n)
+	})
+}
+
+// collect creates the direct debit run of a date and writes its file for the
+// bank. It is meant to run daily from cron; a date that already has a run
+// fails, so a repeated invocation does nothing.
+func collect(ctx context.Context, svc *services, out *output, args []string) error {
+	fs := newFlagSet("collect")
+	date := fs.String("date", "", "collection date, YYYY-MM-DD (default today)")
+	format := fs.String("format", ddfile.FormatCSV, "file format: csv or pain008")
+	path := fs.String("out", "", "collection file to write")
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	if *path == "" {
+		return usageError("collect needs an -out file")
+	}
+	if *format != ddfile.FormatCSV && *format != ddfile.FormatPain008 {
+		return usageError("-format must be csv or pain008")
+	}
+	var collectionDate time.Time
+	if *date != "" {
+		var err error
+		if collectionDate, err = time.Parse("2006-01-02", *date); err != nil {
+			return usageError("invalid -date, use YYYY-MM-DD")
+		}
+	}
+
+	run, err := svc.directDebit.CreateRun(ctx, collectionDate, "loanctl")
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(*path)
+	if err != nil {
+		return err
+	}
+	if err := svc.directDebit.ExportRun(ctx, run.ID, *format, f); err != nil {
+		f.Close()
+		return fmt.Errorf("collection run %d created but not exported: %w", run.ID, err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return out.print(run, func(w io.Writer) { writeCollectionRun(w, run) })
+}
+
+func collectionResults(ctx context.Context, svc *services, out *output, args []string) error {
+	runID, rest, err := idArg("collection-results", "run-id", args)
+	if err != nil {
+		return err
+	}
+	fs := newFlagSet("collection-results")
+	path := fs.String("file", "", "result file from the bank")
+	if err := fs.Parse(rest); err != nil {
+		return usageError(err.Error())
+	}
+	if *path == "" {
+		return usageError("collection-results needs a -file")
+	}
+	f, err := os.Open(*path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	res, err := svc.directDebit.ImportResults(ctx, runID, f)
+	if err != nil {
+		return err
+	}
+	return out.print(res, func(w io.Writer) { writeCollectionResults(w, res) })
+}
+
+// loanHistory is everything recorded about one loan.
+type loanHistory struct {
+	Loan           *models.Loan             `json:"loan"`
+	Schedules      []models.LoanSchedule    `json:"schedules"`
+	Restructures   []models.Restructure     `json:"restructures"`
+	Disbursements  []models.Disbursement    `json:"disbursements"`
+	Payments       []models.Payment         `json:"payments"`
+	Accruals       []models.InterestAccrual `json:"accruals"`
+	WriteOffs      []models.WriteOff        `json:"write_offs"`
+	TopUps         []models.TopUp           `json:"top_ups"`
+	JournalEntries []models.JournalEntry    `json:"journal_entries"`
+}
+
+// history always prints JSON; the export is meant to be archived or diffed.
+func history(ctx context.Context, svc *services, out *output, args []string) error {
+	loanID, _, err := idArg("history", "loan-id", args)
+	if err != nil {
+		return err
+	}
+
+	h := loanHistory{}
+	if h.Loan, err = svc.loan.GetLoan(ctx, loanID); err != nil {
+		return err
+	}
+	for v := 1; v <= h.Loan.ScheduleVersion; v++ {
+		s, err := svc.loan.GetSchedule(ctx, loanID, v)
+		if err != nil {
+			return err
+		}
+		h.Schedules = append(h.Schedules, *s)
+	}
+	if h.Restructures, err = svc.loan.GetRestructures(ctx, loanID); err != nil {
+		return err
+	}
+	if h.Disbursements, err = svc.disbursement.ListDisbursements(ctx, loanID); err != nil {
+		return err
+	}
+	if h.Payments, err = svc.payment.ListPayments(ctx, loanID); err != nil {
+		return err
+	}
+	if h.Accruals, err = svc.accrual.GetLoanAccruals(ctx, loanID); err != nil {
+		return err
+	}
+	if h.WriteOffs, err = svc.writeOff.ListWriteOffs(ctx, loanID); err != nil {
+		return err
+	}
+	if h.TopUps, err = svc.topUp.GetTopUps(ctx, loanID); err != nil {
+		return err
+	}
+	if h.JournalEntries, err = svc.accounting.GetLoanEntries(ctx, loanID); err != nil {
+		return err
+	}
+	return out.writeJSON(h)
+}
+
+// importLoans runs a CSV import to completion. Rows that fail are listed and
+// make the command exit non-zero.
+func importLoans(ctx context.Context, svc *services, out *output, args []string) error {
+	fs := newFlagSet("import")
+	path := fs.String("file", "", "loan CSV file")
+	dryRun := fs.Bool("dry-run", false, "validate and simulate without saving")
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	if *path == "" {
+		return usageError("import needs a -file")
+	}
+	f, err := os.Open(*path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	job, err := svc.loanImport.Import(ctx, f, *dryRun, "loanctl")
+	if err != nil {
+		return err
+	}
+	if err := out.print(job, func(w io.Writer) { writeImportJob(w, job) }); err != nil {
+		return err
+	}
+	if job.Error != "" {
+		return fmt.Errorf("import job %d failed: %s", job.ID, job.Error)
+	}
+	if job.Failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", job.Failed, job.TotalRows)
+	}
+	return nil
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// idArg takes the leading ID argument of a command and returns the rest.
+func idArg(cmd, name string, args []string) (int, []string, error) {
+	if len(args) == 0 {
+		return 0, nil, usageError(fmt.Sprintf("%s needs a %s", cmd, name))
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id <= 0 {
+		return 0, nil, usageError(fmt.Sprintf("invalid %s %q", name, args[0]))
+	}
+	return id, args[1:], nil
+}
+
+// notify queues the business date's reminders and delinquency alerts and
+// sends whatever is pending, for deployments without the API's dispatcher.
+func notify(ctx context.Context, svc *services, out *output, args []string) error {
+	fs := newFlagSet("notify")
+	date := fs.String("date", "", "business date, YYYY-MM-DD (default today)")
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	var businessDate time.Time
+	if *date != "" {
+		var err error
+		if businessDate, err = time.Parse("2006-01-02", *date); err != nil {
+			return usageError("invalid -date, use YYYY-MM-DD")
+		}
+	}
+
+	run, err := svc.notification.Run(ctx, businessDate)
+	if err != nil {
+		return err
+	}
+	dispatch, err := svc.notification.Dispatch(ctx)
+	if err != nil {
+		return err
+	}
+	result := struct {
+		Run      *models.NotificationRun      `json:"run"`
+		Dispatch *models.NotificationDispatch `json:"dispatch"`
+	}{run, dispatch}
+	return out.print(result, func(w io.Writer) { writeNotify(w, run, dispatch) })
+}
+
+// relayEvents publishes the pending domain events, for deployments without
+// the API's relay.
+func relayEvents(ctx context.Context, svc *services, out *output, args []string) error {
+	if len(args) > 0 {
+		return usageError("relay-events takes no arguments")
+	}
+	result, err := svc.eventStream.Relay(ctx)
+	if err != nil {
+		return err
+	}
+	return out.print(result, func(w io.Writer) { fmt.Fprintf(w, "Published %d events\n", result.Published) })
+}
+
+// deliverWebhooks posts the pending partner webhook deliveries, for
+// deployments without the API's dispatcher.
+func deliverWebhooks(ctx context.Context, svc *services, out *output, args []string) error {
+	if len(args) > 0 {
+		return usageError("deliver-webhooks takes no arguments")
+	}
+	result, err := svc.subscription.Dispatch(ctx)
+	if err != nil {
+		return err
+	}
+	return out.print(result, func(w io.Writer) {
+		fmt.Fprintf(w, "Webhooks: %d delivered, %d to retry, %d dead\n", result.Delivered, result.Retrying, result.Dead)
+	})
+}
@@ -0,0 +1,131 @@
+// Command loanctl runs loan operations straight against the database, using
+// the same usecases and configuration as the API.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/config"
+	notificationChannel "github.com/evrintobing17/loan-billing-system/internal/notification/channel"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+	"github.com/evrintobing17/loan-billing-system/pkg/virtualaccount"
+	"github.com/joho/godotenv"
+)
+
+const usage = `usage: loanctl [-o table|json] [-as-of YYYY-MM-DD] <command> [args]
+
+commands:
+  create-loan -borrower ID -principal N -rate R -weeks N [-start YYYY-MM-DD] [-product CODE]
+                                          create a loan
+  schedule <loan-id> [-version N]         print the current or a past schedule
+  payments <loan-id>                      list a loan's payments
+  pay <loan-id> -amount N -key KEY        post a payment
+  reverse-payment <payment-id> -reason R  reverse a payment
+  sweep [-date YYYY-MM-DD]                classify every active loan by delinquency
+  history <loan-id>                       export a loan's full history as JSON
+  import -file F [-dry-run]               import legacy loans and payments from CSV
+  assign-virtual-accounts                 number loans booked before virtual accounts
+  collect -out F [-date YYYY-MM-DD] [-format csv|pain008]
+                                          create the day's direct debit run and write its file
+  collection-results <run-id> -file F     apply the bank's direct debit result file
+  notify [-date YYYY-MM-DD]               queue reminders and delinquency alerts and send pending notifications
+  relay-events                            publish pending domain events to the event stream and partner webhooks
+  deliver-webhooks                        post pending partner webhook deliveries
+
+The database and business timezone are read from the same environment as
+the API. -as-of sets the business date, like the API's X-As-Of-Date header.`
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run parses the global flags, dispatches the command and returns the exit code.
+func run(args []string) int {
+	global := flag.NewFlagSet("loanctl", flag.ContinueOnError)
+	global.Usage = func() { fmt.Fprintln(os.Stderr, usage) }
+	format := global.String("o", "table", "output format: table or json")
+	asOf := global.String("as-of", "", "business date, YYYY-MM-DD")
+	if err := global.Parse(args); err != nil {
+		return 2
+	}
+	if *format != "table" && *format != "json" {
+		fmt.Fprintln(os.Stderr, "output format must be table or json")
+		return 2
+	}
+	if global.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	cmd, ok := commands[global.Arg(0)]
+	if !ok {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	ctx := context.Background()
+	if *asOf != "" {
+		date, err := time.Parse("2006-01-02", *asOf)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "invalid -as-of date, use YYYY-MM-DD")
+			return 2
+		}
+		ctx = clock.WithAsOfDate(ctx, date)
+	}
+
+	_ = godotenv.Load()
+	cfg := config.Load()
+	loc, err := time.LoadLocation(cfg.BusinessTimezone)
+	if err != nil {
+		log.Println("Invalid BUSINESS_TIMEZONE:", err)
+		return 1
+	}
+	if !virtualaccount.ValidPrefix(cfg.VirtualAccountPrefix) {
+		log.Printf("Invalid VIRTUAL_ACCOUNT_PREFIX: must be 1 to %d digits", virtualaccount.MaxPrefixLength)
+		return 1
+	}
+	channels, err := notificationChannel.Build(cfg.NotificationChannels, notificationChannel.Config{
+		SMTPHost:      cfg.SMTPHost,
+		SMTPPort:      cfg.SMTPPort,
+		SMTPUsername:  cfg.SMTPUsername,
+		SMTPPassword:  cfg.SMTPPassword,
+		SMTPFrom:      cfg.SMTPFrom,
+		SMSGatewayURL: cfg.SMSGatewayURL,
+		SMSAPIKey:     cfg.SMSAPIKey,
+		SMSSender:     cfg.SMSSender,
+		LogFile:       cfg.NotificationLogFile,
+	})
+	if err != nil {
+		log.Println("Invalid NOTIFICATION_CHANNELS:", err)
+		return 1
+	}
+	db, err := postgres.NewConnection(cfg)
+	if err != nil {
+		log.Println("Failed to connect to DB:", err)
+		return 1
+	}
+	defer db.Close()
+
+	svc := newServices(cfg, db, clock.New(loc), channels)
+	defer svc.close()
+
+	out := &output{json: *format == "json", w: os.Stdout}
+	if err := cmd(ctx, svc, out, global.Args()[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if _, ok := err.(usageError); ok {
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+// usageError reports bad command-line arguments.
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// output prints command results as an aligned table or as JSON.
+type output struct {
+	json bool
+	w    io.Writer
+}
+
+func (o *output) print(v any, table func(w io.Writer)) error {
+	if o.json {
+		return o.writeJSON(v)
+	}
+	tw := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+func (o *output) writeJSON(v any) error {
+	enc := json.NewEncoder(o.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeLoan(w io.Writer, l *models.Loan) {
+	fmt.Fprintf(w, "ID\t%d\n", l.ID)
+	fmt.Fprintf(w, "Borrower\t%s\n", l.BorrowerID)
+	fmt.Fprintf(w, "Status\t%s\n", l.Status)
+	fmt.Fprintf(w, "Virtual account\t%s\n", l.VirtualAccount)
+	fmt.Fprintf(w, "Principal\t%.2f\n", l.Principal)
+	fmt.Fprintf(w, "Interest rate\t%.2f\n", l.InterestRate)
+	fmt.Fprintf(w, "Term (weeks)\t%d\n", l.TermWeeks)
+	fmt.Fprintf(w, "Weekly amount\t%.2f\n", l.WeeklyAmount)
+	fmt.Fprintf(w, "Start date\t%s\n", l.StartDate.Format("2006-01-02"))
+	fmt.Fprintf(w, "APR\t%.2f\n", l.APR)
+	fmt.Fprintf(w, "Net disbursement\t%.2f\n", l.NetDisbursement)
+}
+
+func writeSchedule(w io.Writer, s *models.LoanSchedule) {
+	fmt.Fprintf(w, "Loan %d, schedule version %d\n", s.LoanID, s.Version)
+	fmt.Fprintln(w, "WEEK\tDUE\tAMOUNT\tPRINCIPAL\tINTEREST\tFEES\tSTATUS")
+	for _, inst := range s.Installments {
+		fmt.Fprintf(w, "%d\t%s\t%.2f\t%.2f\t%.2f\t%.2f\t%s\n", inst.WeekNumber, inst.DueDate.Format("2006-01-02"),
+			inst.Amount, inst.PrincipalAmount, inst.InterestAmount, inst.FeeAmount, itemStatus(inst.Paid, inst.Closed))
+	}
+	if len(s.Charges) == 0 {
+		return
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "CHARGE\tDUE\tAMOUNT\tSTATUS")
+	for _, charge := range s.Charges {
+		fmt.Fprintf(w, "%s\t%s\t%.2f\t%s\n", charge.FeeType, charge.DueDate.Format("2006-01-02"),
+			charge.Amount, itemStatus(charge.Paid, charge.Closed))
+	}
+}
+
+func itemStatus(paid, closed bool) string {
+	switch {
+	case paid:
+		return "paid"
+	case closed:
+		return "closed"
+	default:
+		return "open"
+	}
+}
+
+func writePayments(w io.Writer, payments []models.Payment) {
+	fmt.Fprintln(w, "ID\tLOAN\tDATE\tTYPE\tAMOUNT\tREVERSED")
+	for _, p := range payments {
+		reversed := "-"
+		if p.ReversedAt != nil {
+			reversed = p.ReversedAt.Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%.2f\t%s\n", p.ID, p.LoanID, p.PaymentDate.Format("2006-01-02"),
+			p.PaymentType, p.Amount, reversed)
+	}
+}
+
+func writeSweep(w io.Writer, s *models.DelinquencySweep) {
+	fmt.Fprintf(w, "Business date %s: %d loans, %d current, %d past due, %d delinquent\n",
+		s.BusinessDate.Format("2006-01-02"), s.LoansProcessed, s.Current, s.PastDue, s.Delinquent)
+	fmt.Fprintln(w, "LOAN\tBORROWER\tCLASSIFICATION\tDPD\tRESTRUCTURED")
+	for _, l := range s.Loans {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%t\n", l.LoanID, l.BorrowerID, l.Classification, l.DaysPastDue, l.Restructured)
+	}
+	for _, e := range s.Errors {
+		fmt.Fprintf(w, "error: %s\n", e)
+	}
+}
+
+func writeImportJob(w io.Writer, job *models.LoanImportJob) {
+	mode := ""
+	if job.DryRun {
+		mode = " (dry run)"
+	}
+	fmt.Fprintf(w, "Import job %d%s: %s, %d rows, %d succeeded, %d failed\n",
+		job.ID, mode, job.Status, job.TotalRows, job.Succeeded, job.Failed)
+	fmt.Fprintln(w, "LINE\tREF\tSTATUS\tLOAN\tPAYMENTS\tERROR")
+	for _, row := range job.Rows {
+		loanID := "-"
+		if row.LoanID != nil {
+			loanID = strconv.Itoa(*row.LoanID)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\n", row.Line, row.Ref, row.Status, loanID, row.PaymentsReplayed, row.Error)
+	}
+}
+
+func writeCollectionRun(w io.Writer, run *models.CollectionRun) {
+	fmt.Fprintf(w, "Collection run %d for %s: %s, %d instructions, %.2f total\n",
+		run.ID, run.CollectionDate.Format("2006-01-02"), run.Status, run.Instructions, run.TotalAmount)
+	fmt.Fprintln(w, "END-TO-END ID\tLOAN\tMANDATE\tATTEMPT\tAMOUNT\tSTATUS")
+	for _, in := range run.Items {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.2f\t%s\n", in.EndToEndID, in.LoanID, in.MandateID, in.Attempt, in.Amount, in.Status)
+	}
+}
+
+func writeCollectionResults(w io.Writer, res *models.CollectionResults) {
+	fmt.Fprintf(w, "Collection run %d: %d collected, %d failed (%d to retry), %d unapplied, %d pending\n",
+		res.RunID, res.Collected, res.Failed, res.RetryScheduled, res.Unapplied, res.Pending)
+	for _, id := range res.Unknown {
+		fmt.Fprintf(w, "unknown: %s\n", id)
+	}
+}
+
+func writeNotify(w io.Writer, run *models.NotificationRun, dispatch *models.NotificationDispatch) {
+	fmt.Fprintf(w, "Notifications for %s: %d reminders and %d alerts queued\n",
+		run.BusinessDate.Format("2006-01-02"), run.Reminders, run.Alerts)
+	fmt.Fprintf(w, "Dispatched: %d sent, %d to retry, %d failed\n", dispatch.Sent, dispatch.Retrying, dispatch.Failed)
+	for _, e := range run.Errors {
+		fmt.Fprintf(w, "error: %s\n", e)
+	}
+}
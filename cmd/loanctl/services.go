@@ -0,0 +1,128 @@
+package main
+
+import (
+	"database/sql"
+
+	"github.com/evrintobing17/loan-billing-system/config"
+	"github.com/evrintobing17/loan-billing-system/internal/accounting"
+	accountingRepo "github.com/evrintobing17/loan-billing-system/internal/accounting/repository"
+	accountingUsecase "github.com/evrintobing17/loan-billing-system/internal/accounting/usecase"
+	"github.com/evrintobing17/loan-billing-system/internal/accrual"
+	accrualRepo "github.com/evrintobing17/loan-billing-system/internal/accrual/repository"
+	accrualUsecase "github.com/evrintobing17/loan-billing-system/internal/accrual/usecase"
+	borrowerRepo "github.com/evrintobing17/loan-billing-system/internal/borrower/repository"
+	"github.com/evrintobing17/loan-billing-system/internal/directdebit"
+	directDebitRepo "github.com/evrintobing17/loan-billing-system/internal/directdebit/repository"
+	directDebitUsecase "github.com/evrintobing17/loan-billing-system/internal/directdebit/usecase"
+	"github.com/evrintobing17/loan-billing-system/internal/disbursement"
+	disbursementRepo "github.com/evrintobing17/loan-billing-system/internal/disbursement/repository"
+	disbursementUsecase "github.com/evrintobing17/loan-billing-system/internal/disbursement/usecase"
+	"github.com/evrintobing17/loan-billing-system/internal/eventstream"
+	eventStreamPublisher "github.com/evrintobing17/loan-billing-system/internal/eventstream/publisher"
+	eventStreamRepo "github.com/evrintobing17/loan-billing-system/internal/eventstream/repository"
+	eventStreamUsecase "github.com/evrintobing17/loan-billing-system/internal/eventstream/usecase"
+	"github.com/evrintobing17/loan-billing-system/internal/loan"
+	loanRepo "github.com/evrintobing17/loan-billing-system/internal/loan/repository"
+	loanUsecase "github.com/evrintobing17/loan-billing-system/internal/loan/usecase"
+	"github.com/evrintobing17/loan-billing-system/internal/loanimport"
+	loanImportRepo "github.com/evrintobing17/loan-billing-system/internal/loanimport/repository"
+	loanImportUsecase "github.com/evrintobing17/loan-billing-system/internal/loanimport/usecase"
+	"github.com/evrintobing17/loan-billing-system/internal/notification"
+	notificationRepo "github.com/evrintobing17/loan-billing-system/internal/notification/repository"
+	notificationUsecase "github.com/evrintobing17/loan-billing-system/internal/notification/usecase"
+	"github.com/evrintobing17/loan-billing-system/internal/payment"
+	paymentRepo "github.com/evrintobing17/loan-billing-system/internal/payment/repository"
+	paymentUsecase "github.com/evrintobing17/loan-billing-system/internal/payment/usecase"
+	productRepo "github.com/evrintobing17/loan-billing-system/internal/product/repository"
+	"github.com/evrintobing17/loan-billing-system/internal/subscription"
+	subscriptionRepo "github.com/evrintobing17/loan-billing-system/internal/subscription/repository"
+	subscriptionUsecase "github.com/evrintobing17/loan-billing-system/internal/subscription/usecase"
+	"github.com/evrintobing17/loan-billing-system/internal/topup"
+	topUpRepo "github.com/evrintobing17/loan-billing-system/internal/topup/repository"
+	topUpUsecase "github.com/evrintobing17/loan-billing-system/internal/topup/usecase"
+	"github.com/evrintobing17/loan-billing-system/internal/writeoff"
+	writeOffRepo "github.com/evrintobing17/loan-billing-system/internal/writeoff/repository"
+	writeOffUsecase "github.com/evrintobing17/loan-billing-system/internal/writeoff/usecase"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+	ddfile "github.com/evrintobing17/loan-billing-system/pkg/directdebit"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+	"github.com/go-redis/redis/v8"
+)
+
+// services holds the usecases the commands run, wired as in cmd/api.
+type services struct {
+	loan         loan.LoanUsecase
+	payment      payment.PaymentUsecase
+	accounting   accounting.AccountingUsecase
+	disbursement disbursement.DisbursementUsecase
+	accrual      accrual.AccrualUsecase
+	writeOff     writeoff.WriteOffUsecase
+	topUp        topup.TopUpUsecase
+	loanImport   loanimport.LoanImportUsecase
+	directDebit  directdebit.DirectDebitUsecase
+	notification notification.NotificationUsecase
+	eventStream  eventstream.EventStreamUsecase
+	subscription subscription.SubscriptionUsecase
+	clock        clock.Clock
+	rdb          *redis.Client
+}
+
+// newServices wires the usecases. Redis is only dialled when a payment is
+// posted, so read-only commands work without it.
+func newServices(cfg *config.Config, db *sql.DB, clk clock.Clock, channels map[string]notification.Channel) *services {
+	lRepo := loanRepo.NewLoanRepository(db)
+	pRepo := paymentRepo.NewPaymentRepository(db)
+	txManager := postgres.NewTransactor(db)
+	rdb := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
+
+	accountingUC := accountingUsecase.NewAccountingUseCase(accountingRepo.NewAccountingRepository(db), clk)
+	loanUC := loanUsecase.NewLoanUseCase(lRepo, productRepo.NewProductRepository(db), borrowerRepo.NewBorrowerRepository(db),
+		accountingUC, txManager, clk, models.ExposureLimits{
+			MaxOutstanding:  cfg.BorrowerMaxOutstanding,
+			MaxActiveLoans:  cfg.BorrowerMaxActiveLoans,
+			BlockDelinquent: cfg.BorrowerBlockDelinquent,
+		}, cfg.VirtualAccountPrefix)
+	notificationUC := notificationUsecase.NewNotificationUseCase(notificationRepo.NewNotificationRepository(db), lRepo, loanUC,
+		clk, channels, cfg.NotificationReminderDays, cfg.NotificationMaxAttempts)
+	paymentUC := paymentUsecase.NewPaymentUseCase(pRepo, lRepo, accountingUC, txManager, clk,
+		notificationUC)
+	subscriptionUC := subscriptionUsecase.NewSubscriptionUseCase(subscriptionRepo.NewSubscriptionRepository(db), clk,
+		cfg.SubscriptionTimeout, cfg.SubscriptionMaxAttempts, cfg.SubscriptionSecretGrace)
+	disbursementUC := disbursementUsecase.NewDisbursementUseCase(disbursementRepo.NewDisbursementRepository(db), lRepo, accountingUC, txManager, clk)
+
+	return &services{
+		loan:         loanUC,
+		payment:      paymentUC,
+		accounting:   accountingUC,
+		disbursement: disbursementUC,
+		accrual:      accrualUsecase.NewAccrualUseCase(accrualRepo.NewAccrualRepository(db), lRepo, loanUC, accountingUC, txManager, clk, cfg.NonAccrualDPD),
+		writeOff:     writeOffUsecase.NewWriteOffUseCase(writeOffRepo.NewWriteOffRepository(db), lRepo, loanUC, accountingUC, txManager, clk),
+		topUp:        topUpUsecase.NewTopUpUseCase(topUpRepo.NewTopUpRepository(db), lRepo, loanUC, paymentUC, disbursementUC, txManager, clk),
+		loanImport:   loanImportUsecase.NewLoanImportUseCase(loanImportRepo.NewLoanImportRepository(db), loanUC, disbursementUC, paymentUC, txManager),
+		directDebit: directDebitUsecase.NewDirectDebitUseCase(directDebitRepo.NewDirectDebitRepository(db), lRepo, paymentUC,
+			txManager, clk, ddfile.Creditor{
+				Name:     cfg.CreditorName,
+				Account:  cfg.CreditorAccount,
+				Agent:    cfg.CreditorAgent,
+				SchemeID: cfg.CreditorSchemeID,
+				Currency: cfg.CollectionCurrency,
+			}, models.CollectionPolicy{
+				MaxAttempts:  cfg.CollectionMaxAttempts,
+				RetryDays:    cfg.CollectionRetryDays,
+				SuspendAfter: cfg.CollectionSuspendAfter,
+			}),
+		notification: notificationUC,
+		eventStream: eventStreamUsecase.NewEventStreamUseCase(eventStreamRepo.NewEventRepository(db), eventStreamPublisher.NewMulti(
+			eventStreamPublisher.NewRedisStream(rdb, cfg.EventStream, cfg.EventStreamMaxLen),
+			subscriptionUC,
+		), clk),
+		subscription: subscriptionUC,
+		clock:        clk,
+		rdb:          rdb,
+	}
+}
+
+func (s *services) close() {
+	s.rdb.Close()
+}
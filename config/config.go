@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
@@ -13,6 +15,117 @@ type Config struct {
 	DBName     string
 	RedisAddr  string
 	Port       string
+	// MigrateOnStart applies pending schema migrations when the API starts.
+	MigrateOnStart bool
+
+	// BusinessTimezone is the IANA zone used to decide the current business
+	// date for due dates and delinquency.
+	BusinessTimezone string
+	// LoanCurrency is the ISO 4217 code all loans are held and repaid in.
+	// Money received in another currency is left for manual review.
+	LoanCurrency string
+	// AdminAPIKey unlocks admin-only features such as the X-As-Of-Date
+	// override. Leave empty to disable them.
+	AdminAPIKey string
+	// NonAccrualDPD is the days-past-due threshold beyond which a loan stops
+	// accruing interest.
+	NonAccrualDPD int
+	// ApprovalLimits is the largest principal each role may approve on a loan
+	// application. Roles not listed cannot approve.
+	ApprovalLimits map[string]float64
+	// UserKeys maps each user to "role:key". When set, the acting user and
+	// their role are taken from the X-User-Key header instead of the
+	// X-User-ID and X-User-Role headers. Without it, application and
+	// write-off decisions are refused.
+	UserKeys map[string]string
+
+	// Thresholds of the built-in credit rules engine; zero disables a rule.
+	CreditMaxPrincipal      float64
+	CreditMinTermWeeks      int
+	CreditMaxTermWeeks      int
+	CreditMaxExposure       float64
+	CreditDeclineDelinquent bool
+	CreditMaxDaysPastDue    int
+	CreditMaxDaysPaidLate   int
+	CreditDeclineWrittenOff bool
+	CreditReferRestructured bool
+
+	// Default exposure limits for borrowers without limits of their own;
+	// zero amounts mean no limit.
+	BorrowerMaxOutstanding  float64
+	BorrowerMaxActiveLoans  int
+	BorrowerBlockDelinquent bool
+
+	// VirtualAccountPrefix starts every loan's virtual account number,
+	// usually the bank's company code for virtual accounts.
+	VirtualAccountPrefix string
+
+	// WebhookSecrets is the signing secret of each payment provider allowed
+	// to post webhooks; providers without one are rejected.
+	WebhookSecrets map[string]string
+	// WebhookTolerance is how far a webhook's signed timestamp may be from
+	// now before the request is rejected as a replay.
+	WebhookTolerance time.Duration
+	// WebhookMaxBackdateDays is how many days before today a webhook
+	// payment may be made as of; older ones are not paid.
+	WebhookMaxBackdateDays int
+
+	// Direct debit collection: how often a failed collection is tried, the
+	// days between tries, and the dunning level that suspends a mandate.
+	CollectionMaxAttempts  int
+	CollectionRetryDays    int
+	CollectionSuspendAfter int
+	// Creditor details written into collection files for the bank.
+	CollectionCurrency string
+	CreditorName       string
+	CreditorAccount    string
+	CreditorAgent      string
+	CreditorSchemeID   string
+
+	// NotificationChannels names the channels borrowers are notified on:
+	// email, sms and log.
+	NotificationChannels []string
+	// NotificationReminderDays is how many days ahead of an installment's due
+	// date its reminder may be sent.
+	NotificationReminderDays int
+	// NotificationMaxAttempts is how often a notification is tried before it
+	// is given up as failed.
+	NotificationMaxAttempts int
+	// NotificationDispatchInterval is how often the API sends pending
+	// notifications; zero disables the background dispatcher.
+	NotificationDispatchInterval time.Duration
+	// NotificationLogFile receives the notifications of the log channel;
+	// empty means standard output.
+	NotificationLogFile string
+	SMTPHost            string
+	SMTPPort            int
+	SMTPUsername        string
+	SMTPPassword        string
+	SMTPFrom            string
+	SMSGatewayURL       string
+	SMSAPIKey           string
+	SMSSender           string
+
+	// EventStream is the Redis stream domain events are published to, trimmed
+	// to about EventStreamMaxLen entries (zero keeps them all).
+	EventStream       string
+	EventStreamMaxLen int64
+	// EventRelayInterval is how often the API publishes the outbox; zero
+	// disables the background relay.
+	EventRelayInterval time.Duration
+
+	// SubscriptionTimeout is how long a partner endpoint gets to answer a
+	// delivery.
+	SubscriptionTimeout time.Duration
+	// SubscriptionMaxAttempts is how often a delivery is tried before it goes
+	// to the dead-letter list.
+	SubscriptionMaxAttempts int
+	// SubscriptionSecretGrace is how long a rotated secret keeps signing
+	// deliveries alongside the new one.
+	SubscriptionSecretGrace time.Duration
+	// SubscriptionDispatchInterval is how often the API posts pending
+	// deliveries; zero disables the background dispatcher.
+	SubscriptionDispatchInterval time.Duration
 }
 
 func Load() *Config {
@@ -24,6 +137,67 @@ func Load() *Config {
 		DBName:     getEnv("DB_NAME", "postgres"),
 		RedisAddr:  getEnv("REDIS_ADDR", "localhost:6379"),
 		Port:       getEnv("PORT", "8080"),
+
+		MigrateOnStart: getEnvAsBool("MIGRATE_ON_START", true),
+
+		BusinessTimezone: getEnv("BUSINESS_TIMEZONE", "UTC"),
+		LoanCurrency:     strings.ToUpper(getEnv("LOAN_CURRENCY", "IDR")),
+		AdminAPIKey:      getEnv("ADMIN_API_KEY", ""),
+		NonAccrualDPD:    getEnvAsInt("NON_ACCRUAL_DPD", 90),
+		ApprovalLimits:   getEnvAsLimits("APPROVAL_LIMITS", "officer:10000000,manager:50000000,director:500000000"),
+		UserKeys:         getEnvAsMap("USER_KEYS", ""),
+
+		CreditMaxPrincipal:      getEnvAsFloat("CREDIT_MAX_PRINCIPAL", 500000000),
+		CreditMinTermWeeks:      getEnvAsInt("CREDIT_MIN_TERM_WEEKS", 4),
+		CreditMaxTermWeeks:      getEnvAsInt("CREDIT_MAX_TERM_WEEKS", 156),
+		CreditMaxExposure:       getEnvAsFloat("CREDIT_MAX_EXPOSURE", 750000000),
+		CreditDeclineDelinquent: getEnvAsBool("CREDIT_DECLINE_DELINQUENT", true),
+		CreditMaxDaysPastDue:    getEnvAsInt("CREDIT_MAX_DAYS_PAST_DUE", 30),
+		CreditMaxDaysPaidLate:   getEnvAsInt("CREDIT_MAX_DAYS_PAID_LATE", 30),
+		CreditDeclineWrittenOff: getEnvAsBool("CREDIT_DECLINE_WRITTEN_OFF", true),
+		CreditReferRestructured: getEnvAsBool("CREDIT_REFER_RESTRUCTURED", true),
+
+		BorrowerMaxOutstanding:  getEnvAsFloat("BORROWER_MAX_OUTSTANDING", 1000000000),
+		BorrowerMaxActiveLoans:  getEnvAsInt("BORROWER_MAX_ACTIVE_LOANS", 3),
+		BorrowerBlockDelinquent: getEnvAsBool("BORROWER_BLOCK_DELINQUENT", true),
+
+		VirtualAccountPrefix: getEnv("VIRTUAL_ACCOUNT_PREFIX", "8808"),
+
+		WebhookSecrets:         getEnvAsMap("WEBHOOK_SECRETS", ""),
+		WebhookTolerance:       time.Duration(getEnvAsInt("WEBHOOK_TOLERANCE_SECONDS", 300)) * time.Second,
+		WebhookMaxBackdateDays: getEnvAsInt("WEBHOOK_MAX_BACKDATE_DAYS", 7),
+
+		CollectionMaxAttempts:  getEnvAsInt("COLLECTION_MAX_ATTEMPTS", 3),
+		CollectionRetryDays:    getEnvAsInt("COLLECTION_RETRY_DAYS", 3),
+		CollectionSuspendAfter: getEnvAsInt("COLLECTION_SUSPEND_AFTER", 2),
+		CollectionCurrency:     getEnv("COLLECTION_CURRENCY", "IDR"),
+		CreditorName:           getEnv("CREDITOR_NAME", ""),
+		CreditorAccount:        getEnv("CREDITOR_ACCOUNT", ""),
+		CreditorAgent:          getEnv("CREDITOR_AGENT", ""),
+		CreditorSchemeID:       getEnv("CREDITOR_SCHEME_ID", ""),
+
+		NotificationChannels:         getEnvAsList("NOTIFICATION_CHANNELS", "log"),
+		NotificationReminderDays:     getEnvAsInt("NOTIFICATION_REMINDER_DAYS", 3),
+		NotificationMaxAttempts:      getEnvAsInt("NOTIFICATION_MAX_ATTEMPTS", 5),
+		NotificationDispatchInterval: time.Duration(getEnvAsInt("NOTIFICATION_DISPATCH_INTERVAL_SECONDS", 30)) * time.Second,
+		NotificationLogFile:          getEnv("NOTIFICATION_LOG_FILE", ""),
+		SMTPHost:                     getEnv("SMTP_HOST", ""),
+		SMTPPort:                     getEnvAsInt("SMTP_PORT", 587),
+		SMTPUsername:                 getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:                 getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:                     getEnv("SMTP_FROM", ""),
+		SMSGatewayURL:                getEnv("SMS_GATEWAY_URL", ""),
+		SMSAPIKey:                    getEnv("SMS_API_KEY", ""),
+		SMSSender:                    getEnv("SMS_SENDER", ""),
+
+		EventStream:        getEnv("EVENT_STREAM", "loan-billing:events"),
+		EventStreamMaxLen:  int64(getEnvAsInt("EVENT_STREAM_MAX_LEN", 100000)),
+		EventRelayInterval: time.Duration(getEnvAsInt("EVENT_RELAY_INTERVAL_SECONDS", 2)) * time.Second,
+
+		SubscriptionTimeout:          time.Duration(getEnvAsInt("SUBSCRIPTION_TIMEOUT_SECONDS", 10)) * time.Second,
+		SubscriptionMaxAttempts:      getEnvAsInt("SUBSCRIPTION_MAX_ATTEMPTS", 10),
+		SubscriptionSecretGrace:      time.Duration(getEnvAsInt("SUBSCRIPTION_SECRET_GRACE_HOURS", 24)) * time.Hour,
+		SubscriptionDispatchInterval: time.Duration(getEnvAsInt("SUBSCRIPTION_DISPATCH_INTERVAL_SECONDS", 5)) * time.Second,
 	}
 }
 
@@ -42,3 +216,61 @@ func getEnvAsInt(key string, fallback int) int {
 	}
 	return fallback
 }
+
+func getEnvAsFloat(key string, fallback float64) float64 {
+	if val := os.Getenv(key); val != "" {
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			return f
+		}
+	}
+	return fallback
+}
+
+func getEnvAsBool(key string, fallback bool) bool {
+	if val := os.Getenv(key); val != "" {
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	}
+	return fallback
+}
+
+// getEnvAsLimits parses "role:amount" pairs separated by commas. Malformed
+// pairs are skipped.
+func getEnvAsLimits(key, fallback string) map[string]float64 {
+	limits := make(map[string]float64)
+	for _, pair := range strings.Split(getEnv(key, fallback), ",") {
+		role, amount, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			continue
+		}
+		if f, err := strconv.ParseFloat(amount, 64); err == nil {
+			limits[strings.TrimSpace(role)] = f
+		}
+	}
+	return limits
+}
+
+// getEnvAsMap parses "key:value" pairs separated by commas. The value runs to
+// the next comma, so it may itself contain colons.
+func getEnvAsMap(key, fallback string) map[string]string {
+	m := make(map[string]string)
+	for _, pair := range strings.Split(getEnv(key, fallback), ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if ok && k != "" && v != "" {
+			m[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return m
+}
+
+// getEnvAsList parses a comma separated list, dropping empty items.
+func getEnvAsList(key, fallback string) []string {
+	var list []string
+	for _, item := range strings.Split(getEnv(key, fallback), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
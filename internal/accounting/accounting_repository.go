@@ -0,0 +1,16 @@
+package accounting
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type AccountingRepository interface {
+	CreateEntry(ctx context.Context, entry *models.JournalEntry) error
+	GetEntry(ctx context.Context, id int) (*models.JournalEntry, error)
+	GetEntriesByLoanID(ctx context.Context, loanID int) ([]models.JournalEntry, error)
+	TrialBalance(ctx context.Context) ([]models.TrialBalanceRow, error)
+	// LoanAccountBalance returns debits minus credits posted to an account for one loan.
+	LoanAccountBalance(ctx context.Context, loanID int, accountCode string) (float64, error)
+}
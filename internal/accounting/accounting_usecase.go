@@ -0,0 +1,30 @@
+package accounting
+
+import (
+	"context"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// AccountingUsecase posts balanced journal entries for loan and payment
+// events. The Record* methods are meant to be called inside the caller's
+// transaction so the entry commits together with the event.
+type AccountingUsecase interface {
+	RecordLoanBooked(ctx context.Context, loan *models.Loan) error
+	RecordDisbursement(ctx context.Context, disbursement *models.Disbursement) error
+	RecordPayment(ctx context.Context, payment *models.Payment, split models.PaymentSplit) error
+	RecordInterestAccrual(ctx context.Context, loanID int, date time.Time, amount float64) error
+	// RecordWriteOff returns the receivables charged to loan loss expense.
+	RecordWriteOff(ctx context.Context, loanID int, date time.Time) (float64, error)
+	RecordRecovery(ctx context.Context, payment *models.Payment) error
+	RecordRestructure(ctx context.Context, restructure *models.Restructure, capitalisedInterest, capitalisedFees float64) error
+	// RecognisedInterest returns the interest income booked so far for a loan,
+	// whether accrued or taken straight to income on payment.
+	RecognisedInterest(ctx context.Context, loanID int) (float64, error)
+	ReverseEntry(ctx context.Context, entryID int, reason string) (*models.JournalEntry, error)
+	// ReversePayment reverses the entry booked for a payment or recovery.
+	ReversePayment(ctx context.Context, payment *models.Payment, reason string) (*models.JournalEntry, error)
+	GetLoanEntries(ctx context.Context, loanID int) ([]models.JournalEntry, error)
+	TrialBalance(ctx context.Context) (*models.TrialBalance, error)
+}
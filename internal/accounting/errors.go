@@ -0,0 +1,8 @@
+package accounting
+
+import "github.com/evrintobing17/loan-billing-system/pkg/apperror"
+
+var (
+	ErrReversalOfReversal = apperror.New(apperror.Conflict, "reversal_not_reversible", "a reversal cannot be reversed")
+	ErrBusinessEntry      = apperror.New(apperror.Conflict, "entry_not_reversible", "entry records a loan, payment, write-off or restructure and cannot be reversed on its own")
+)
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/evrintobing17/loan-billing-system/internal/accounting"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+type AccountingHandler struct {
+	accountingUC accounting.AccountingUsecase
+}
+
+func NewAccountingHandler(uc accounting.AccountingUsecase) *AccountingHandler {
+	return &AccountingHandler{accountingUC: uc}
+}
+
+// TrialBalance godoc
+// @Summary Get the trial balance
+// @Description Debit and credit totals per general ledger account (admin only). balanced is true when total debits equal total credits.
+// @Tags accounting
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.TrialBalance
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /accounting/trial-balance [get]
+func (h *AccountingHandler) TrialBalance(c *gin.Context) {
+	tb, err := h.accountingUC.TrialBalance(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, tb)
+}
+
+// GetLoanEntries godoc
+// @Summary Get the journal entries of a loan
+// @Tags accounting
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {array} models.JournalEntry
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /loans/{id}/journal-entries [get]
+func (h *AccountingHandler) GetLoanEntries(c *gin.Context) {
+	loanID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid loan id"))
+		return
+	}
+	entries, err := h.accountingUC.GetLoanEntries(c.Request.Context(), loanID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// ReverseEntry godoc
+// @Summary Reverse a journal entry
+// @Description Post the mirror image of a journal entry (admin only). Each entry can be reversed once. Entries of a loan booking, payment, write-off or restructure are refused; reverse payments with POST /payments/{id}/reverse.
+// @Tags accounting
+// @Accept json
+// @Produce json
+// @Param id path int true "Journal entry ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param request body models.ReverseEntryRequest true "Reversal reason"
+// @Success 201 {object} models.JournalEntry
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /accounting/journal-entries/{id}/reverse [post]
+func (h *AccountingHandler) ReverseEntry(c *gin.Context) {
+	entryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid journal entry id"))
+		return
+	}
+
+	var req models.ReverseEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+
+	entry, err := h.accountingUC.ReverseEntry(c.Request.Context(), entryID, req.Reason)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, entry)
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evrintobing17/loan-billing-system/internal/accounting"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+	"github.com/lib/pq"
+)
+
+type accountingRepository struct {
+	DB *sql.DB
+}
+
+func NewAccountingRepository(DB *sql.DB) accounting.AccountingRepository {
+	return &accountingRepository{
+		DB: DB,
+	}
+}
+
+// CreateEntry implements [accounting.AccountingRepository].
+func (a *accountingRepository) CreateEntry(ctx context.Context, entry *models.JournalEntry) error {
+	return postgres.RunInTx(ctx, a.DB, func(tx postgres.DBTX) error {
+		query := `INSERT INTO journal_entries (entry_date, event_type, loan_id, reference, description, reversal_of)
+                  VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+		err := tx.QueryRowContext(ctx, query, entry.EntryDate, entry.EventType, entry.LoanID,
+			entry.Reference, entry.Description, entry.ReversalOf).Scan(&entry.ID, &entry.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		for _, line := range entry.Lines {
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO journal_lines (entry_id, account_code, debit, credit) VALUES ($1, $2, $3, $4)`,
+				entry.ID, line.AccountCode, line.Debit, line.Credit)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (a *accountingRepository) GetEntry(ctx context.Context, id int) (*models.JournalEntry, error) {
+	entries, err := a.queryEntries(ctx, `WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &entries[0], nil
+}
+
+// GetEntriesByLoanID returns the journal of a loan in posting order.
+func (a *accountingRepository) GetEntriesByLoanID(ctx context.Context, loanID int) ([]models.JournalEntry, error) {
+	return a.queryEntries(ctx, `WHERE loan_id = $1`, loanID)
+}
+
+// TrialBalance sums debits and credits per account over the whole ledger.
+func (a *accountingRepository) TrialBalance(ctx context.Context) ([]models.TrialBalanceRow, error) {
+	query := `SELECT a.code, a.name, a.account_type,
+                     COALESCE(SUM(l.debit), 0), COALESCE(SUM(l.credit), 0)
+              FROM gl_accounts a
+              LEFT JOIN journal_lines l ON l.account_code = a.code
+              GROUP BY a.code, a.name, a.account_type
+              ORDER BY a.code`
+	rows, err := postgres.Conn(ctx, a.DB).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query trial balance: %w", err)
+	}
+	defer rows.Close()
+
+	var balances []models.TrialBalanceRow
+	for rows.Next() {
+		var row models.TrialBalanceRow
+		err := rows.Scan(&row.AccountCode, &row.AccountName, &row.AccountType, &row.Debit, &row.Credit)
+		if err != nil {
+			return nil, fmt.Errorf("scan trial balance: %w", err)
+		}
+		balances = append(balances, row)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return balances, nil
+}
+
+func (a *accountingRepository) LoanAccountBalance(ctx context.Context, loanID int, accountCode string) (float64, error) {
+	query := `SELECT COALESCE(SUM(l.debit - l.credit), 0)
+              FROM journal_lines l
+              JOIN journal_entries e ON e.id = l.entry_id
+              WHERE e.loan_id = $1 AND l.account_code = $2`
+	var balance float64
+	err := postgres.Conn(ctx, a.DB).QueryRowContext(ctx, query, loanID, accountCode).Scan(&balance)
+	if err != nil {
+		return 0, fmt.Errorf("query loan account balance: %w", err)
+	}
+	return balance, nil
+}
+
+func (a *accountingRepository) queryEntries(ctx context.Context, where string, args ...any) ([]models.JournalEntry, error) {
+	query := `SELECT id, entry_date, event_type, loan_id, reference, description, reversal_of, created_at
+              FROM journal_entries ` + where + ` ORDER BY id`
+	rows, err := postgres.Conn(ctx, a.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query journal entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.JournalEntry
+	for rows.Next() {
+		var entry models.JournalEntry
+		var loanID, reversalOf sql.NullInt64
+		err := rows.Scan(
+			&entry.ID,
+			&entry.EntryDate,
+			&entry.EventType,
+			&loanID,
+			&entry.Reference,
+			&entry.Description,
+			&reversalOf,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan journal entry: %w", err)
+		}
+		entry.LoanID = nullIntPtr(loanID)
+		entry.ReversalOf = nullIntPtr(reversalOf)
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	if err := a.loadLines(ctx, entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (a *accountingRepository) loadLines(ctx context.Context, entries []models.JournalEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	index := make(map[int]int, len(entries))
+	ids := make([]int, len(entries))
+	for i, entry := range entries {
+		index[entry.ID] = i
+		ids[i] = entry.ID
+	}
+
+	rows, err := postgres.Conn(ctx, a.DB).QueryContext(ctx,
+		`SELECT entry_id, account_code, debit, credit FROM journal_lines WHERE entry_id = ANY($1) ORDER BY id`,
+		pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("query journal lines: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entryID int
+		var line models.JournalLine
+		if err := rows.Scan(&entryID, &line.AccountCode, &line.Debit, &line.Credit); err != nil {
+			return fmt.Errorf("scan journal line: %w", err)
+		}
+		i, ok := index[entryID]
+		if !ok {
+			return errors.New("journal line for unknown entry")
+		}
+		entries[i].Lines = append(entries[i].Lines, line)
+	}
+	return rows.Err()
+}
+
+func nullIntPtr(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int64)
+	return &v
+}
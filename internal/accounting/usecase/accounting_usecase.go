@@ -0,0 +1,334 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/accounting"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+)
+
+type accountingUseCase struct {
+	accountingRepo accounting.AccountingRepository
+	clock          clock.Clock
+}
+
+func NewAccountingUseCase(ar accounting.AccountingRepository, clk clock.Clock) accounting.AccountingUsecase {
+	return &accountingUseCase{
+		accountingRepo: ar,
+		clock:          clk,
+	}
+}
+
+// RecordLoanBooked recognises the receivable when a loan is created. The
+// proceeds owed to the borrower sit in suspense until disbursed; the
+// origination fee is income straight away.
+func (uc *accountingUseCase) RecordLoanBooked(ctx context.Context, loan *models.Loan) error {
+	fee := round2(loan.Principal) - round2(loan.NetDisbursement)
+	return uc.post(ctx, &models.JournalEntry{
+		EventType:   models.JournalEventLoanBooked,
+		LoanID:      &loan.ID,
+		Reference:   fmt.Sprintf("loan:%d", loan.ID),
+		Description: "Loan booked",
+		Lines: []models.JournalLine{
+			debit(models.AccountLoanReceivable, loan.Principal),
+			credit(models.AccountSuspense, loan.NetDisbursement),
+			credit(models.AccountFeeIncome, fee),
+		},
+	})
+}
+
+// RecordDisbursement moves a tranche out of suspense and into cash paid.
+func (uc *accountingUseCase) RecordDisbursement(ctx context.Context, disb *models.Disbursement) error {
+	return uc.post(ctx, &models.JournalEntry{
+		EntryDate:   disb.DisbursementDate,
+		EventType:   models.JournalEventDisbursement,
+		LoanID:      &disb.LoanID,
+		Reference:   fmt.Sprintf("disbursement:%d", disb.ID),
+		Description: fmt.Sprintf("Disbursement via %s (%s)", disb.Channel, disb.Reference),
+		Lines: []models.JournalLine{
+			debit(models.AccountSuspense, disb.Amount),
+			credit(models.AccountCash, disb.Amount),
+		},
+	})
+}
+
+// RecordPayment books cash received against principal, interest and fees.
+// The final payment clears what is left on the loan receivable, so no
+// rounding residue outlives the loan. Interest takes up any difference so the
+// entry always balances. It first settles interest already accrued; any excess
+// goes straight to income.
+func (uc *accountingUseCase) RecordPayment(ctx context.Context, payment *models.Payment, split models.PaymentSplit) error {
+	principal := round2(split.Principal)
+	if split.Final {
+		outstanding, err := uc.accountingRepo.LoanAccountBalance(ctx, payment.LoanID, models.AccountLoanReceivable)
+		if err != nil {
+			return err
+		}
+		principal = math.Max(0, round2(outstanding))
+	}
+	interest := round2(payment.Amount) - principal - round2(split.Fees)
+
+	accrued, err := uc.accountingRepo.LoanAccountBalance(ctx, payment.LoanID, models.AccountInterestReceivable)
+	if err != nil {
+		return err
+	}
+	fromAccrued := math.Max(0, math.Min(interest, round2(accrued)))
+
+	description := "Installment payment"
+	if payment.PaymentType == models.PaymentTypeSettlement {
+		description = "Settlement on refinancing"
+	}
+	return uc.post(ctx, &models.JournalEntry{
+		EventType:   models.JournalEventPayment,
+		LoanID:      &payment.LoanID,
+		Reference:   fmt.Sprintf("payment:%d", payment.ID),
+		Description: description,
+		Lines: []models.JournalLine{
+			debit(models.AccountCash, payment.Amount),
+			credit(models.AccountLoanReceivable, principal),
+			credit(models.AccountInterestReceivable, fromAccrued),
+			credit(models.AccountInterestIncome, interest-fromAccrued),
+			credit(models.AccountFeeIncome, split.Fees),
+		},
+	})
+}
+
+// RecordInterestAccrual recognises interest earned but not yet received.
+func (uc *accountingUseCase) RecordInterestAccrual(ctx context.Context, loanID int, date time.Time, amount float64) error {
+	return uc.post(ctx, &models.JournalEntry{
+		EntryDate:   date,
+		EventType:   models.JournalEventAccrual,
+		LoanID:      &loanID,
+		Reference:   fmt.Sprintf("accrual:%d:%s", loanID, date.Format("2006-01-02")),
+		Description: "Daily interest accrual",
+		Lines: []models.JournalLine{
+			debit(models.AccountInterestReceivable, amount),
+			credit(models.AccountInterestIncome, amount),
+		},
+	})
+}
+
+// RecordWriteOff charges the loan's remaining receivables to loan loss expense
+// and returns the amount charged, which is the loan's written-off balance.
+func (uc *accountingUseCase) RecordWriteOff(ctx context.Context, loanID int, date time.Time) (float64, error) {
+	principal, err := uc.accountingRepo.LoanAccountBalance(ctx, loanID, models.AccountLoanReceivable)
+	if err != nil {
+		return 0, err
+	}
+	interest, err := uc.accountingRepo.LoanAccountBalance(ctx, loanID, models.AccountInterestReceivable)
+	if err != nil {
+		return 0, err
+	}
+	principal, interest = math.Max(0, round2(principal)), math.Max(0, round2(interest))
+	if principal+interest == 0 {
+		return 0, nil
+	}
+	return principal + interest, uc.post(ctx, &models.JournalEntry{
+		EntryDate:   date,
+		EventType:   models.JournalEventWriteOff,
+		LoanID:      &loanID,
+		Reference:   fmt.Sprintf("write-off:%d", loanID),
+		Description: "Loan written off",
+		Lines: []models.JournalLine{
+			debit(models.AccountLoanLossExpense, principal+interest),
+			credit(models.AccountLoanReceivable, principal),
+			credit(models.AccountInterestReceivable, interest),
+		},
+	})
+}
+
+// RecordRecovery books cash collected on a written-off loan as recovery income.
+func (uc *accountingUseCase) RecordRecovery(ctx context.Context, payment *models.Payment) error {
+	return uc.post(ctx, &models.JournalEntry{
+		EventType:   models.JournalEventRecovery,
+		LoanID:      &payment.LoanID,
+		Reference:   fmt.Sprintf("payment:%d", payment.ID),
+		Description: "Recovery on written-off loan",
+		Lines: []models.JournalLine{
+			debit(models.AccountCash, payment.Amount),
+			credit(models.AccountRecoveryIncome, payment.Amount),
+		},
+	})
+}
+
+// RecordRestructure capitalises overdue interest and fees into the loan
+// receivable. Interest accrued on the closed schedule is settled first; any
+// accrued interest not capitalised is reversed out of income, since the new
+// schedule charges its own interest from the restructure date.
+func (uc *accountingUseCase) RecordRestructure(ctx context.Context, r *models.Restructure, capitalisedInterest, capitalisedFees float64) error {
+	accrued, err := uc.accountingRepo.LoanAccountBalance(ctx, r.LoanID, models.AccountInterestReceivable)
+	if err != nil {
+		return err
+	}
+	accrued = math.Max(0, round2(accrued))
+	capitalisedInterest, capitalisedFees = round2(capitalisedInterest), round2(capitalisedFees)
+	if accrued+capitalisedInterest+capitalisedFees == 0 {
+		return nil
+	}
+
+	lines := []models.JournalLine{
+		debit(models.AccountLoanReceivable, capitalisedInterest+capitalisedFees),
+		credit(models.AccountInterestReceivable, accrued),
+		credit(models.AccountFeeIncome, capitalisedFees),
+	}
+	if diff := capitalisedInterest - accrued; diff >= 0 {
+		lines = append(lines, credit(models.AccountInterestIncome, diff))
+	} else {
+		lines = append(lines, debit(models.AccountInterestIncome, -diff))
+	}
+	return uc.post(ctx, &models.JournalEntry{
+		EntryDate:   r.RestructureDate,
+		EventType:   models.JournalEventRestructure,
+		LoanID:      &r.LoanID,
+		Reference:   fmt.Sprintf("restructure:%d:v%d", r.LoanID, r.ToVersion),
+		Description: "Loan restructured",
+		Lines:       lines,
+	})
+}
+
+func (uc *accountingUseCase) RecognisedInterest(ctx context.Context, loanID int) (float64, error) {
+	balance, err := uc.accountingRepo.LoanAccountBalance(ctx, loanID, models.AccountInterestIncome)
+	if err != nil {
+		return 0, err
+	}
+	// Income accounts carry a credit balance.
+	return -balance, nil
+}
+
+// businessReferences prefix the references of entries that record a loan,
+// payment, write-off or restructure. Reversing one of them alone would leave
+// the ledger disagreeing with the loan and payment records.
+var businessReferences = []string{"loan:", "payment:", "write-off:", "restructure:"}
+
+// ReverseEntry posts the mirror image of an entry. An entry can be reversed
+// once, and entries of business events only through their own reversal, such
+// as ReversePayment.
+func (uc *accountingUseCase) ReverseEntry(ctx context.Context, entryID int, reason string) (*models.JournalEntry, error) {
+	original, err := uc.accountingRepo.GetEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	for _, prefix := range businessReferences {
+		if strings.HasPrefix(original.Reference, prefix) {
+			return nil, accounting.ErrBusinessEntry.Withf("entry %d records %s; reverse payments with POST /payments/{id}/reverse",
+				original.ID, original.Reference)
+		}
+	}
+	return uc.reverse(ctx, original, reason)
+}
+
+func (uc *accountingUseCase) reverse(ctx context.Context, original *models.JournalEntry, reason string) (*models.JournalEntry, error) {
+	if original.EventType == models.JournalEventReversal {
+		return nil, accounting.ErrReversalOfReversal
+	}
+
+	lines := make([]models.JournalLine, len(original.Lines))
+	for i, line := range original.Lines {
+		lines[i] = models.JournalLine{
+			AccountCode: line.AccountCode,
+			Debit:       line.Credit,
+			Credit:      line.Debit,
+		}
+	}
+	reversal := &models.JournalEntry{
+		EventType:   models.JournalEventReversal,
+		LoanID:      original.LoanID,
+		Reference:   original.Reference,
+		Description: reason,
+		ReversalOf:  &original.ID,
+		Lines:       lines,
+	}
+	if err := uc.post(ctx, reversal); err != nil {
+		return nil, err
+	}
+	return reversal, nil
+}
+
+// ReversePayment implements [accounting.AccountingUsecase].
+func (uc *accountingUseCase) ReversePayment(ctx context.Context, payment *models.Payment, reason string) (*models.JournalEntry, error) {
+	entries, err := uc.accountingRepo.GetEntriesByLoanID(ctx, payment.LoanID)
+	if err != nil {
+		return nil, err
+	}
+	reference := fmt.Sprintf("payment:%d", payment.ID)
+	for _, entry := range entries {
+		if entry.Reference != reference {
+			continue
+		}
+		if entry.EventType == models.JournalEventPayment || entry.EventType == models.JournalEventRecovery {
+			return uc.reverse(ctx, &entry, reason)
+		}
+	}
+	return nil, fmt.Errorf("no journal entry for payment %d", payment.ID)
+}
+
+func (uc *accountingUseCase) GetLoanEntries(ctx context.Context, loanID int) ([]models.JournalEntry, error) {
+	return uc.accountingRepo.GetEntriesByLoanID(ctx, loanID)
+}
+
+func (uc *accountingUseCase) TrialBalance(ctx context.Context) (*models.TrialBalance, error) {
+	rows, err := uc.accountingRepo.TrialBalance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tb := &models.TrialBalance{Accounts: rows}
+	for i := range tb.Accounts {
+		row := &tb.Accounts[i]
+		row.Balance = round2(row.Debit - row.Credit)
+		tb.TotalDebit += row.Debit
+		tb.TotalCredit += row.Credit
+	}
+	tb.TotalDebit = round2(tb.TotalDebit)
+	tb.TotalCredit = round2(tb.TotalCredit)
+	tb.Balanced = tb.TotalDebit == tb.TotalCredit
+	return tb, nil
+}
+
+// post drops zero lines, checks that debits equal credits and stores the entry.
+func (uc *accountingUseCase) post(ctx context.Context, entry *models.JournalEntry) error {
+	if entry.EntryDate.IsZero() {
+		entry.EntryDate = clock.Today(ctx, uc.clock)
+	}
+
+	var lines []models.JournalLine
+	var debits, credits float64
+	for _, line := range entry.Lines {
+		line.Debit = round2(line.Debit)
+		line.Credit = round2(line.Credit)
+		if line.Debit == 0 && line.Credit == 0 {
+			continue
+		}
+		if line.Debit < 0 || line.Credit < 0 {
+			return fmt.Errorf("negative amount on account %s", line.AccountCode)
+		}
+		debits += line.Debit
+		credits += line.Credit
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return errors.New("journal entry has no lines")
+	}
+	if round2(debits) != round2(credits) {
+		return fmt.Errorf("unbalanced journal entry: debits %.2f, credits %.2f", debits, credits)
+	}
+	entry.Lines = lines
+	return uc.accountingRepo.CreateEntry(ctx, entry)
+}
+
+func debit(account string, amount float64) models.JournalLine {
+	return models.JournalLine{AccountCode: account, Debit: amount}
+}
+
+func credit(account string, amount float64) models.JournalLine {
+	return models.JournalLine{AccountCode: account, Credit: amount}
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
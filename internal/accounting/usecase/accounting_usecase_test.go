@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/accounting"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+)
+
+// memoryLedger keeps posted entries in memory and sums them per loan.
+type memoryLedger struct {
+	entries []models.JournalEntry
+}
+
+func (m *memoryLedger) CreateEntry(ctx context.Context, entry *models.JournalEntry) error {
+	entry.ID = len(m.entries) + 1
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+func (m *memoryLedger) GetEntry(ctx context.Context, id int) (*models.JournalEntry, error) {
+	if id < 1 || id > len(m.entries) {
+		return nil, sql.ErrNoRows
+	}
+	entry := m.entries[id-1]
+	return &entry, nil
+}
+
+func (m *memoryLedger) GetEntriesByLoanID(ctx context.Context, loanID int) ([]models.JournalEntry, error) {
+	var entries []models.JournalEntry
+	for _, entry := range m.entries {
+		if entry.LoanID != nil && *entry.LoanID == loanID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *memoryLedger) TrialBalance(ctx context.Context) ([]models.TrialBalanceRow, error) {
+	return nil, nil
+}
+
+func (m *memoryLedger) LoanAccountBalance(ctx context.Context, loanID int, accountCode string) (float64, error) {
+	var balance float64
+	for _, entry := range m.entries {
+		if entry.LoanID == nil || *entry.LoanID != loanID {
+			continue
+		}
+		for _, line := range entry.Lines {
+			if line.AccountCode == accountCode {
+				balance += line.Debit - line.Credit
+			}
+		}
+	}
+	return balance, nil
+}
+
+const testLoanID = 7
+
+func newTestLedger(t *testing.T) (*accountingUseCase, *memoryLedger) {
+	t.Helper()
+	ledger := &memoryLedger{}
+	uc := NewAccountingUseCase(ledger, clock.NewFixed(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))).(*accountingUseCase)
+	err := uc.RecordLoanBooked(context.Background(), &models.Loan{ID: testLoanID, Principal: 1000, NetDisbursement: 980})
+	if err != nil {
+		t.Fatalf("RecordLoanBooked() error = %v", err)
+	}
+	return uc, ledger
+}
+
+func testPayment(id int, amount float64) *models.Payment {
+	return &models.Payment{ID: id, LoanID: testLoanID, Amount: amount, PaymentType: models.PaymentTypeInstallment}
+}
+
+func TestPostingsBalance(t *testing.T) {
+	date := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		post func(ctx context.Context, uc *accountingUseCase) error
+		// want are the loan's balances, debits minus credits, afterwards.
+		want map[string]float64
+	}{
+		{
+			name: "payment settles accrued interest before income",
+			post: func(ctx context.Context, uc *accountingUseCase) error {
+				if err := uc.RecordInterestAccrual(ctx, testLoanID, date, 10.004); err != nil {
+					return err
+				}
+				return uc.RecordPayment(ctx, testPayment(1, 280), models.PaymentSplit{Principal: 250, Interest: 25, Fees: 5})
+			},
+			want: map[string]float64{
+				models.AccountLoanReceivable:     750,
+				models.AccountInterestReceivable: 0,
+				models.AccountInterestIncome:     -25,
+				models.AccountFeeIncome:          -25,
+			},
+		},
+		{
+			name: "final payment clears the rounding residue",
+			post: func(ctx context.Context, uc *accountingUseCase) error {
+				for id := 1; id <= 2; id++ {
+					err := uc.RecordPayment(ctx, testPayment(id, 366.66), models.PaymentSplit{Principal: 333.333, Interest: 33.333})
+					if err != nil {
+						return err
+					}
+				}
+				return uc.RecordPayment(ctx, testPayment(3, 366.67), models.PaymentSplit{Principal: 333.333, Interest: 33.333, Final: true})
+			},
+			want: map[string]float64{
+				models.AccountLoanReceivable: 0,
+				models.AccountInterestIncome: -99.99,
+				models.AccountCash:           1099.99,
+			},
+		},
+		{
+			name: "write-off charges both receivables to loss",
+			post: func(ctx context.Context, uc *accountingUseCase) error {
+				if err := uc.RecordInterestAccrual(ctx, testLoanID, date, 12.345); err != nil {
+					return err
+				}
+				if err := uc.RecordPayment(ctx, testPayment(1, 400), models.PaymentSplit{Principal: 400}); err != nil {
+					return err
+				}
+				amount, err := uc.RecordWriteOff(ctx, testLoanID, date)
+				if err != nil {
+					return err
+				}
+				if amount != 612.35 {
+					return errors.New("written off amount is not the receivables left")
+				}
+				return nil
+			},
+			want: map[string]float64{
+				models.AccountLoanReceivable:     0,
+				models.AccountInterestReceivable: 0,
+				models.AccountLoanLossExpense:    612.35,
+			},
+		},
+		{
+			name: "restructure capitalises more than was accrued",
+			post: func(ctx context.Context, uc *accountingUseCase) error {
+				if err := uc.RecordInterestAccrual(ctx, testLoanID, date, 10); err != nil {
+					return err
+				}
+				r := &models.Restructure{LoanID: testLoanID, ToVersion: 2, RestructureDate: date}
+				return uc.RecordRestructure(ctx, r, 25.005, 5)
+			},
+			want: map[string]float64{
+				models.AccountLoanReceivable:     1030.01,
+				models.AccountInterestReceivable: 0,
+				models.AccountInterestIncome:     -25.01,
+				models.AccountFeeIncome:          -25,
+			},
+		},
+		{
+			name: "restructure reverses accrued interest not capitalised",
+			post: func(ctx context.Context, uc *accountingUseCase) error {
+				if err := uc.RecordInterestAccrual(ctx, testLoanID, date, 40); err != nil {
+					return err
+				}
+				r := &models.Restructure{LoanID: testLoanID, ToVersion: 2, RestructureDate: date}
+				return uc.RecordRestructure(ctx, r, 15, 0)
+			},
+			want: map[string]float64{
+				models.AccountLoanReceivable:     1015,
+				models.AccountInterestReceivable: 0,
+				models.AccountInterestIncome:     -15,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			uc, ledger := newTestLedger(t)
+			if err := tt.post(ctx, uc); err != nil {
+				t.Fatalf("posting error = %v", err)
+			}
+			for _, entry := range ledger.entries {
+				var debits, credits float64
+				for _, line := range entry.Lines {
+					debits += line.Debit
+					credits += line.Credit
+				}
+				if round2(debits) != round2(credits) {
+					t.Errorf("%s entry: debits %.2f, credits %.2f", entry.EventType, debits, credits)
+				}
+			}
+			for account, want := range tt.want {
+				got, _ := ledger.LoanAccountBalance(ctx, testLoanID, account)
+				if math.Abs(got-want) > 0.001 {
+					t.Errorf("%s balance = %.2f, want %.2f", account, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReverseEntry(t *testing.T) {
+	ctx := context.Background()
+	uc, ledger := newTestLedger(t)
+	date := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	if err := uc.RecordInterestAccrual(ctx, testLoanID, date, 10); err != nil {
+		t.Fatalf("RecordInterestAccrual() error = %v", err)
+	}
+	if err := uc.RecordPayment(ctx, testPayment(1, 100), models.PaymentSplit{Principal: 100}); err != nil {
+		t.Fatalf("RecordPayment() error = %v", err)
+	}
+	booked, accrual, paid := 1, 2, 3
+
+	tests := []struct {
+		name    string
+		entryID int
+		wantErr error
+	}{
+		{name: "loan booking", entryID: booked, wantErr: accounting.ErrBusinessEntry},
+		{name: "payment", entryID: paid, wantErr: accounting.ErrBusinessEntry},
+		{name: "accrual", entryID: accrual},
+		{name: "reversal", entryID: 4, wantErr: accounting.ErrReversalOfReversal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.ReverseEntry(ctx, tt.entryID, "posted in error")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReverseEntry() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	if got, _ := ledger.LoanAccountBalance(ctx, testLoanID, models.AccountInterestReceivable); got != 0 {
+		t.Errorf("interest receivable = %.2f after reversing the accrual, want 0", got)
+	}
+}
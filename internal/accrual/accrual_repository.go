@@ -0,0 +1,14 @@
+package accrual
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type AccrualRepository interface {
+	// Create stores the accrual unless one already exists for the loan and
+	// date, and reports whether it was inserted.
+	Create(ctx context.Context, accrual *models.InterestAccrual) (bool, error)
+	GetByLoanID(ctx context.Context, loanID int) ([]models.InterestAccrual, error)
+}
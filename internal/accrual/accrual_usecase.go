@@ -0,0 +1,13 @@
+package accrual
+
+import (
+	"context"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type AccrualUsecase interface {
+	RunAccrual(ctx context.Context, businessDate time.Time) (*models.AccrualRun, error)
+	GetLoanAccruals(ctx context.Context, loanID int) ([]models.InterestAccrual, error)
+}
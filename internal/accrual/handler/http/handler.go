@@ -0,0 +1,82 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/accrual"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+type AccrualHandler struct {
+	accrualUC accrual.AccrualUsecase
+}
+
+func NewAccrualHandler(uc accrual.AccrualUsecase) *AccrualHandler {
+	return &AccrualHandler{accrualUC: uc}
+}
+
+// RunAccrual godoc
+// @Summary Run the interest accrual for a business date
+// @Description Accrue interest for all active loans (admin only). Safe to repeat: each loan is accrued at most once per business date. Defaults to the current business date.
+// @Tags accruals
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param request body models.RunAccrualRequest false "Business date"
+// @Success 200 {object} models.AccrualRun
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /accruals/run [post]
+func (h *AccrualHandler) RunAccrual(c *gin.Context) {
+	var req models.RunAccrualRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(apperror.Default(err, apperror.Invalid))
+			return
+		}
+	}
+
+	var businessDate time.Time
+	if req.BusinessDate != "" {
+		var err error
+		businessDate, err = time.Parse("2006-01-02", req.BusinessDate)
+		if err != nil {
+			c.Error(apperror.Invalidf("invalid business_date format, use YYYY-MM-DD"))
+			return
+		}
+	}
+
+	run, err := h.accrualUC.RunAccrual(c.Request.Context(), businessDate)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+// GetLoanAccruals godoc
+// @Summary Get the interest accrual history of a loan
+// @Tags accruals
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Success 200 {array} models.InterestAccrual
+// @Failure 404 {object} models.Problem
+// @Router /loans/{id}/accruals [get]
+func (h *AccrualHandler) GetLoanAccruals(c *gin.Context) {
+	loanID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid loan id"))
+		return
+	}
+	accruals, err := h.accrualUC.GetLoanAccruals(c.Request.Context(), loanID)
+	if err != nil {
+		c.Error(apperror.Default(err, apperror.NotFound))
+		return
+	}
+	c.JSON(http.StatusOK, accruals)
+}
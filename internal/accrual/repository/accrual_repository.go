@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evrintobing17/loan-billing-system/internal/accrual"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+type accrualRepository struct {
+	DB *sql.DB
+}
+
+func NewAccrualRepository(DB *sql.DB) accrual.AccrualRepository {
+	return &accrualRepository{
+		DB: DB,
+	}
+}
+
+// Create implements [accrual.AccrualRepository].
+func (a *accrualRepository) Create(ctx context.Context, acc *models.InterestAccrual) (bool, error) {
+	query := `INSERT INTO interest_accruals (loan_id, accrual_date, amount, days_past_due, non_accrual)
+              VALUES ($1, $2, $3, $4, $5)
+              ON CONFLICT (loan_id, accrual_date) DO NOTHING
+              RETURNING id, created_at`
+	err := postgres.Conn(ctx, a.DB).QueryRowContext(ctx, query, acc.LoanID, acc.AccrualDate, acc.Amount,
+		acc.DaysPastDue, acc.NonAccrual).Scan(&acc.ID, &acc.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// GetByLoanID returns the accrual history of a loan, oldest first.
+func (a *accrualRepository) GetByLoanID(ctx context.Context, loanID int) ([]models.InterestAccrual, error) {
+	query := `SELECT id, loan_id, accrual_date, amount, days_past_due, non_accrual, created_at
+              FROM interest_accruals
+              WHERE loan_id = $1
+              ORDER BY accrual_date`
+	rows, err := postgres.Conn(ctx, a.DB).QueryContext(ctx, query, loanID)
+	if err != nil {
+		return nil, fmt.Errorf("query interest accruals: %w", err)
+	}
+	defer rows.Close()
+
+	var accruals []models.InterestAccrual
+	for rows.Next() {
+		var acc models.InterestAccrual
+		err := rows.Scan(
+			&acc.ID,
+			&acc.LoanID,
+			&acc.AccrualDate,
+			&acc.Amount,
+			&acc.DaysPastDue,
+			&acc.NonAccrual,
+			&acc.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan interest accrual: %w", err)
+		}
+		accruals = append(accruals, acc)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return accruals, nil
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/accounting"
+	"github.com/evrintobing17/loan-billing-system/internal/accrual"
+	"github.com/evrintobing17/loan-billing-system/internal/loan"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+type accrualUseCase struct {
+	accrualRepo   accrual.AccrualRepository
+	loanRepo      loan.LoanRepository
+	loanUC        loan.LoanUsecase
+	accountingUC  accounting.AccountingUsecase
+	tx            postgres.Transactor
+	clock         clock.Clock
+	nonAccrualDPD int
+}
+
+// NewAccrualUseCase builds the accrual engine. Loans more than nonAccrualDPD
+// days past due stop accruing interest.
+func NewAccrualUseCase(ar accrual.AccrualRepository, lr loan.LoanRepository, luc loan.LoanUsecase, auc accounting.AccountingUsecase,
+	tx postgres.Transactor, clk clock.Clock, nonAccrualDPD int) accrual.AccrualUsecase {
+	return &accrualUseCase{
+		accrualRepo:   ar,
+		loanRepo:      lr,
+		loanUC:        luc,
+		accountingUC:  auc,
+		tx:            tx,
+		clock:         clk,
+		nonAccrualDPD: nonAccrualDPD,
+	}
+}
+
+// RunAccrual accrues interest for every active loan up to businessDate
+// (today when zero). Running it again for the same date is a no-op.
+func (uc *accrualUseCase) RunAccrual(ctx context.Context, businessDate time.Time) (*models.AccrualRun, error) {
+	if businessDate.IsZero() {
+		businessDate = clock.Today(ctx, uc.clock)
+	}
+	businessDate = clock.Date(businessDate)
+	// Evaluate days past due as of the business date being accrued.
+	ctx = clock.WithAsOfDate(ctx, businessDate)
+
+	loans, err := uc.loanRepo.ListByStatus(ctx, models.LoanStatusActive)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &models.AccrualRun{BusinessDate: businessDate}
+	for i := range loans {
+		run.LoansProcessed++
+		acc, inserted, err := uc.accrueLoan(ctx, &loans[i], businessDate)
+		if err != nil {
+			run.Errors = append(run.Errors, fmt.Sprintf("loan %d: %v", loans[i].ID, err))
+			continue
+		}
+		switch {
+		case !inserted:
+			run.AlreadyRun++
+		case acc.NonAccrual:
+			run.NonAccrual++
+		default:
+			run.Accrued++
+			run.TotalAmount += acc.Amount
+		}
+	}
+	run.TotalAmount = round2(run.TotalAmount)
+	return run, nil
+}
+
+func (uc *accrualUseCase) GetLoanAccruals(ctx context.Context, loanID int) ([]models.InterestAccrual, error) {
+	if _, err := uc.loanRepo.GetByID(ctx, loanID); err != nil {
+		return nil, err
+	}
+	return uc.accrualRepo.GetByLoanID(ctx, loanID)
+}
+
+func (uc *accrualUseCase) accrueLoan(ctx context.Context, l *models.Loan, date time.Time) (*models.InterestAccrual, bool, error) {
+	dpd, err := uc.loanUC.GetDaysPastDue(ctx, l.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	acc := &models.InterestAccrual{
+		LoanID:      l.ID,
+		AccrualDate: date,
+		DaysPastDue: dpd,
+		NonAccrual:  dpd > uc.nonAccrualDPD,
+	}
+
+	var inserted bool
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if !acc.NonAccrual {
+			acc.Amount, err = uc.accrualAmount(ctx, l, date)
+			if err != nil {
+				return err
+			}
+		}
+		inserted, err = uc.accrualRepo.Create(ctx, acc)
+		if err != nil || !inserted || acc.Amount == 0 {
+			return err
+		}
+		return uc.accountingUC.RecordInterestAccrual(ctx, l.ID, date, acc.Amount)
+	})
+	return acc, inserted, err
+}
+
+// accrualAmount spreads the scheduled interest evenly over the days from the
+// first disbursement to the final due date, and returns the part of it up to
+// date that has not been recognised yet. For a restructured loan the current
+// schedule's interest is spread from the restructure date, on top of what was
+// recognised before it.
+func (uc *accrualUseCase) accrualAmount(ctx context.Context, l *models.Loan, date time.Time) (float64, error) {
+	installments, err := uc.loanRepo.GetInstallments(ctx, l.ID)
+	if err != nil {
+		return 0, err
+	}
+	if len(installments) == 0 {
+		return 0, nil
+	}
+
+	var totalInterest float64
+	for _, inst := range installments {
+		totalInterest += inst.InterestAmount
+	}
+
+	anchor := l.StartDate
+	if l.FirstDisbursementDate != nil {
+		anchor = *l.FirstDisbursementDate
+	}
+	var base float64
+	if l.Restructured {
+		restructures, err := uc.loanRepo.GetRestructures(ctx, l.ID)
+		if err != nil {
+			return 0, err
+		}
+		if n := len(restructures); n > 0 {
+			anchor = restructures[n-1].RestructureDate
+			base = restructures[n-1].RecognisedInterest
+		}
+	}
+	anchor = clock.Date(anchor)
+	maturity := clock.Date(installments[len(installments)-1].DueDate)
+	totalDays := days(maturity.Sub(anchor))
+	if totalDays <= 0 {
+		return 0, nil
+	}
+	elapsed := math.Min(math.Max(days(date.Sub(anchor)), 0), totalDays)
+	target := round2(base + totalInterest*elapsed/totalDays)
+
+	recognised, err := uc.accountingUC.RecognisedInterest(ctx, l.ID)
+	if err != nil {
+		return 0, err
+	}
+	return math.Max(0, round2(target-recognised)), nil
+}
+
+func days(d time.Duration) float64 {
+	return math.Round(d.Hours() / 24)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
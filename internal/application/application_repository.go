@@ -0,0 +1,16 @@
+package application
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type ApplicationRepository interface {
+	Create(ctx context.Context, app *models.LoanApplication) error
+	GetByID(ctx context.Context, id int) (*models.LoanApplication, error)
+	// GetForUpdate loads the application and locks it for the caller's transaction.
+	GetForUpdate(ctx context.Context, id int) (*models.LoanApplication, error)
+	List(ctx context.Context, status string) ([]models.LoanApplication, error)
+	Update(ctx context.Context, app *models.LoanApplication) error
+}
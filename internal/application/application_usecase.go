@@ -0,0 +1,17 @@
+package application
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type ApplicationUsecase interface {
+	Submit(ctx context.Context, terms models.LoanTerms, submittedBy string) (*models.LoanApplication, error)
+	GetApplication(ctx context.Context, id int) (*models.LoanApplication, error)
+	ListApplications(ctx context.Context, status string) ([]models.LoanApplication, error)
+	GetCreditDecisions(ctx context.Context, id int) ([]models.CreditDecision, error)
+	StartReview(ctx context.Context, id int, reviewer string) (*models.LoanApplication, error)
+	Approve(ctx context.Context, id int, approver, role, note string) (*models.LoanApplication, error)
+	Reject(ctx context.Context, id int, rejecter, note string) (*models.LoanApplication, error)
+}
@@ -0,0 +1,11 @@
+package application
+
+import "github.com/evrintobing17/loan-billing-system/pkg/apperror"
+
+var (
+	ErrNotFound              = apperror.New(apperror.NotFound, "application_not_found", "loan application not found")
+	ErrStatus                = apperror.New(apperror.Conflict, "application_status", "application is not in a status that allows this")
+	ErrSubmitterDecides      = apperror.New(apperror.Forbidden, "submitter_cannot_decide", "an application cannot be decided by its submitter")
+	ErrRoleCannotApprove     = apperror.New(apperror.Forbidden, "role_cannot_approve", "role cannot approve loan applications")
+	ErrApprovalLimitExceeded = apperror.New(apperror.Forbidden, "approval_limit_exceeded", "principal exceeds the approval limit of the role")
+)
@@ -0,0 +1,268 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/application"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/evrintobing17/loan-billing-system/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type ApplicationHandler struct {
+	applicationUC application.ApplicationUsecase
+}
+
+func NewApplicationHandler(uc application.ApplicationUsecase) *ApplicationHandler {
+	return &ApplicationHandler{applicationUC: uc}
+}
+
+// Submit godoc
+// @Summary Submit a loan application
+// @Description Submit loan terms for review; borrower_id is required. The automated credit decision runs straight away and declines reject the application. The loan is only booked once the application is approved.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "Submitting user"
+// @Param request body models.CreateLoanRequest true "Loan details"
+// @Success 201 {object} models.LoanApplication
+// @Failure 400 {object} models.Problem
+// @Router /applications [post]
+func (h *ApplicationHandler) Submit(c *gin.Context) {
+	actor, ok := requireActor(c)
+	if !ok {
+		return
+	}
+	var req models.CreateLoanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid start_date format, use YYYY-MM-DD"))
+		return
+	}
+
+	if req.BorrowerID == "" {
+		c.Error(apperror.Invalidf("borrower_id is required"))
+		return
+	}
+
+	app, err := h.applicationUC.Submit(c.Request.Context(), models.LoanTerms{
+		BorrowerID:   req.BorrowerID,
+		Principal:    req.Principal,
+		InterestRate: req.InterestRate,
+		TermWeeks:    req.TermWeeks,
+		StartDate:    startDate,
+		ProductCode:  req.ProductCode,
+	}, actor)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, app)
+}
+
+// ListApplications godoc
+// @Summary List loan applications
+// @Tags applications
+// @Produce json
+// @Param status query string false "Filter by status"
+// @Success 200 {array} models.LoanApplication
+// @Failure 500 {object} models.Problem
+// @Router /applications [get]
+func (h *ApplicationHandler) ListApplications(c *gin.Context) {
+	apps, err := h.applicationUC.ListApplications(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, apps)
+}
+
+// GetApplication godoc
+// @Summary Get a loan application
+// @Tags applications
+// @Produce json
+// @Param id path int true "Application ID"
+// @Success 200 {object} models.LoanApplication
+// @Failure 404 {object} models.Problem
+// @Router /applications/{id} [get]
+func (h *ApplicationHandler) GetApplication(c *gin.Context) {
+	id, ok := applicationID(c)
+	if !ok {
+		return
+	}
+	app, err := h.applicationUC.GetApplication(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, app)
+}
+
+// GetCreditDecisions godoc
+// @Summary List the credit decisions of a loan application
+// @Description Automated decisions with the inputs they were made on and their reasons.
+// @Tags applications
+// @Produce json
+// @Param id path int true "Application ID"
+// @Success 200 {array} models.CreditDecision
+// @Failure 404 {object} models.Problem
+// @Router /applications/{id}/credit-decisions [get]
+func (h *ApplicationHandler) GetCreditDecisions(c *gin.Context) {
+	id, ok := applicationID(c)
+	if !ok {
+		return
+	}
+	decisions, err := h.applicationUC.GetCreditDecisions(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, decisions)
+}
+
+// StartReview godoc
+// @Summary Start reviewing a loan application
+// @Description Move a submitted application to under_review (admin only).
+// @Tags applications
+// @Produce json
+// @Param id path int true "Application ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param X-User-ID header string false "Reviewing user, where user keys are not configured"
+// @Param X-User-Key header string false "Reviewing user's key, where user keys are configured"
+// @Success 200 {object} models.LoanApplication
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Router /applications/{id}/review [post]
+func (h *ApplicationHandler) StartReview(c *gin.Context) {
+	id, ok := applicationID(c)
+	if !ok {
+		return
+	}
+	actor, ok := requireActor(c)
+	if !ok {
+		return
+	}
+	app, err := h.applicationUC.StartReview(c.Request.Context(), id, actor)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, app)
+}
+
+// Approve godoc
+// @Summary Approve a loan application
+// @Description Approve an application under review and book its loan (admin only). The approver must not be the submitter, and the principal must be within the approval limit of the approver's role. Refused with 422 if the loan would exceed the borrower's exposure limits.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path int true "Application ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param X-User-Key header string true "Approving user's key; refused with 403 unless USER_KEYS is configured"
+// @Param request body models.ApplicationDecisionRequest false "Decision note"
+// @Success 200 {object} models.LoanApplication
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Router /applications/{id}/approve [post]
+func (h *ApplicationHandler) Approve(c *gin.Context) {
+	id, ok := applicationID(c)
+	if !ok {
+		return
+	}
+	actor, ok := requireActor(c)
+	if !ok {
+		return
+	}
+	note, ok := decisionNote(c)
+	if !ok {
+		return
+	}
+	app, err := h.applicationUC.Approve(c.Request.Context(), id, actor, middleware.ActorRole(c), note)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, app)
+}
+
+// Reject godoc
+// @Summary Reject a loan application
+// @Description Reject a submitted or under-review application (admin only). The rejecter must not be the submitter.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path int true "Application ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param X-User-Key header string true "Rejecting user's key; refused with 403 unless USER_KEYS is configured"
+// @Param request body models.ApplicationDecisionRequest false "Decision note"
+// @Success 200 {object} models.LoanApplication
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Router /applications/{id}/reject [post]
+func (h *ApplicationHandler) Reject(c *gin.Context) {
+	id, ok := applicationID(c)
+	if !ok {
+		return
+	}
+	actor, ok := requireActor(c)
+	if !ok {
+		return
+	}
+	note, ok := decisionNote(c)
+	if !ok {
+		return
+	}
+	app, err := h.applicationUC.Reject(c.Request.Context(), id, actor, note)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, app)
+}
+
+func applicationID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid application id"))
+		return 0, false
+	}
+	return id, true
+}
+
+func requireActor(c *gin.Context) (string, bool) {
+	actor := middleware.Actor(c)
+	if actor == "" {
+		c.Error(middleware.ErrActorRequired)
+		return "", false
+	}
+	return actor, true
+}
+
+// decisionNote reads the optional decision body.
+func decisionNote(c *gin.Context) (string, bool) {
+	var req models.ApplicationDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(apperror.Default(err, apperror.Invalid))
+			return "", false
+		}
+	}
+	return req.Note, true
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evrintobing17/loan-billing-system/internal/application"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+type applicationRepository struct {
+	DB *sql.DB
+}
+
+func NewApplicationRepository(DB *sql.DB) application.ApplicationRepository {
+	return &applicationRepository{
+		DB: DB,
+	}
+}
+
+const applicationColumns = `id, borrower_id, principal, interest_rate, term_weeks, start_date, product_code, status, credit_outcome, submitted_by,
+                            reviewed_by, decided_by, decided_role, decision_note, loan_id, created_at, updated_at, decided_at`
+
+func scanApplication(row interface{ Scan(...any) error }, a *models.LoanApplication) error {
+	var reviewedBy, decidedBy, decidedRole sql.NullString
+	var loanID sql.NullInt64
+	var decidedAt sql.NullTime
+	err := row.Scan(
+		&a.ID,
+		&a.BorrowerID,
+		&a.Principal,
+		&a.InterestRate,
+		&a.TermWeeks,
+		&a.StartDate,
+		&a.ProductCode,
+		&a.Status,
+		&a.CreditOutcome,
+		&a.SubmittedBy,
+		&reviewedBy,
+		&decidedBy,
+		&decidedRole,
+		&a.DecisionNote,
+		&loanID,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+		&decidedAt,
+	)
+	if err != nil {
+		return err
+	}
+	a.ReviewedBy = reviewedBy.String
+	a.DecidedBy = decidedBy.String
+	a.DecidedRole = decidedRole.String
+	if loanID.Valid {
+		id := int(loanID.Int64)
+		a.LoanID = &id
+	}
+	if decidedAt.Valid {
+		a.DecidedAt = &decidedAt.Time
+	}
+	return nil
+}
+
+// Create implements [application.ApplicationRepository].
+func (r *applicationRepository) Create(ctx context.Context, a *models.LoanApplication) error {
+	query := `INSERT INTO loan_applications (borrower_id, principal, interest_rate, term_weeks, start_date, product_code, status, submitted_by)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at, updated_at`
+	return postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, a.BorrowerID, a.Principal, a.InterestRate, a.TermWeeks,
+		a.StartDate, a.ProductCode, a.Status, a.SubmittedBy).Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt)
+}
+
+func (r *applicationRepository) GetByID(ctx context.Context, id int) (*models.LoanApplication, error) {
+	return r.get(ctx, `SELECT `+applicationColumns+` FROM loan_applications WHERE id = $1`, id)
+}
+
+func (r *applicationRepository) GetForUpdate(ctx context.Context, id int) (*models.LoanApplication, error) {
+	return r.get(ctx, `SELECT `+applicationColumns+` FROM loan_applications WHERE id = $1 FOR UPDATE`, id)
+}
+
+func (r *applicationRepository) get(ctx context.Context, query string, id int) (*models.LoanApplication, error) {
+	var a models.LoanApplication
+	err := scanApplication(postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, id), &a)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, application.ErrNotFound.Wrap(err)
+		}
+		return nil, fmt.Errorf("query loan application: %w", err)
+	}
+	return &a, nil
+}
+
+// List returns applications in the given status, or all when status is empty.
+func (r *applicationRepository) List(ctx context.Context, status string) ([]models.LoanApplication, error) {
+	query := `SELECT ` + applicationColumns + `
+              FROM loan_applications
+              WHERE $1 = '' OR status = $1
+              ORDER BY id`
+	rows, err := postgres.Conn(ctx, r.DB).QueryContext(ctx, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("query loan applications: %w", err)
+	}
+	defer rows.Close()
+
+	var apps []models.LoanApplication
+	for rows.Next() {
+		var a models.LoanApplication
+		if err := scanApplication(rows, &a); err != nil {
+			return nil, fmt.Errorf("scan loan application: %w", err)
+		}
+		apps = append(apps, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return apps, nil
+}
+
+// Update stores the workflow fields of an application.
+func (r *applicationRepository) Update(ctx context.Context, a *models.LoanApplication) error {
+	query := `UPDATE loan_applications
+              SET status = $2, credit_outcome = $8, reviewed_by = NULLIF($3, ''), decided_by = NULLIF($4, ''), decided_role = NULLIF($5, ''),
+                  decision_note = $6, loan_id = $7, updated_at = CURRENT_TIMESTAMP,
+                  decided_at = CASE WHEN $2 IN ('approved', 'rejected') THEN CURRENT_TIMESTAMP END
+              WHERE id = $1
+              RETURNING updated_at, decided_at`
+	var decidedAt sql.NullTime
+	err := postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, a.ID, a.Status, a.ReviewedBy, a.DecidedBy,
+		a.DecidedRole, a.DecisionNote, a.LoanID, a.CreditOutcome).Scan(&a.UpdatedAt, &decidedAt)
+	if err != nil {
+		return fmt.Errorf("update loan application: %w", err)
+	}
+	if decidedAt.Valid {
+		a.DecidedAt = &decidedAt.Time
+	}
+	return nil
+}
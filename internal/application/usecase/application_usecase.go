@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/evrintobing17/loan-billing-system/internal/application"
+	"github.com/evrintobing17/loan-billing-system/internal/credit"
+	"github.com/evrintobing17/loan-billing-system/internal/loan"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+type applicationUseCase struct {
+	applicationRepo application.ApplicationRepository
+	loanUC          loan.LoanUsecase
+	creditUC        credit.CreditUsecase
+	tx              postgres.Transactor
+	approvalLimits  map[string]float64
+}
+
+// NewApplicationUseCase builds the application workflow. approvalLimits is
+// the largest principal each role may approve.
+func NewApplicationUseCase(ar application.ApplicationRepository, luc loan.LoanUsecase, cuc credit.CreditUsecase,
+	tx postgres.Transactor, approvalLimits map[string]float64) application.ApplicationUsecase {
+	return &applicationUseCase{
+		applicationRepo: ar,
+		loanUC:          luc,
+		creditUC:        cuc,
+		tx:              tx,
+		approvalLimits:  approvalLimits,
+	}
+}
+
+// Submit stores an application and runs the automated credit decision on it.
+// A decline rejects the application straight away; approve and refer leave
+// it for manual review.
+func (uc *applicationUseCase) Submit(ctx context.Context, terms models.LoanTerms, submittedBy string) (*models.LoanApplication, error) {
+	app := &models.LoanApplication{
+		BorrowerID:   terms.BorrowerID,
+		Principal:    terms.Principal,
+		InterestRate: terms.InterestRate,
+		TermWeeks:    terms.TermWeeks,
+		StartDate:    terms.StartDate,
+		ProductCode:  terms.ProductCode,
+		Status:       models.ApplicationStatusSubmitted,
+		SubmittedBy:  submittedBy,
+	}
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.applicationRepo.Create(ctx, app); err != nil {
+			return err
+		}
+		decision, err := uc.creditUC.Assess(ctx, app)
+		if err != nil {
+			return err
+		}
+		app.CreditOutcome = decision.Outcome
+		if decision.Outcome == models.CreditOutcomeDecline {
+			app.Status = models.ApplicationStatusRejected
+			app.DecidedBy = "credit:" + decision.Engine
+			app.DecisionNote = strings.Join(decision.Reasons, "; ")
+		}
+		return uc.applicationRepo.Update(ctx, app)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+func (uc *applicationUseCase) GetApplication(ctx context.Context, id int) (*models.LoanApplication, error) {
+	return uc.applicationRepo.GetByID(ctx, id)
+}
+
+func (uc *applicationUseCase) GetCreditDecisions(ctx context.Context, id int) ([]models.CreditDecision, error) {
+	if _, err := uc.applicationRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return uc.creditUC.GetDecisions(ctx, id)
+}
+
+func (uc *applicationUseCase) ListApplications(ctx context.Context, status string) ([]models.LoanApplication, error) {
+	return uc.applicationRepo.List(ctx, status)
+}
+
+// StartReview picks up a submitted application for review.
+func (uc *applicationUseCase) StartReview(ctx context.Context, id int, reviewer string) (*models.LoanApplication, error) {
+	return uc.transition(ctx, id, func(ctx context.Context, app *models.LoanApplication) error {
+		if app.Status != models.ApplicationStatusSubmitted {
+			return application.ErrStatus.Withf("application is %s, not submitted", app.Status)
+		}
+		app.Status = models.ApplicationStatusUnderReview
+		app.ReviewedBy = reviewer
+		return nil
+	})
+}
+
+// Approve books the loan for an application under review through CreateLoan.
+// The approver may not be the submitter, and the principal must be within
+// the approver's role limit.
+func (uc *applicationUseCase) Approve(ctx context.Context, id int, approver, role, note string) (*models.LoanApplication, error) {
+	return uc.transition(ctx, id, func(ctx context.Context, app *models.LoanApplication) error {
+		if app.Status != models.ApplicationStatusUnderReview {
+			return application.ErrStatus.Withf("application is %s, not under review", app.Status)
+		}
+		if app.SubmittedBy == approver {
+			return application.ErrSubmitterDecides.Withf("an application cannot be approved by its submitter")
+		}
+		limit, ok := uc.approvalLimits[role]
+		if !ok {
+			return application.ErrRoleCannotApprove.Withf("role %q cannot approve loan applications", role)
+		}
+		if app.Principal > limit {
+			return application.ErrApprovalLimitExceeded.Withf("principal %.2f exceeds the %.2f approval limit of role %q",
+				app.Principal, limit, role)
+		}
+
+		l, err := uc.loanUC.CreateLoan(ctx, app.Terms())
+		if err != nil {
+			return err
+		}
+		app.Status = models.ApplicationStatusApproved
+		app.DecidedBy = approver
+		app.DecidedRole = role
+		app.DecisionNote = note
+		app.LoanID = &l.ID
+		return nil
+	})
+}
+
+// Reject declines a submitted or under-review application. Like approval it
+// takes someone other than the submitter.
+func (uc *applicationUseCase) Reject(ctx context.Context, id int, rejecter, note string) (*models.LoanApplication, error) {
+	return uc.transition(ctx, id, func(ctx context.Context, app *models.LoanApplication) error {
+		if app.Status != models.ApplicationStatusSubmitted && app.Status != models.ApplicationStatusUnderReview {
+			return application.ErrStatus.Withf("application is already %s", app.Status)
+		}
+		if app.SubmittedBy == rejecter {
+			return application.ErrSubmitterDecides.Withf("an application cannot be rejected by its submitter")
+		}
+		app.Status = models.ApplicationStatusRejected
+		app.DecidedBy = rejecter
+		app.DecisionNote = note
+		return nil
+	})
+}
+
+// transition locks the application, applies fn and stores the result in one
+// transaction, so concurrent decisions cannot both succeed.
+func (uc *applicationUseCase) transition(ctx context.Context, id int, fn func(ctx context.Context, app *models.LoanApplication) error) (*models.LoanApplication, error) {
+	var app *models.LoanApplication
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		app, err = uc.applicationRepo.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := fn(ctx, app); err != nil {
+			return err
+		}
+		return uc.applicationRepo.Update(ctx, app)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return app, nil
+}
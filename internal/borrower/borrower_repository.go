@@ -0,0 +1,13 @@
+package borrower
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type BorrowerRepository interface {
+	GetLimit(ctx context.Context, borrowerID string) (*models.BorrowerLimit, error)
+	SaveLimit(ctx context.Context, limit *models.BorrowerLimit) error
+	DeleteLimit(ctx context.Context, borrowerID string) error
+}
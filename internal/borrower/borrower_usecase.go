@@ -0,0 +1,14 @@
+package borrower
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type BorrowerUsecase interface {
+	GetExposure(ctx context.Context, borrowerID string) (*models.BorrowerExposure, error)
+	GetLimit(ctx context.Context, borrowerID string) (*models.BorrowerLimit, error)
+	SetLimit(ctx context.Context, borrowerID, actor string, limits models.ExposureLimits) (*models.BorrowerLimit, error)
+	DeleteLimit(ctx context.Context, borrowerID string) error
+}
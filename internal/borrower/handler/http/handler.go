@@ -0,0 +1,116 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/evrintobing17/loan-billing-system/internal/borrower"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/evrintobing17/loan-billing-system/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type BorrowerHandler struct {
+	borrowerUC borrower.BorrowerUsecase
+}
+
+func NewBorrowerHandler(uc borrower.BorrowerUsecase) *BorrowerHandler {
+	return &BorrowerHandler{borrowerUC: uc}
+}
+
+// GetExposure godoc
+// @Summary Get a borrower's exposure
+// @Description Outstanding balance and loan counts across the borrower's loans, overall and per product, with the borrower-level limits in force.
+// @Tags borrowers
+// @Produce json
+// @Param borrowerId path string true "Borrower ID"
+// @Success 200 {object} models.BorrowerExposure
+// @Failure 500 {object} models.Problem
+// @Router /borrowers/{borrowerId}/exposure [get]
+func (h *BorrowerHandler) GetExposure(c *gin.Context) {
+	exposure, err := h.borrowerUC.GetExposure(c.Request.Context(), c.Param("borrowerId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, exposure)
+}
+
+// GetLimit godoc
+// @Summary Get a borrower's own limits
+// @Description Limits set for this borrower. Borrowers without their own limits use the configured defaults.
+// @Tags borrowers
+// @Produce json
+// @Param borrowerId path string true "Borrower ID"
+// @Success 200 {object} models.BorrowerLimit
+// @Failure 404 {object} models.Problem
+// @Router /borrowers/{borrowerId}/limits [get]
+func (h *BorrowerHandler) GetLimit(c *gin.Context) {
+	limit, err := h.borrowerUC.GetLimit(c.Request.Context(), c.Param("borrowerId"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, limit)
+}
+
+// SetLimit godoc
+// @Summary Set a borrower's limits
+// @Description Replace the default exposure limits for one borrower (admin only). Zero amounts mean no limit.
+// @Tags borrowers
+// @Accept json
+// @Produce json
+// @Param borrowerId path string true "Borrower ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param X-User-ID header string true "Acting user"
+// @Param request body models.ExposureLimits true "Limits"
+// @Success 200 {object} models.BorrowerLimit
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Router /borrowers/{borrowerId}/limits [put]
+func (h *BorrowerHandler) SetLimit(c *gin.Context) {
+	actor := middleware.Actor(c)
+	if actor == "" {
+		c.Error(middleware.ErrActorRequired)
+		return
+	}
+	var req models.ExposureLimits
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+	limit, err := h.borrowerUC.SetLimit(c.Request.Context(), c.Param("borrowerId"), actor, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, limit)
+}
+
+// DeleteLimit godoc
+// @Summary Remove a borrower's limits
+// @Description Put the borrower back on the configured default limits (admin only).
+// @Tags borrowers
+// @Param borrowerId path string true "Borrower ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 204
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Router /borrowers/{borrowerId}/limits [delete]
+func (h *BorrowerHandler) DeleteLimit(c *gin.Context) {
+	if err := h.borrowerUC.DeleteLimit(c.Request.Context(), c.Param("borrowerId")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func respondError(c *gin.Context, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		c.Error(apperror.NotFoundf("borrower has no limits of their own"))
+		return
+	}
+	c.Error(err)
+}
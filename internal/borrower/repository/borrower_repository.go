@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evrintobing17/loan-billing-system/internal/borrower"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+type borrowerRepository struct {
+	DB *sql.DB
+}
+
+func NewBorrowerRepository(DB *sql.DB) borrower.BorrowerRepository {
+	return &borrowerRepository{
+		DB: DB,
+	}
+}
+
+func (r *borrowerRepository) GetLimit(ctx context.Context, borrowerID string) (*models.BorrowerLimit, error) {
+	var limit models.BorrowerLimit
+	query := `SELECT borrower_id, max_outstanding, max_active_loans, block_delinquent, updated_by, updated_at
+              FROM borrower_limits WHERE borrower_id = $1`
+	err := postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, borrowerID).Scan(
+		&limit.BorrowerID,
+		&limit.MaxOutstanding,
+		&limit.MaxActiveLoans,
+		&limit.BlockDelinquent,
+		&limit.UpdatedBy,
+		&limit.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query borrower limit: %w", err)
+	}
+	return &limit, nil
+}
+
+// SaveLimit inserts or replaces the borrower's limits.
+func (r *borrowerRepository) SaveLimit(ctx context.Context, limit *models.BorrowerLimit) error {
+	query := `INSERT INTO borrower_limits (borrower_id, max_outstanding, max_active_loans, block_delinquent, updated_by)
+              VALUES ($1, $2, $3, $4, $5)
+              ON CONFLICT (borrower_id) DO UPDATE
+              SET max_outstanding = EXCLUDED.max_outstanding,
+                  max_active_loans = EXCLUDED.max_active_loans,
+                  block_delinquent = EXCLUDED.block_delinquent,
+                  updated_by = EXCLUDED.updated_by,
+                  updated_at = CURRENT_TIMESTAMP
+              RETURNING updated_at`
+	err := postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, limit.BorrowerID, limit.MaxOutstanding,
+		limit.MaxActiveLoans, limit.BlockDelinquent, limit.UpdatedBy).Scan(&limit.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("save borrower limit: %w", err)
+	}
+	return nil
+}
+
+// DeleteLimit removes the borrower's limits, returning sql.ErrNoRows when
+// there were none.
+func (r *borrowerRepository) DeleteLimit(ctx context.Context, borrowerID string) error {
+	res, err := postgres.Conn(ctx, r.DB).ExecContext(ctx, `DELETE FROM borrower_limits WHERE borrower_id = $1`, borrowerID)
+	if err != nil {
+		return fmt.Errorf("delete borrower limit: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete borrower limit: %w", err)
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/internal/borrower"
+	"github.com/evrintobing17/loan-billing-system/internal/loan"
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type borrowerUseCase struct {
+	borrowerRepo borrower.BorrowerRepository
+	loanUC       loan.LoanUsecase
+}
+
+func NewBorrowerUseCase(br borrower.BorrowerRepository, luc loan.LoanUsecase) borrower.BorrowerUsecase {
+	return &borrowerUseCase{
+		borrowerRepo: br,
+		loanUC:       luc,
+	}
+}
+
+func (uc *borrowerUseCase) GetExposure(ctx context.Context, borrowerID string) (*models.BorrowerExposure, error) {
+	return uc.loanUC.GetBorrowerExposure(ctx, borrowerID)
+}
+
+func (uc *borrowerUseCase) GetLimit(ctx context.Context, borrowerID string) (*models.BorrowerLimit, error) {
+	return uc.borrowerRepo.GetLimit(ctx, borrowerID)
+}
+
+// SetLimit replaces the default limits for the borrower. Limits only apply to
+// new loans; existing loans above them are left alone.
+func (uc *borrowerUseCase) SetLimit(ctx context.Context, borrowerID, actor string, limits models.ExposureLimits) (*models.BorrowerLimit, error) {
+	limit := &models.BorrowerLimit{
+		BorrowerID:     borrowerID,
+		ExposureLimits: limits,
+		UpdatedBy:      actor,
+	}
+	if err := uc.borrowerRepo.SaveLimit(ctx, limit); err != nil {
+		return nil, err
+	}
+	return limit, nil
+}
+
+// DeleteLimit puts the borrower back on the default limits.
+func (uc *borrowerUseCase) DeleteLimit(ctx context.Context, borrowerID string) error {
+	return uc.borrowerRepo.DeleteLimit(ctx, borrowerID)
+}
@@ -0,0 +1,21 @@
+package collateral
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type CollateralRepository interface {
+	CreateCollateral(ctx context.Context, collateral *models.Collateral) error
+	GetCollateral(ctx context.Context, loanID, id int) (*models.Collateral, error)
+	ListCollateral(ctx context.Context, loanID int) ([]models.Collateral, error)
+	UpdateCollateral(ctx context.Context, collateral *models.Collateral) error
+	DeleteCollateral(ctx context.Context, loanID, id int) error
+
+	CreateGuarantor(ctx context.Context, guarantor *models.Guarantor) error
+	GetGuarantor(ctx context.Context, loanID, id int) (*models.Guarantor, error)
+	ListGuarantors(ctx context.Context, loanID int) ([]models.Guarantor, error)
+	UpdateGuarantor(ctx context.Context, guarantor *models.Guarantor) error
+	DeleteGuarantor(ctx context.Context, loanID, id int) error
+}
@@ -0,0 +1,23 @@
+package collateral
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type CollateralUsecase interface {
+	AddCollateral(ctx context.Context, loanID int, req models.CollateralRequest) (*models.Collateral, error)
+	GetCollateral(ctx context.Context, loanID, id int) (*models.Collateral, error)
+	ListCollateral(ctx context.Context, loanID int) ([]models.Collateral, error)
+	UpdateCollateral(ctx context.Context, loanID, id int, req models.CollateralRequest) (*models.Collateral, error)
+	DeleteCollateral(ctx context.Context, loanID, id int) error
+
+	AddGuarantor(ctx context.Context, loanID int, req models.GuarantorRequest) (*models.Guarantor, error)
+	GetGuarantor(ctx context.Context, loanID, id int) (*models.Guarantor, error)
+	ListGuarantors(ctx context.Context, loanID int) ([]models.Guarantor, error)
+	UpdateGuarantor(ctx context.Context, loanID, id int, req models.GuarantorRequest) (*models.Guarantor, error)
+	DeleteGuarantor(ctx context.Context, loanID, id int) error
+
+	GetLoanToValue(ctx context.Context, loanID int) (*models.LoanToValue, error)
+}
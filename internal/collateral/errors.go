@@ -0,0 +1,8 @@
+package collateral
+
+import "github.com/evrintobing17/loan-billing-system/pkg/apperror"
+
+var (
+	ErrLienRegistered          = apperror.New(apperror.Conflict, "lien_registered", "release the lien before deleting the collateral")
+	ErrLiabilitySharesExceeded = apperror.New(apperror.Unprocessable, "liability_shares_exceeded", "liability shares would total above 100%")
+)
@@ -0,0 +1,324 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/evrintobing17/loan-billing-system/internal/collateral"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+type CollateralHandler struct {
+	collateralUC collateral.CollateralUsecase
+}
+
+func NewCollateralHandler(uc collateral.CollateralUsecase) *CollateralHandler {
+	return &CollateralHandler{collateralUC: uc}
+}
+
+// AddCollateral godoc
+// @Summary Register collateral for a loan
+// @Description Record a collateral item with its valuation and lien status (admin only).
+// @Tags collateral
+// @Accept json
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param request body models.CollateralRequest true "Collateral details"
+// @Success 201 {object} models.Collateral
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Router /loans/{id}/collateral [post]
+func (h *CollateralHandler) AddCollateral(c *gin.Context) {
+	loanID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	var req models.CollateralRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+	item, err := h.collateralUC.AddCollateral(c.Request.Context(), loanID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, item)
+}
+
+// ListCollateral godoc
+// @Summary List collateral of a loan
+// @Tags collateral
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Success 200 {array} models.Collateral
+// @Failure 404 {object} models.Problem
+// @Router /loans/{id}/collateral [get]
+func (h *CollateralHandler) ListCollateral(c *gin.Context) {
+	loanID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	items, err := h.collateralUC.ListCollateral(c.Request.Context(), loanID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// GetCollateral godoc
+// @Summary Get a collateral item
+// @Tags collateral
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Param collateralId path int true "Collateral ID"
+// @Success 200 {object} models.Collateral
+// @Failure 404 {object} models.Problem
+// @Router /loans/{id}/collateral/{collateralId} [get]
+func (h *CollateralHandler) GetCollateral(c *gin.Context) {
+	loanID, id, ok := pathIDs(c, "collateralId")
+	if !ok {
+		return
+	}
+	item, err := h.collateralUC.GetCollateral(c.Request.Context(), loanID, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// UpdateCollateral godoc
+// @Summary Update a collateral item
+// @Description Replace the details of a collateral item, e.g. after revaluation or lien release (admin only).
+// @Tags collateral
+// @Accept json
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Param collateralId path int true "Collateral ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param request body models.CollateralRequest true "Collateral details"
+// @Success 200 {object} models.Collateral
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Router /loans/{id}/collateral/{collateralId} [put]
+func (h *CollateralHandler) UpdateCollateral(c *gin.Context) {
+	loanID, id, ok := pathIDs(c, "collateralId")
+	if !ok {
+		return
+	}
+	var req models.CollateralRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+	item, err := h.collateralUC.UpdateCollateral(c.Request.Context(), loanID, id, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// DeleteCollateral godoc
+// @Summary Delete a collateral item
+// @Description Remove a collateral item whose lien is not registered (admin only).
+// @Tags collateral
+// @Param id path int true "Loan ID"
+// @Param collateralId path int true "Collateral ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 204
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Router /loans/{id}/collateral/{collateralId} [delete]
+func (h *CollateralHandler) DeleteCollateral(c *gin.Context) {
+	loanID, id, ok := pathIDs(c, "collateralId")
+	if !ok {
+		return
+	}
+	if err := h.collateralUC.DeleteCollateral(c.Request.Context(), loanID, id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AddGuarantor godoc
+// @Summary Add a guarantor or co-borrower to a loan
+// @Description Liability shares of all guarantors and co-borrowers of a loan may not exceed 100 percent (admin only).
+// @Tags guarantors
+// @Accept json
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param request body models.GuarantorRequest true "Guarantor details"
+// @Success 201 {object} models.Guarantor
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Router /loans/{id}/guarantors [post]
+func (h *CollateralHandler) AddGuarantor(c *gin.Context) {
+	loanID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	var req models.GuarantorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+	g, err := h.collateralUC.AddGuarantor(c.Request.Context(), loanID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, g)
+}
+
+// ListGuarantors godoc
+// @Summary List guarantors and co-borrowers of a loan
+// @Tags guarantors
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Success 200 {array} models.Guarantor
+// @Failure 404 {object} models.Problem
+// @Router /loans/{id}/guarantors [get]
+func (h *CollateralHandler) ListGuarantors(c *gin.Context) {
+	loanID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	guarantors, err := h.collateralUC.ListGuarantors(c.Request.Context(), loanID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, guarantors)
+}
+
+// GetGuarantor godoc
+// @Summary Get a guarantor or co-borrower
+// @Tags guarantors
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Param guarantorId path int true "Guarantor ID"
+// @Success 200 {object} models.Guarantor
+// @Failure 404 {object} models.Problem
+// @Router /loans/{id}/guarantors/{guarantorId} [get]
+func (h *CollateralHandler) GetGuarantor(c *gin.Context) {
+	loanID, id, ok := pathIDs(c, "guarantorId")
+	if !ok {
+		return
+	}
+	g, err := h.collateralUC.GetGuarantor(c.Request.Context(), loanID, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, g)
+}
+
+// UpdateGuarantor godoc
+// @Summary Update a guarantor or co-borrower
+// @Description Replace the details of a guarantor or co-borrower (admin only).
+// @Tags guarantors
+// @Accept json
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Param guarantorId path int true "Guarantor ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param request body models.GuarantorRequest true "Guarantor details"
+// @Success 200 {object} models.Guarantor
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Router /loans/{id}/guarantors/{guarantorId} [put]
+func (h *CollateralHandler) UpdateGuarantor(c *gin.Context) {
+	loanID, id, ok := pathIDs(c, "guarantorId")
+	if !ok {
+		return
+	}
+	var req models.GuarantorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+	g, err := h.collateralUC.UpdateGuarantor(c.Request.Context(), loanID, id, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, g)
+}
+
+// DeleteGuarantor godoc
+// @Summary Remove a guarantor or co-borrower
+// @Tags guarantors
+// @Param id path int true "Loan ID"
+// @Param guarantorId path int true "Guarantor ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 204
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Router /loans/{id}/guarantors/{guarantorId} [delete]
+func (h *CollateralHandler) DeleteGuarantor(c *gin.Context) {
+	loanID, id, ok := pathIDs(c, "guarantorId")
+	if !ok {
+		return
+	}
+	if err := h.collateralUC.DeleteGuarantor(c.Request.Context(), loanID, id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetLoanToValue godoc
+// @Summary Get the loan-to-value ratio of a loan
+// @Description Compare the principal and the outstanding balance with the collateral whose lien has not been released.
+// @Tags collateral
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Success 200 {object} models.LoanToValue
+// @Failure 404 {object} models.Problem
+// @Router /loans/{id}/ltv [get]
+func (h *CollateralHandler) GetLoanToValue(c *gin.Context) {
+	loanID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	ltv, err := h.collateralUC.GetLoanToValue(c.Request.Context(), loanID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, ltv)
+}
+
+func pathID(c *gin.Context, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid %s", name))
+		return 0, false
+	}
+	return id, true
+}
+
+func pathIDs(c *gin.Context, name string) (int, int, bool) {
+	loanID, ok := pathID(c, "id")
+	if !ok {
+		return 0, 0, false
+	}
+	id, ok := pathID(c, name)
+	return loanID, id, ok
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evrintobing17/loan-billing-system/internal/collateral"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+type collateralRepository struct {
+	DB *sql.DB
+}
+
+func NewCollateralRepository(DB *sql.DB) collateral.CollateralRepository {
+	return &collateralRepository{
+		DB: DB,
+	}
+}
+
+const collateralColumns = `id, loan_id, collateral_type, description, valuation, valuation_date, lien_status, created_at, updated_at`
+
+func scanCollateral(row interface{ Scan(...any) error }, c *models.Collateral) error {
+	return row.Scan(
+		&c.ID,
+		&c.LoanID,
+		&c.CollateralType,
+		&c.Description,
+		&c.Valuation,
+		&c.ValuationDate,
+		&c.LienStatus,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+}
+
+// CreateCollateral implements [collateral.CollateralRepository].
+func (r *collateralRepository) CreateCollateral(ctx context.Context, c *models.Collateral) error {
+	query := `INSERT INTO collateral (loan_id, collateral_type, description, valuation, valuation_date, lien_status)
+              VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at, updated_at`
+	return postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, c.LoanID, c.CollateralType, c.Description,
+		c.Valuation, c.ValuationDate, c.LienStatus).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+}
+
+func (r *collateralRepository) GetCollateral(ctx context.Context, loanID, id int) (*models.Collateral, error) {
+	var c models.Collateral
+	query := `SELECT ` + collateralColumns + ` FROM collateral WHERE loan_id = $1 AND id = $2`
+	err := scanCollateral(postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, loanID, id), &c)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query collateral: %w", err)
+	}
+	return &c, nil
+}
+
+func (r *collateralRepository) ListCollateral(ctx context.Context, loanID int) ([]models.Collateral, error) {
+	query := `SELECT ` + collateralColumns + ` FROM collateral WHERE loan_id = $1 ORDER BY id`
+	rows, err := postgres.Conn(ctx, r.DB).QueryContext(ctx, query, loanID)
+	if err != nil {
+		return nil, fmt.Errorf("query collateral: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.Collateral
+	for rows.Next() {
+		var c models.Collateral
+		if err := scanCollateral(rows, &c); err != nil {
+			return nil, fmt.Errorf("scan collateral: %w", err)
+		}
+		items = append(items, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return items, nil
+}
+
+// UpdateCollateral implements [collateral.CollateralRepository].
+func (r *collateralRepository) UpdateCollateral(ctx context.Context, c *models.Collateral) error {
+	query := `UPDATE collateral
+              SET collateral_type = $3, description = $4, valuation = $5, valuation_date = $6, lien_status = $7,
+                  updated_at = CURRENT_TIMESTAMP
+              WHERE loan_id = $1 AND id = $2
+              RETURNING updated_at`
+	return postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, c.LoanID, c.ID, c.CollateralType, c.Description,
+		c.Valuation, c.ValuationDate, c.LienStatus).Scan(&c.UpdatedAt)
+}
+
+func (r *collateralRepository) DeleteCollateral(ctx context.Context, loanID, id int) error {
+	return deleteRow(ctx, postgres.Conn(ctx, r.DB), `DELETE FROM collateral WHERE loan_id = $1 AND id = $2`, loanID, id)
+}
+
+const guarantorColumns = `id, loan_id, name, role, id_number, phone, liability_share, created_at, updated_at`
+
+func scanGuarantor(row interface{ Scan(...any) error }, g *models.Guarantor) error {
+	return row.Scan(
+		&g.ID,
+		&g.LoanID,
+		&g.Name,
+		&g.Role,
+		&g.IDNumber,
+		&g.Phone,
+		&g.LiabilityShare,
+		&g.CreatedAt,
+		&g.UpdatedAt,
+	)
+}
+
+// CreateGuarantor implements [collateral.CollateralRepository].
+func (r *collateralRepository) CreateGuarantor(ctx context.Context, g *models.Guarantor) error {
+	query := `INSERT INTO guarantors (loan_id, name, role, id_number, phone, liability_share)
+              VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at, updated_at`
+	return postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, g.LoanID, g.Name, g.Role, g.IDNumber,
+		g.Phone, g.LiabilityShare).Scan(&g.ID, &g.CreatedAt, &g.UpdatedAt)
+}
+
+func (r *collateralRepository) GetGuarantor(ctx context.Context, loanID, id int) (*models.Guarantor, error) {
+	var g models.Guarantor
+	query := `SELECT ` + guarantorColumns + ` FROM guarantors WHERE loan_id = $1 AND id = $2`
+	err := scanGuarantor(postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, loanID, id), &g)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query guarantor: %w", err)
+	}
+	return &g, nil
+}
+
+func (r *collateralRepository) ListGuarantors(ctx context.Context, loanID int) ([]models.Guarantor, error) {
+	query := `SELECT ` + guarantorColumns + ` FROM guarantors WHERE loan_id = $1 ORDER BY id`
+	rows, err := postgres.Conn(ctx, r.DB).QueryContext(ctx, query, loanID)
+	if err != nil {
+		return nil, fmt.Errorf("query guarantors: %w", err)
+	}
+	defer rows.Close()
+
+	var guarantors []models.Guarantor
+	for rows.Next() {
+		var g models.Guarantor
+		if err := scanGuarantor(rows, &g); err != nil {
+			return nil, fmt.Errorf("scan guarantor: %w", err)
+		}
+		guarantors = append(guarantors, g)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return guarantors, nil
+}
+
+// UpdateGuarantor implements [collateral.CollateralRepository].
+func (r *collateralRepository) UpdateGuarantor(ctx context.Context, g *models.Guarantor) error {
+	query := `UPDATE guarantors
+              SET name = $3, role = $4, id_number = $5, phone = $6, liability_share = $7, updated_at = CURRENT_TIMESTAMP
+              WHERE loan_id = $1 AND id = $2
+              RETURNING updated_at`
+	return postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, g.LoanID, g.ID, g.Name, g.Role, g.IDNumber,
+		g.Phone, g.LiabilityShare).Scan(&g.UpdatedAt)
+}
+
+func (r *collateralRepository) DeleteGuarantor(ctx context.Context, loanID, id int) error {
+	return deleteRow(ctx, postgres.Conn(ctx, r.DB), `DELETE FROM guarantors WHERE loan_id = $1 AND id = $2`, loanID, id)
+}
+
+// deleteRow runs a delete and reports sql.ErrNoRows when nothing matched.
+func deleteRow(ctx context.Context, db postgres.DBTX, query string, args ...any) error {
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/collateral"
+	"github.com/evrintobing17/loan-billing-system/internal/loan"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+type collateralUseCase struct {
+	collateralRepo collateral.CollateralRepository
+	loanRepo       loan.LoanRepository
+	loanUC         loan.LoanUsecase
+	tx             postgres.Transactor
+}
+
+func NewCollateralUseCase(cr collateral.CollateralRepository, lr loan.LoanRepository, luc loan.LoanUsecase, tx postgres.Transactor) collateral.CollateralUsecase {
+	return &collateralUseCase{
+		collateralRepo: cr,
+		loanRepo:       lr,
+		loanUC:         luc,
+		tx:             tx,
+	}
+}
+
+func (uc *collateralUseCase) AddCollateral(ctx context.Context, loanID int, req models.CollateralRequest) (*models.Collateral, error) {
+	if _, err := uc.loanRepo.GetByID(ctx, loanID); err != nil {
+		return nil, err
+	}
+	c := &models.Collateral{LoanID: loanID}
+	if err := applyCollateral(c, req); err != nil {
+		return nil, err
+	}
+	if err := uc.collateralRepo.CreateCollateral(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (uc *collateralUseCase) GetCollateral(ctx context.Context, loanID, id int) (*models.Collateral, error) {
+	return uc.collateralRepo.GetCollateral(ctx, loanID, id)
+}
+
+func (uc *collateralUseCase) ListCollateral(ctx context.Context, loanID int) ([]models.Collateral, error) {
+	if _, err := uc.loanRepo.GetByID(ctx, loanID); err != nil {
+		return nil, err
+	}
+	return uc.collateralRepo.ListCollateral(ctx, loanID)
+}
+
+func (uc *collateralUseCase) UpdateCollateral(ctx context.Context, loanID, id int, req models.CollateralRequest) (*models.Collateral, error) {
+	c, err := uc.collateralRepo.GetCollateral(ctx, loanID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyCollateral(c, req); err != nil {
+		return nil, err
+	}
+	if err := uc.collateralRepo.UpdateCollateral(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DeleteCollateral removes a collateral record. A registered lien has to be
+// released first so the registry never drops live security.
+func (uc *collateralUseCase) DeleteCollateral(ctx context.Context, loanID, id int) error {
+	c, err := uc.collateralRepo.GetCollateral(ctx, loanID, id)
+	if err != nil {
+		return err
+	}
+	if c.LienStatus == models.LienStatusRegistered {
+		return collateral.ErrLienRegistered
+	}
+	return uc.collateralRepo.DeleteCollateral(ctx, loanID, id)
+}
+
+func (uc *collateralUseCase) AddGuarantor(ctx context.Context, loanID int, req models.GuarantorRequest) (*models.Guarantor, error) {
+	if _, err := uc.loanRepo.GetByID(ctx, loanID); err != nil {
+		return nil, err
+	}
+	g := &models.Guarantor{LoanID: loanID}
+	applyGuarantor(g, req)
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.checkLiabilityShares(ctx, g); err != nil {
+			return err
+		}
+		return uc.collateralRepo.CreateGuarantor(ctx, g)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (uc *collateralUseCase) GetGuarantor(ctx context.Context, loanID, id int) (*models.Guarantor, error) {
+	return uc.collateralRepo.GetGuarantor(ctx, loanID, id)
+}
+
+func (uc *collateralUseCase) ListGuarantors(ctx context.Context, loanID int) ([]models.Guarantor, error) {
+	if _, err := uc.loanRepo.GetByID(ctx, loanID); err != nil {
+		return nil, err
+	}
+	return uc.collateralRepo.ListGuarantors(ctx, loanID)
+}
+
+func (uc *collateralUseCase) UpdateGuarantor(ctx context.Context, loanID, id int, req models.GuarantorRequest) (*models.Guarantor, error) {
+	g, err := uc.collateralRepo.GetGuarantor(ctx, loanID, id)
+	if err != nil {
+		return nil, err
+	}
+	applyGuarantor(g, req)
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.checkLiabilityShares(ctx, g); err != nil {
+			return err
+		}
+		return uc.collateralRepo.UpdateGuarantor(ctx, g)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (uc *collateralUseCase) DeleteGuarantor(ctx context.Context, loanID, id int) error {
+	return uc.collateralRepo.DeleteGuarantor(ctx, loanID, id)
+}
+
+// GetLoanToValue compares the principal and the outstanding balance with the
+// value of the collateral whose lien has not been released.
+func (uc *collateralUseCase) GetLoanToValue(ctx context.Context, loanID int) (*models.LoanToValue, error) {
+	l, err := uc.loanRepo.GetByID(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	outstanding, err := uc.loanUC.GetOutstanding(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := uc.collateralRepo.ListCollateral(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	ltv := &models.LoanToValue{
+		LoanID:      loanID,
+		Principal:   l.Principal,
+		Outstanding: outstanding,
+	}
+	for _, c := range items {
+		if c.LienStatus != models.LienStatusReleased {
+			ltv.CollateralValue += c.Valuation
+		}
+	}
+	if ltv.CollateralValue > 0 {
+		ltv.OriginalLTV = percent(l.Principal, ltv.CollateralValue)
+		ltv.CurrentLTV = percent(outstanding, ltv.CollateralValue)
+	}
+	return ltv, nil
+}
+
+// checkLiabilityShares makes sure the shares of the loan's guarantors and
+// co-borrowers, including g, add up to at most 100 percent.
+func (uc *collateralUseCase) checkLiabilityShares(ctx context.Context, g *models.Guarantor) error {
+	existing, err := uc.collateralRepo.ListGuarantors(ctx, g.LoanID)
+	if err != nil {
+		return err
+	}
+	total := g.LiabilityShare
+	for _, other := range existing {
+		if other.ID != g.ID {
+			total += other.LiabilityShare
+		}
+	}
+	if total > 100.005 {
+		return collateral.ErrLiabilitySharesExceeded.Withf("liability shares would total %.2f%%, above 100%%", total)
+	}
+	return nil
+}
+
+func applyCollateral(c *models.Collateral, req models.CollateralRequest) error {
+	valuationDate, err := time.Parse("2006-01-02", req.ValuationDate)
+	if err != nil {
+		return apperror.Invalidf("invalid valuation_date format, use YYYY-MM-DD")
+	}
+	c.CollateralType = req.CollateralType
+	c.Description = req.Description
+	c.Valuation = req.Valuation
+	c.ValuationDate = valuationDate
+	c.LienStatus = req.LienStatus
+	return nil
+}
+
+func applyGuarantor(g *models.Guarantor, req models.GuarantorRequest) {
+	g.Name = req.Name
+	g.Role = req.Role
+	g.IDNumber = req.IDNumber
+	g.Phone = req.Phone
+	g.LiabilityShare = req.LiabilityShare
+}
+
+func percent(amount, base float64) float64 {
+	return math.Round(amount/base*10000) / 100
+}
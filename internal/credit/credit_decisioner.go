@@ -0,0 +1,16 @@
+package credit
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// CreditDecisioner decides on a loan application from its credit inputs.
+// Implementations may be the built-in rules engine or an external scoring
+// model; they return an outcome and the reasons behind it.
+type CreditDecisioner interface {
+	// Name identifies the engine on stored decisions.
+	Name() string
+	Decide(ctx context.Context, inputs models.CreditInputs) (outcome string, reasons []string, err error)
+}
@@ -0,0 +1,12 @@
+package credit
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type CreditRepository interface {
+	Create(ctx context.Context, decision *models.CreditDecision) error
+	GetByApplicationID(ctx context.Context, applicationID int) ([]models.CreditDecision, error)
+}
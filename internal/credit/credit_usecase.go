@@ -0,0 +1,14 @@
+package credit
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type CreditUsecase interface {
+	// Assess gathers the credit inputs for an application, runs the
+	// decisioner and stores the decision.
+	Assess(ctx context.Context, app *models.LoanApplication) (*models.CreditDecision, error)
+	GetDecisions(ctx context.Context, applicationID int) ([]models.CreditDecision, error)
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/evrintobing17/loan-billing-system/internal/credit"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+type creditRepository struct {
+	DB *sql.DB
+}
+
+func NewCreditRepository(DB *sql.DB) credit.CreditRepository {
+	return &creditRepository{
+		DB: DB,
+	}
+}
+
+// Create implements [credit.CreditRepository].
+func (r *creditRepository) Create(ctx context.Context, d *models.CreditDecision) error {
+	inputs, err := json.Marshal(d.Inputs)
+	if err != nil {
+		return fmt.Errorf("marshal credit inputs: %w", err)
+	}
+	if d.Reasons == nil {
+		d.Reasons = []string{}
+	}
+	reasons, err := json.Marshal(d.Reasons)
+	if err != nil {
+		return fmt.Errorf("marshal credit reasons: %w", err)
+	}
+	query := `INSERT INTO credit_decisions (application_id, engine, outcome, inputs, reasons)
+              VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	return postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, d.ApplicationID, d.Engine, d.Outcome, inputs, reasons).
+		Scan(&d.ID, &d.CreatedAt)
+}
+
+func (r *creditRepository) GetByApplicationID(ctx context.Context, applicationID int) ([]models.CreditDecision, error) {
+	query := `SELECT id, application_id, engine, outcome, inputs, reasons, created_at
+              FROM credit_decisions
+              WHERE application_id = $1
+              ORDER BY id`
+	rows, err := postgres.Conn(ctx, r.DB).QueryContext(ctx, query, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("query credit decisions: %w", err)
+	}
+	defer rows.Close()
+
+	var decisions []models.CreditDecision
+	for rows.Next() {
+		var d models.CreditDecision
+		var inputs, reasons []byte
+		if err := rows.Scan(&d.ID, &d.ApplicationID, &d.Engine, &d.Outcome, &inputs, &reasons, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan credit decision: %w", err)
+		}
+		if err := json.Unmarshal(inputs, &d.Inputs); err != nil {
+			return nil, fmt.Errorf("unmarshal credit inputs: %w", err)
+		}
+		if err := json.Unmarshal(reasons, &d.Reasons); err != nil {
+			return nil, fmt.Errorf("unmarshal credit reasons: %w", err)
+		}
+		decisions = append(decisions, d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return decisions, nil
+}
@@ -0,0 +1,84 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evrintobing17/loan-billing-system/internal/credit"
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// Config holds the thresholds of the built-in rules engine. Zero values
+// disable the corresponding rule.
+type Config struct {
+	MaxPrincipal float64
+	MinTermWeeks int
+	MaxTermWeeks int
+	// MaxExposure caps the borrower's existing exposure plus the new principal.
+	MaxExposure float64
+	// DeclineDelinquent declines borrowers with a delinquent loan.
+	DeclineDelinquent bool
+	// MaxDaysPastDue declines borrowers with any loan further behind.
+	MaxDaysPastDue int
+	// MaxDaysPaidLate refers borrowers who once paid an installment later
+	// than this.
+	MaxDaysPaidLate int
+	// DeclineWrittenOff declines borrowers with a written-off loan.
+	DeclineWrittenOff bool
+	// ReferRestructured sends borrowers with a restructured loan to manual review.
+	ReferRestructured bool
+}
+
+type engine struct {
+	cfg Config
+}
+
+// NewEngine returns the built-in rules engine. Every failed rule adds a
+// reason; the outcome is the most severe of them.
+func NewEngine(cfg Config) credit.CreditDecisioner {
+	return &engine{cfg: cfg}
+}
+
+func (e *engine) Name() string {
+	return "rules"
+}
+
+func (e *engine) Decide(ctx context.Context, in models.CreditInputs) (string, []string, error) {
+	outcome := models.CreditOutcomeApprove
+	var reasons []string
+	fail := func(result, reason string) {
+		reasons = append(reasons, reason)
+		if result == models.CreditOutcomeDecline || outcome == models.CreditOutcomeApprove {
+			outcome = result
+		}
+	}
+
+	if e.cfg.MaxPrincipal > 0 && in.Principal > e.cfg.MaxPrincipal {
+		fail(models.CreditOutcomeDecline, fmt.Sprintf("principal %.2f above maximum %.2f", in.Principal, e.cfg.MaxPrincipal))
+	}
+	if e.cfg.MinTermWeeks > 0 && in.TermWeeks < e.cfg.MinTermWeeks {
+		fail(models.CreditOutcomeRefer, fmt.Sprintf("term %d weeks below minimum %d", in.TermWeeks, e.cfg.MinTermWeeks))
+	}
+	if e.cfg.MaxTermWeeks > 0 && in.TermWeeks > e.cfg.MaxTermWeeks {
+		fail(models.CreditOutcomeRefer, fmt.Sprintf("term %d weeks above maximum %d", in.TermWeeks, e.cfg.MaxTermWeeks))
+	}
+	if total := in.ExistingExposure + in.Principal; e.cfg.MaxExposure > 0 && total > e.cfg.MaxExposure {
+		fail(models.CreditOutcomeDecline, fmt.Sprintf("total exposure %.2f above maximum %.2f", total, e.cfg.MaxExposure))
+	}
+	if e.cfg.DeclineDelinquent && in.DelinquentLoans > 0 {
+		fail(models.CreditOutcomeDecline, fmt.Sprintf("%d delinquent loan(s)", in.DelinquentLoans))
+	}
+	if e.cfg.MaxDaysPastDue > 0 && in.MaxDaysPastDue > e.cfg.MaxDaysPastDue {
+		fail(models.CreditOutcomeDecline, fmt.Sprintf("%d days past due above maximum %d", in.MaxDaysPastDue, e.cfg.MaxDaysPastDue))
+	}
+	if e.cfg.MaxDaysPaidLate > 0 && in.MaxDaysPaidLate > e.cfg.MaxDaysPaidLate {
+		fail(models.CreditOutcomeRefer, fmt.Sprintf("paid %d days late, above maximum %d", in.MaxDaysPaidLate, e.cfg.MaxDaysPaidLate))
+	}
+	if e.cfg.DeclineWrittenOff && in.WrittenOffLoans > 0 {
+		fail(models.CreditOutcomeDecline, fmt.Sprintf("%d written-off loan(s)", in.WrittenOffLoans))
+	}
+	if e.cfg.ReferRestructured && in.RestructuredLoans > 0 {
+		fail(models.CreditOutcomeRefer, fmt.Sprintf("%d restructured loan(s)", in.RestructuredLoans))
+	}
+	return outcome, reasons, nil
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/internal/credit"
+	"github.com/evrintobing17/loan-billing-system/internal/loan"
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type creditUseCase struct {
+	creditRepo credit.CreditRepository
+	loanUC     loan.LoanUsecase
+	decisioner credit.CreditDecisioner
+}
+
+func NewCreditUseCase(cr credit.CreditRepository, luc loan.LoanUsecase, decisioner credit.CreditDecisioner) credit.CreditUsecase {
+	return &creditUseCase{
+		creditRepo: cr,
+		loanUC:     luc,
+		decisioner: decisioner,
+	}
+}
+
+func (uc *creditUseCase) Assess(ctx context.Context, app *models.LoanApplication) (*models.CreditDecision, error) {
+	inputs, err := uc.inputs(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	outcome, reasons, err := uc.decisioner.Decide(ctx, *inputs)
+	if err != nil {
+		return nil, err
+	}
+	decision := &models.CreditDecision{
+		ApplicationID: app.ID,
+		Engine:        uc.decisioner.Name(),
+		Outcome:       outcome,
+		Inputs:        *inputs,
+		Reasons:       reasons,
+	}
+	if err := uc.creditRepo.Create(ctx, decision); err != nil {
+		return nil, err
+	}
+	return decision, nil
+}
+
+func (uc *creditUseCase) GetDecisions(ctx context.Context, applicationID int) ([]models.CreditDecision, error) {
+	return uc.creditRepo.GetByApplicationID(ctx, applicationID)
+}
+
+// inputs collects the requested terms and the borrower's exposure and
+// delinquency history from their existing loans.
+func (uc *creditUseCase) inputs(ctx context.Context, app *models.LoanApplication) (*models.CreditInputs, error) {
+	in := &models.CreditInputs{
+		BorrowerID:   app.BorrowerID,
+		Principal:    app.Principal,
+		InterestRate: app.InterestRate,
+		TermWeeks:    app.TermWeeks,
+		ProductCode:  app.ProductCode,
+	}
+	if app.BorrowerID == "" {
+		return in, nil
+	}
+
+	exposure, err := uc.loanUC.GetBorrowerExposure(ctx, app.BorrowerID)
+	if err != nil {
+		return nil, err
+	}
+	in.ExistingExposure = exposure.Outstanding
+	in.ActiveLoans = exposure.ActiveLoans
+	in.DelinquentLoans = exposure.DelinquentLoans
+	in.MaxDaysPastDue = exposure.MaxDaysPastDue
+	in.MaxDaysPaidLate = exposure.MaxDaysPaidLate
+	in.RestructuredLoans = exposure.RestructuredLoans
+	in.WrittenOffLoans = exposure.WrittenOffLoans
+	return in, nil
+}
@@ -0,0 +1,35 @@
+package directdebit
+
+import (
+	"context"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type DirectDebitRepository interface {
+	// CreateMandate returns sql.ErrNoRows when the reference is taken or the
+	// loan has a mandate in force.
+	CreateMandate(ctx context.Context, m *models.Mandate) error
+	GetMandate(ctx context.Context, id int) (*models.Mandate, error)
+	ListMandatesByLoan(ctx context.Context, loanID int) ([]models.Mandate, error)
+	ListMandatesByStatus(ctx context.Context, status string) ([]models.Mandate, error)
+	// ListDunning returns mandates in dunning or suspended, highest level first.
+	ListDunning(ctx context.Context) ([]models.Mandate, error)
+	UpdateMandate(ctx context.Context, m *models.Mandate) error
+
+	// CreateRun returns sql.ErrNoRows when the date has a run already.
+	CreateRun(ctx context.Context, run *models.CollectionRun) error
+	GetRun(ctx context.Context, id int) (*models.CollectionRun, error)
+	UpdateRun(ctx context.Context, run *models.CollectionRun) error
+	MarkExported(ctx context.Context, runID int, at time.Time) error
+
+	CreateInstruction(ctx context.Context, in *models.CollectionInstruction) error
+	GetInstructionByEndToEndID(ctx context.Context, endToEndID string) (*models.CollectionInstruction, error)
+	// LatestInstruction returns the mandate's most recent instruction, or
+	// sql.ErrNoRows if it has none.
+	LatestInstruction(ctx context.Context, mandateID int) (*models.CollectionInstruction, error)
+	// UpdateInstruction returns sql.ErrNoRows unless the instruction is in
+	// one of fromStatuses.
+	UpdateInstruction(ctx context.Context, in *models.CollectionInstruction, fromStatuses ...string) error
+}
@@ -0,0 +1,33 @@
+package directdebit
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// DirectDebitUsecase manages mandates and collects the amounts due on loans
+// by direct debit.
+type DirectDebitUsecase interface {
+	CreateMandate(ctx context.Context, loanID int, req models.CreateMandateRequest, createdBy string) (*models.Mandate, error)
+	ListMandates(ctx context.Context, loanID int) ([]models.Mandate, error)
+	CancelMandate(ctx context.Context, id int, reason string) (*models.Mandate, error)
+	// ReactivateMandate resumes collecting on a suspended mandate and clears
+	// its dunning level.
+	ReactivateMandate(ctx context.Context, id int) (*models.Mandate, error)
+	ListDunning(ctx context.Context) ([]models.Mandate, error)
+
+	// CreateRun creates an instruction for every active mandate whose loan
+	// has an installment due on the collection date, or a failed collection
+	// to retry then. A zero date means the business date.
+	CreateRun(ctx context.Context, collectionDate time.Time, createdBy string) (*models.CollectionRun, error)
+	GetRun(ctx context.Context, id int) (*models.CollectionRun, error)
+	// ExportRun writes the run's open instructions as a csv or pain008 file
+	// for the bank.
+	ExportRun(ctx context.Context, id int, format string, w io.Writer) error
+	// ImportResults applies the bank's result file: collections are posted
+	// as payments and failures are retried or escalated.
+	ImportResults(ctx context.Context, id int, file io.Reader) (*models.CollectionResults, error)
+}
@@ -0,0 +1,14 @@
+package directdebit
+
+import "github.com/evrintobing17/loan-billing-system/pkg/apperror"
+
+var (
+	ErrMandateNotFound   = apperror.New(apperror.NotFound, "mandate_not_found", "mandate not found")
+	ErrMandateExists     = apperror.New(apperror.Conflict, "mandate_exists", "loan already has a mandate in force")
+	ErrMandateReference  = apperror.New(apperror.Conflict, "mandate_reference_taken", "mandate reference is already in use")
+	ErrMandateStatus     = apperror.New(apperror.Conflict, "mandate_status", "mandate is not in a state that allows this")
+	ErrRunNotFound       = apperror.New(apperror.NotFound, "collection_run_not_found", "collection run not found")
+	ErrRunExists         = apperror.New(apperror.Conflict, "collection_run_exists", "a collection run exists for this date")
+	ErrUnknownFileFormat = apperror.New(apperror.Invalid, "unknown_file_format", "collection file format must be csv or pain008")
+	ErrInvalidResultFile = apperror.New(apperror.Invalid, "invalid_result_file", "collection result file cannot be read")
+)
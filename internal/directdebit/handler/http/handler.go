@@ -0,0 +1,333 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/directdebit"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	ddfile "github.com/evrintobing17/loan-billing-system/pkg/directdebit"
+	"github.com/evrintobing17/loan-billing-system/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type DirectDebitHandler struct {
+	directDebitUC directdebit.DirectDebitUsecase
+}
+
+func NewDirectDebitHandler(uc directdebit.DirectDebitUsecase) *DirectDebitHandler {
+	return &DirectDebitHandler{directDebitUC: uc}
+}
+
+// CreateMandate godoc
+// @Summary Register a direct debit mandate (admin)
+// @Description Records the borrower's authorisation to debit their bank account for the loan. A loan has at most one mandate in force; cancel it to switch accounts.
+// @Tags direct-debit
+// @Accept json
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Param request body models.CreateMandateRequest true "Mandate"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param X-User-ID header string true "Requesting user"
+// @Success 201 {object} models.Mandate
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /loans/{id}/mandates [post]
+func (h *DirectDebitHandler) CreateMandate(c *gin.Context) {
+	actor := middleware.Actor(c)
+	if actor == "" {
+		c.Error(middleware.ErrActorRequired)
+		return
+	}
+	loanID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid loan id"))
+		return
+	}
+
+	var req models.CreateMandateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+
+	m, err := h.directDebitUC.CreateMandate(c.Request.Context(), loanID, req, actor)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, m)
+}
+
+// ListMandates godoc
+// @Summary List the mandates of a loan (admin)
+// @Description Every mandate the loan has had, cancelled ones included.
+// @Tags direct-debit
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {array} models.Mandate
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /loans/{id}/mandates [get]
+func (h *DirectDebitHandler) ListMandates(c *gin.Context) {
+	loanID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid loan id"))
+		return
+	}
+
+	mandates, err := h.directDebitUC.ListMandates(c.Request.Context(), loanID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, mandates)
+}
+
+// CancelMandate godoc
+// @Summary Cancel a mandate (admin)
+// @Description The loan is no longer collected by direct debit. Instructions already sent to the bank are not recalled.
+// @Tags direct-debit
+// @Accept json
+// @Produce json
+// @Param id path int true "Mandate ID"
+// @Param request body models.CancelMandateRequest true "Cancellation reason"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.Mandate
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /mandates/{id}/cancel [post]
+func (h *DirectDebitHandler) CancelMandate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid mandate id"))
+		return
+	}
+
+	var req models.CancelMandateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+
+	m, err := h.directDebitUC.CancelMandate(c.Request.Context(), id, req.Reason)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, m)
+}
+
+// ReactivateMandate godoc
+// @Summary Reactivate a suspended mandate (admin)
+// @Description Resumes collection, e.g. after the borrower fixed their account, and clears the dunning level.
+// @Tags direct-debit
+// @Produce json
+// @Param id path int true "Mandate ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.Mandate
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /mandates/{id}/reactivate [post]
+func (h *DirectDebitHandler) ReactivateMandate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid mandate id"))
+		return
+	}
+
+	m, err := h.directDebitUC.ReactivateMandate(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, m)
+}
+
+// ListDunning godoc
+// @Summary List mandates in dunning (admin)
+// @Description Mandates with collections that failed for good, and suspended mandates, highest dunning level first.
+// @Tags direct-debit
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {array} models.Mandate
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /direct-debit/dunning [get]
+func (h *DirectDebitHandler) ListDunning(c *gin.Context) {
+	mandates, err := h.directDebitUC.ListDunning(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, mandates)
+}
+
+// CreateRun godoc
+// @Summary Create a collection run (admin)
+// @Description Creates a collection instruction for every active mandate whose loan has an installment due on the collection date or a failed collection to retry then. The amount is everything due on that date, arrears included. One run per date. Defaults to the current business date.
+// @Tags direct-debit
+// @Accept json
+// @Produce json
+// @Param request body models.CreateCollectionRunRequest false "Collection date"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param X-User-ID header string true "Requesting user"
+// @Success 201 {object} models.CollectionRun
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /direct-debit/runs [post]
+func (h *DirectDebitHandler) CreateRun(c *gin.Context) {
+	actor := middleware.Actor(c)
+	if actor == "" {
+		c.Error(middleware.ErrActorRequired)
+		return
+	}
+	var req models.CreateCollectionRunRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(apperror.Default(err, apperror.Invalid))
+			return
+		}
+	}
+
+	var collectionDate time.Time
+	if req.CollectionDate != "" {
+		var err error
+		collectionDate, err = time.Parse("2006-01-02", req.CollectionDate)
+		if err != nil {
+			c.Error(apperror.Invalidf("invalid collection_date format, use YYYY-MM-DD"))
+			return
+		}
+	}
+
+	run, err := h.directDebitUC.CreateRun(c.Request.Context(), collectionDate, actor)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, run)
+}
+
+// GetRun godoc
+// @Summary Get a collection run (admin)
+// @Description The run's counts and every instruction with its outcome.
+// @Tags direct-debit
+// @Produce json
+// @Param id path int true "Collection run ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.CollectionRun
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /direct-debit/runs/{id} [get]
+func (h *DirectDebitHandler) GetRun(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid collection run id"))
+		return
+	}
+
+	run, err := h.directDebitUC.GetRun(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+// ExportRun godoc
+// @Summary Download a collection file (admin)
+// @Description The run's open instructions as a file for the bank, CSV or ISO 20022 pain.008. The first download marks the run exported; later ones return the same instructions.
+// @Tags direct-debit
+// @Produce text/csv
+// @Produce application/xml
+// @Param id path int true "Collection run ID"
+// @Param format query string false "csv (default) or pain008"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {file} file
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /direct-debit/runs/{id}/file [get]
+func (h *DirectDebitHandler) ExportRun(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid collection run id"))
+		return
+	}
+	format := c.DefaultQuery("format", ddfile.FormatCSV)
+
+	var buf bytes.Buffer
+	if err := h.directDebitUC.ExportRun(c.Request.Context(), id, format, &buf); err != nil {
+		c.Error(err)
+		return
+	}
+
+	contentType, ext := "text/csv", "csv"
+	if format == ddfile.FormatPain008 {
+		contentType, ext = "application/xml", "xml"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="collection-run-%d.%s"`, id, ext))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// ImportResults godoc
+// @Summary Import a collection result file (admin)
+// @Description Upload the bank's result as CSV (end_to_end_id, status, reason_code, reason) or an ISO 20022 pain.002 status report. Collections are posted as payments as of the collection date. A soft failure is retried after the configured days while attempts remain; a final failure raises the mandate's dunning level, and a hard reason code such as AC04 (account closed) or reaching the dunning limit suspends the mandate. Importing a file again is harmless.
+// @Tags direct-debit
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Collection run ID"
+// @Param file formData file true "Result file"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.CollectionResults
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /direct-debit/runs/{id}/results [post]
+func (h *DirectDebitHandler) ImportResults(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid collection run id"))
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.Error(apperror.Invalidf("file is required"))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer file.Close()
+
+	results, err := h.directDebitUC.ImportResults(c.Request.Context(), id, file)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/directdebit"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+	"github.com/lib/pq"
+)
+
+type directDebitRepository struct {
+	DB *sql.DB
+}
+
+func NewDirectDebitRepository(DB *sql.DB) directdebit.DirectDebitRepository {
+	return &directDebitRepository{
+		DB: DB,
+	}
+}
+
+const mandateColumns = `id, loan_id, reference, debtor_name, debtor_account, debtor_agent, signed_date, status,
+                        dunning_level, status_reason, created_by, created_at, updated_at`
+
+func scanMandate(row interface{ Scan(...any) error }, m *models.Mandate) error {
+	var agent, reason sql.NullString
+	err := row.Scan(
+		&m.ID,
+		&m.LoanID,
+		&m.Reference,
+		&m.DebtorName,
+		&m.DebtorAccount,
+		&agent,
+		&m.SignedDate,
+		&m.Status,
+		&m.DunningLevel,
+		&reason,
+		&m.CreatedBy,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	)
+	m.DebtorAgent, m.StatusReason = agent.String, reason.String
+	return err
+}
+
+// CreateMandate implements [directdebit.DirectDebitRepository]. Both a taken
+// reference and a second mandate in force on the loan conflict.
+func (r *directDebitRepository) CreateMandate(ctx context.Context, m *models.Mandate) error {
+	query := `INSERT INTO mandates (loan_id, reference, debtor_name, debtor_account, debtor_agent, signed_date, status, created_by)
+              VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8)
+              ON CONFLICT DO NOTHING
+              RETURNING id, created_at, updated_at`
+	return postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, m.LoanID, m.Reference, m.DebtorName, m.DebtorAccount,
+		m.DebtorAgent, m.SignedDate, m.Status, m.CreatedBy).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
+}
+
+func (r *directDebitRepository) GetMandate(ctx context.Context, id int) (*models.Mandate, error) {
+	var m models.Mandate
+	query := `SELECT ` + mandateColumns + ` FROM mandates WHERE id = $1`
+	err := scanMandate(postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, id), &m)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query mandate: %w", err)
+	}
+	return &m, nil
+}
+
+func (r *directDebitRepository) ListMandatesByLoan(ctx context.Context, loanID int) ([]models.Mandate, error) {
+	return r.queryMandates(ctx, `WHERE loan_id = $1 ORDER BY id`, loanID)
+}
+
+func (r *directDebitRepository) ListMandatesByStatus(ctx context.Context, status string) ([]models.Mandate, error) {
+	return r.queryMandates(ctx, `WHERE status = $1 ORDER BY id`, status)
+}
+
+// ListDunning implements [directdebit.DirectDebitRepository].
+func (r *directDebitRepository) ListDunning(ctx context.Context) ([]models.Mandate, error) {
+	return r.queryMandates(ctx, `WHERE (status = $1 AND dunning_level > 0) OR status = $2 ORDER BY dunning_level DESC, id`,
+		models.MandateActive, models.MandateSuspended)
+}
+
+func (r *directDebitRepository) queryMandates(ctx context.Context, where string, args ...any) ([]models.Mandate, error) {
+	query := `SELECT ` + mandateColumns + ` FROM mandates ` + where
+	rows, err := postgres.Conn(ctx, r.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query mandates: %w", err)
+	}
+	defer rows.Close()
+
+	var mandates []models.Mandate
+	for rows.Next() {
+		var m models.Mandate
+		if err := scanMandate(rows, &m); err != nil {
+			return nil, fmt.Errorf("scan mandate: %w", err)
+		}
+		mandates = append(mandates, m)
+	}
+	return mandates, rows.Err()
+}
+
+func (r *directDebitRepository) UpdateMandate(ctx context.Context, m *models.Mandate) error {
+	query := `UPDATE mandates
+              SET status = $2, dunning_level = $3, status_reason = NULLIF($4, ''), updated_at = CURRENT_TIMESTAMP
+              WHERE id = $1
+              RETURNING updated_at`
+	err := postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, m.ID, m.Status, m.DunningLevel, m.StatusReason).Scan(&m.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		return fmt.Errorf("update mandate: %w", err)
+	}
+	return nil
+}
+
+// CreateRun implements [directdebit.DirectDebitRepository].
+func (r *directDebitRepository) CreateRun(ctx context.Context, run *models.CollectionRun) error {
+	query := `INSERT INTO collection_runs (collection_date, status, created_by)
+              VALUES ($1, $2, $3)
+              ON CONFLICT (collection_date) DO NOTHING
+              RETURNING id, created_at`
+	return postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, run.CollectionDate, run.Status, run.CreatedBy).
+		Scan(&run.ID, &run.CreatedAt)
+}
+
+func (r *directDebitRepository) GetRun(ctx context.Context, id int) (*models.CollectionRun, error) {
+	var run models.CollectionRun
+	query := `SELECT id, collection_date, status, instructions, total_amount, collected, failed, created_by, created_at, exported_at
+              FROM collection_runs WHERE id = $1`
+	err := postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, id).Scan(
+		&run.ID,
+		&run.CollectionDate,
+		&run.Status,
+		&run.Instructions,
+		&run.TotalAmount,
+		&run.Collected,
+		&run.Failed,
+		&run.CreatedBy,
+		&run.CreatedAt,
+		&run.ExportedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query collection run: %w", err)
+	}
+
+	run.Items, err = r.queryInstructions(ctx, `WHERE run_id = $1 ORDER BY id`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *directDebitRepository) UpdateRun(ctx context.Context, run *models.CollectionRun) error {
+	query := `UPDATE collection_runs
+              SET status = $2, instructions = $3, total_amount = $4, collected = $5, failed = $6
+              WHERE id = $1`
+	_, err := postgres.Conn(ctx, r.DB).ExecContext(ctx, query, run.ID, run.Status, run.Instructions, run.TotalAmount,
+		run.Collected, run.Failed)
+	if err != nil {
+		return fmt.Errorf("update collection run: %w", err)
+	}
+	return nil
+}
+
+// MarkExported implements [directdebit.DirectDebitRepository]. Only the
+// first export moves the run and its pending instructions on; a later one
+// just rewrites the file.
+func (r *directDebitRepository) MarkExported(ctx context.Context, runID int, at time.Time) error {
+	return postgres.RunInTx(ctx, r.DB, func(tx postgres.DBTX) error {
+		_, err := tx.ExecContext(ctx, `UPDATE collection_instructions SET status = $2, updated_at = CURRENT_TIMESTAMP
+                                       WHERE run_id = $1 AND status = $3`,
+			runID, models.CollectionExported, models.CollectionPending)
+		if err != nil {
+			return fmt.Errorf("mark instructions exported: %w", err)
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE collection_runs SET status = $2, exported_at = COALESCE(exported_at, $3)
+                                      WHERE id = $1 AND status = $4`,
+			runID, models.CollectionRunExported, at, models.CollectionRunOpen)
+		if err != nil {
+			return fmt.Errorf("mark collection run exported: %w", err)
+		}
+		return nil
+	})
+}
+
+const instructionColumns = `id, run_id, mandate_id, loan_id, end_to_end_id, amount, attempt, status, reason_code, reason,
+                            retry_on, payment_id, updated_at`
+
+func scanInstruction(row interface{ Scan(...any) error }, in *models.CollectionInstruction) error {
+	var reasonCode, reason sql.NullString
+	err := row.Scan(
+		&in.ID,
+		&in.RunID,
+		&in.MandateID,
+		&in.LoanID,
+		&in.EndToEndID,
+		&in.Amount,
+		&in.Attempt,
+		&in.Status,
+		&reasonCode,
+		&reason,
+		&in.RetryOn,
+		&in.PaymentID,
+		&in.UpdatedAt,
+	)
+	in.ReasonCode, in.Reason = reasonCode.String, reason.String
+	return err
+}
+
+func (r *directDebitRepository) CreateInstruction(ctx context.Context, in *models.CollectionInstruction) error {
+	query := `INSERT INTO collection_instructions (run_id, mandate_id, loan_id, end_to_end_id, amount, attempt, status)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)
+              RETURNING id, updated_at`
+	err := postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, in.RunID, in.MandateID, in.LoanID, in.EndToEndID,
+		in.Amount, in.Attempt, in.Status).Scan(&in.ID, &in.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("insert collection instruction: %w", err)
+	}
+	return nil
+}
+
+func (r *directDebitRepository) GetInstructionByEndToEndID(ctx context.Context, endToEndID string) (*models.CollectionInstruction, error) {
+	return r.getInstruction(ctx, `WHERE end_to_end_id = $1`, endToEndID)
+}
+
+// LatestInstruction implements [directdebit.DirectDebitRepository].
+func (r *directDebitRepository) LatestInstruction(ctx context.Context, mandateID int) (*models.CollectionInstruction, error) {
+	return r.getInstruction(ctx, `WHERE mandate_id = $1 ORDER BY id DESC LIMIT 1`, mandateID)
+}
+
+func (r *directDebitRepository) getInstruction(ctx context.Context, where string, args ...any) (*models.CollectionInstruction, error) {
+	var in models.CollectionInstruction
+	query := `SELECT ` + instructionColumns + ` FROM collection_instructions ` + where
+	err := scanInstruction(postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, args...), &in)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query collection instruction: %w", err)
+	}
+	return &in, nil
+}
+
+func (r *directDebitRepository) queryInstructions(ctx context.Context, where string, args ...any) ([]models.CollectionInstruction, error) {
+	query := `SELECT ` + instructionColumns + ` FROM collection_instructions ` + where
+	rows, err := postgres.Conn(ctx, r.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query collection instructions: %w", err)
+	}
+	defer rows.Close()
+
+	var instructions []models.CollectionInstruction
+	for rows.Next() {
+		var in models.CollectionInstruction
+		if err := scanInstruction(rows, &in); err != nil {
+			return nil, fmt.Errorf("scan collection instruction: %w", err)
+		}
+		instructions = append(instructions, in)
+	}
+	return instructions, rows.Err()
+}
+
+// UpdateInstruction implements [directdebit.DirectDebitRepository].
+func (r *directDebitRepository) UpdateInstruction(ctx context.Context, in *models.CollectionInstruction, fromStatuses ...string) error {
+	query := `UPDATE collection_instructions
+              SET status = $2, reason_code = NULLIF($3, ''), reason = NULLIF($4, ''), retry_on = $5, payment_id = $6,
+                  updated_at = CURRENT_TIMESTAMP
+              WHERE id = $1 AND status = ANY($7)`
+	res, err := postgres.Conn(ctx, r.DB).ExecContext(ctx, query, in.ID, in.Status, in.ReasonCode, in.Reason, in.RetryOn,
+		in.PaymentID, pq.Array(fromStatuses))
+	if err != nil {
+		return fmt.Errorf("update collection instruction: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,500 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/directdebit"
+	"github.com/evrintobing17/loan-billing-system/internal/loan"
+	"github.com/evrintobing17/loan-billing-system/internal/payment"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+	ddfile "github.com/evrintobing17/loan-billing-system/pkg/directdebit"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+var openStatuses = []string{models.CollectionPending, models.CollectionExported}
+
+// hardReasonCodes are ISO 20022 return reasons that retrying cannot fix: the
+// account is wrong, closed or blocked, or the mandate is void. They suspend
+// the mandate at once.
+var hardReasonCodes = []string{"AC01", "AC04", "AC06", "AG01", "MD01", "MD07"}
+
+type directDebitUseCase struct {
+	directDebitRepo directdebit.DirectDebitRepository
+	loanRepo        loan.LoanRepository
+	paymentUC       payment.PaymentUsecase
+	tx              postgres.Transactor
+	clock           clock.Clock
+	creditor        ddfile.Creditor
+	policy          models.CollectionPolicy
+}
+
+// NewDirectDebitUseCase builds direct debit collection. The creditor is
+// written into every collection file; the policy decides how failed
+// collections are retried and escalated.
+func NewDirectDebitUseCase(dr directdebit.DirectDebitRepository, lr loan.LoanRepository, puc payment.PaymentUsecase,
+	tx postgres.Transactor, clk clock.Clock, creditor ddfile.Creditor,
+	policy models.CollectionPolicy) directdebit.DirectDebitUsecase {
+	return &directDebitUseCase{
+		directDebitRepo: dr,
+		loanRepo:        lr,
+		paymentUC:       puc,
+		tx:              tx,
+		clock:           clk,
+		creditor:        creditor,
+		policy:          policy,
+	}
+}
+
+// CreateMandate implements [directdebit.DirectDebitUsecase]. A loan can have
+// one mandate in force; the old one must be cancelled to change accounts.
+func (uc *directDebitUseCase) CreateMandate(ctx context.Context, loanID int, req models.CreateMandateRequest, createdBy string) (*models.Mandate, error) {
+	l, err := uc.loanRepo.GetByID(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	if l.Status != models.LoanStatusActive && l.Status != models.LoanStatusPendingDisbursement {
+		return nil, loan.ErrNotActive
+	}
+	signed, err := time.Parse(time.DateOnly, req.SignedDate)
+	if err != nil {
+		return nil, apperror.Invalidf("signed_date must be YYYY-MM-DD")
+	}
+	if signed.After(clock.Today(ctx, uc.clock)) {
+		return nil, apperror.Invalidf("signed_date cannot be in the future")
+	}
+
+	m := &models.Mandate{
+		LoanID:        loanID,
+		Reference:     req.Reference,
+		DebtorName:    req.DebtorName,
+		DebtorAccount: req.DebtorAccount,
+		DebtorAgent:   req.DebtorAgent,
+		SignedDate:    signed,
+		Status:        models.MandateActive,
+		CreatedBy:     createdBy,
+	}
+	if err := uc.directDebitRepo.CreateMandate(ctx, m); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		mandates, err := uc.directDebitRepo.ListMandatesByLoan(ctx, loanID)
+		if err != nil {
+			return nil, err
+		}
+		for _, existing := range mandates {
+			if existing.Status != models.MandateCancelled {
+				return nil, directdebit.ErrMandateExists.Withf("loan %d has mandate %s in force", loanID, existing.Reference)
+			}
+		}
+		return nil, directdebit.ErrMandateReference
+	}
+	return m, nil
+}
+
+func (uc *directDebitUseCase) ListMandates(ctx context.Context, loanID int) ([]models.Mandate, error) {
+	if _, err := uc.loanRepo.GetByID(ctx, loanID); err != nil {
+		return nil, err
+	}
+	return uc.directDebitRepo.ListMandatesByLoan(ctx, loanID)
+}
+
+func (uc *directDebitUseCase) CancelMandate(ctx context.Context, id int, reason string) (*models.Mandate, error) {
+	m, err := uc.getMandate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.Status == models.MandateCancelled {
+		return nil, directdebit.ErrMandateStatus.Withf("mandate is already cancelled")
+	}
+	m.Status = models.MandateCancelled
+	m.StatusReason = reason
+	return m, uc.directDebitRepo.UpdateMandate(ctx, m)
+}
+
+// ReactivateMandate implements [directdebit.DirectDebitUsecase].
+func (uc *directDebitUseCase) ReactivateMandate(ctx context.Context, id int) (*models.Mandate, error) {
+	m, err := uc.getMandate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.Status != models.MandateSuspended {
+		return nil, directdebit.ErrMandateStatus.Withf("only suspended mandates can be reactivated")
+	}
+	m.Status = models.MandateActive
+	m.DunningLevel = 0
+	m.StatusReason = ""
+	return m, uc.directDebitRepo.UpdateMandate(ctx, m)
+}
+
+func (uc *directDebitUseCase) ListDunning(ctx context.Context) ([]models.Mandate, error) {
+	return uc.directDebitRepo.ListDunning(ctx)
+}
+
+func (uc *directDebitUseCase) getMandate(ctx context.Context, id int) (*models.Mandate, error) {
+	m, err := uc.directDebitRepo.GetMandate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, directdebit.ErrMandateNotFound.Wrap(err)
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// CreateRun implements [directdebit.DirectDebitUsecase]. A mandate whose
+// last instruction is still open at the bank is skipped, so a loan is never
+// collected twice at once. The amount is everything due on the collection
+// date, arrears included.
+func (uc *directDebitUseCase) CreateRun(ctx context.Context, collectionDate time.Time, createdBy string) (*models.CollectionRun, error) {
+	if collectionDate.IsZero() {
+		collectionDate = clock.Today(ctx, uc.clock)
+	}
+	collectionDate = clock.Date(collectionDate)
+
+	run := &models.CollectionRun{CollectionDate: collectionDate, Status: models.CollectionRunOpen, CreatedBy: createdBy}
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.directDebitRepo.CreateRun(ctx, run); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return directdebit.ErrRunExists.Withf("a collection run exists for %s", collectionDate.Format(time.DateOnly))
+			}
+			return err
+		}
+
+		mandates, err := uc.directDebitRepo.ListMandatesByStatus(ctx, models.MandateActive)
+		if err != nil {
+			return err
+		}
+		for _, m := range mandates {
+			in, err := uc.instruction(ctx, run, &m)
+			if err != nil {
+				return err
+			}
+			if in == nil {
+				continue
+			}
+			if err := uc.directDebitRepo.CreateInstruction(ctx, in); err != nil {
+				return err
+			}
+			run.Instructions++
+			run.TotalAmount += in.Amount
+		}
+		run.TotalAmount = math.Round(run.TotalAmount*100) / 100
+		return uc.directDebitRepo.UpdateRun(ctx, run)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uc.directDebitRepo.GetRun(ctx, run.ID)
+}
+
+// instruction returns what to collect on the mandate in the run, or nil when
+// nothing is to be collected: no installment falls due on the date and no
+// failed collection is to be retried then. A retry that coincides with a new
+// due date still counts as a retry, so the arrears it carries do not get a
+// fresh set of attempts.
+func (uc *directDebitUseCase) instruction(ctx context.Context, run *models.CollectionRun, m *models.Mandate) (*models.CollectionInstruction, error) {
+	latest, err := uc.directDebitRepo.LatestInstruction(ctx, m.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if latest != nil && slices.Contains(openStatuses, latest.Status) {
+		return nil, nil
+	}
+
+	retryDue := latest != nil && latest.Status == models.CollectionFailed && latest.RetryOn != nil &&
+		!latest.RetryOn.After(run.CollectionDate)
+	fallsDue, err := uc.fallsDue(ctx, m.LoanID, run.CollectionDate)
+	if err != nil {
+		return nil, err
+	}
+	if !fallsDue && !retryDue {
+		return nil, nil
+	}
+	attempt := 1
+	if retryDue {
+		attempt = latest.Attempt + 1
+	}
+
+	amount, err := uc.paymentUC.AmountDue(clock.WithAsOfDate(ctx, run.CollectionDate), m.LoanID)
+	if err != nil {
+		if errors.Is(err, loan.ErrNotActive) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if amount <= 0 {
+		return nil, nil
+	}
+	return &models.CollectionInstruction{
+		RunID:      run.ID,
+		MandateID:  m.ID,
+		LoanID:     m.LoanID,
+		EndToEndID: fmt.Sprintf("DD%d-%d-%d", run.ID, m.LoanID, attempt),
+		Amount:     amount,
+		Attempt:    attempt,
+		Status:     models.CollectionPending,
+	}, nil
+}
+
+// fallsDue reports whether an unpaid installment or charge of the loan is
+// due on the date.
+func (uc *directDebitUseCase) fallsDue(ctx context.Context, loanID int, date time.Time) (bool, error) {
+	installments, err := uc.loanRepo.GetInstallments(ctx, loanID)
+	if err != nil {
+		return false, err
+	}
+	for _, inst := range installments {
+		if !inst.Paid && inst.DueDate.Equal(date) {
+			return true, nil
+		}
+	}
+	charges, err := uc.loanRepo.GetCharges(ctx, loanID)
+	if err != nil {
+		return false, err
+	}
+	for _, charge := range charges {
+		if !charge.Paid && charge.DueDate.Equal(date) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (uc *directDebitUseCase) GetRun(ctx context.Context, id int) (*models.CollectionRun, error) {
+	run, err := uc.directDebitRepo.GetRun(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, directdebit.ErrRunNotFound.Wrap(err)
+		}
+		return nil, err
+	}
+	return run, nil
+}
+
+// ExportRun implements [directdebit.DirectDebitUsecase]. Exporting again
+// writes the same open instructions, e.g. when the first file was lost.
+func (uc *directDebitUseCase) ExportRun(ctx context.Context, id int, format string, w io.Writer) error {
+	if format == "" {
+		format = ddfile.FormatCSV
+	}
+	if format != ddfile.FormatCSV && format != ddfile.FormatPain008 {
+		return directdebit.ErrUnknownFileFormat
+	}
+	run, err := uc.GetRun(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	batch := &ddfile.Batch{
+		MessageID:      fmt.Sprintf("DD-RUN-%d", run.ID),
+		CreatedAt:      uc.clock.Now(),
+		CollectionDate: run.CollectionDate,
+		Creditor:       uc.creditor,
+	}
+	for _, in := range run.Items {
+		if !slices.Contains(openStatuses, in.Status) {
+			continue
+		}
+		m, err := uc.directDebitRepo.GetMandate(ctx, in.MandateID)
+		if err != nil {
+			return err
+		}
+		l, err := uc.loanRepo.GetByID(ctx, in.LoanID)
+		if err != nil {
+			return err
+		}
+		remittance := fmt.Sprintf("Loan %d", l.ID)
+		if l.VirtualAccount != "" {
+			remittance += " VA " + l.VirtualAccount
+		}
+		batch.Instructions = append(batch.Instructions, ddfile.Instruction{
+			EndToEndID:    in.EndToEndID,
+			Amount:        in.Amount,
+			MandateID:     m.Reference,
+			MandateSigned: m.SignedDate,
+			DebtorName:    m.DebtorName,
+			DebtorAccount: m.DebtorAccount,
+			DebtorAgent:   m.DebtorAgent,
+			Remittance:    remittance,
+		})
+	}
+
+	// Render first so a failed write does not leave the run marked exported.
+	var buf bytes.Buffer
+	if err := ddfile.Write(format, &buf, batch); err != nil {
+		return err
+	}
+	if err := uc.directDebitRepo.MarkExported(ctx, run.ID, uc.clock.Now()); err != nil {
+		return err
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// ImportResults implements [directdebit.DirectDebitUsecase]. Results for
+// instructions that are already closed are reported as unknown, so importing
+// a file twice is harmless. A collection that cannot be posted, e.g. because
+// the loan was paid another way meanwhile, is left unapplied for manual
+// handling.
+func (uc *directDebitUseCase) ImportResults(ctx context.Context, id int, file io.Reader) (*models.CollectionResults, error) {
+	run, err := uc.GetRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	results, err := ddfile.ParseResults(file)
+	if err != nil {
+		return nil, directdebit.ErrInvalidResultFile.Withf("%s", err.Error())
+	}
+
+	summary := &models.CollectionResults{RunID: run.ID}
+	for _, res := range results {
+		in, err := uc.directDebitRepo.GetInstructionByEndToEndID(ctx, res.EndToEndID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		if in == nil || in.RunID != run.ID || !slices.Contains(openStatuses, in.Status) {
+			summary.Unknown = append(summary.Unknown, res.EndToEndID)
+			continue
+		}
+
+		in.ReasonCode, in.Reason = res.ReasonCode, res.Reason
+		switch res.Status {
+		case ddfile.ResultCollected:
+			if err := uc.collected(ctx, run, in); err != nil {
+				return nil, err
+			}
+			if in.Status == models.CollectionCollected {
+				summary.Collected++
+			} else {
+				summary.Unapplied++
+			}
+		case ddfile.ResultFailed:
+			if err := uc.failed(ctx, run, in); err != nil {
+				return nil, err
+			}
+			summary.Failed++
+			if in.RetryOn != nil {
+				summary.RetryScheduled++
+			}
+		default:
+			summary.Pending++
+		}
+	}
+
+	if err := uc.updateCounts(ctx, run.ID); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// collected posts a collection as a payment as of the collection date, keyed
+// on the end-to-end ID, and clears the mandate's dunning level.
+func (uc *directDebitUseCase) collected(ctx context.Context, run *models.CollectionRun, in *models.CollectionInstruction) error {
+	key := "dd:" + in.EndToEndID
+	paymentID, err := uc.pay(ctx, run.CollectionDate, in, key)
+	if err != nil {
+		if e, ok := apperror.Lookup(err); !ok || e.Kind == apperror.Internal {
+			return err
+		}
+		in.Status = models.CollectionUnapplied
+		in.Reason = err.Error()
+		return uc.directDebitRepo.UpdateInstruction(ctx, in, openStatuses...)
+	}
+
+	in.Status = models.CollectionCollected
+	in.PaymentID = &paymentID
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.directDebitRepo.UpdateInstruction(ctx, in, openStatuses...); err != nil {
+			return err
+		}
+		m, err := uc.directDebitRepo.GetMandate(ctx, in.MandateID)
+		if err != nil {
+			return err
+		}
+		if m.DunningLevel == 0 {
+			return nil
+		}
+		m.DunningLevel = 0
+		return uc.directDebitRepo.UpdateMandate(ctx, m)
+	})
+}
+
+// pay makes the payment, or finds the one an earlier import made but failed
+// to record.
+func (uc *directDebitUseCase) pay(ctx context.Context, date time.Time, in *models.CollectionInstruction, key string) (int, error) {
+	if date.Before(clock.Today(ctx, uc.clock)) {
+		ctx = clock.WithAsOfDate(ctx, date)
+	}
+	p, err := uc.paymentUC.MakePayment(ctx, in.LoanID, in.Amount, key)
+	if err != nil {
+		return 0, err
+	}
+	return p.ID, nil
+}
+
+// failed schedules a retry for a soft failure while attempts remain.
+// Otherwise the failure is final and raises the mandate's dunning level; a
+// hard reason code or reaching the policy's level suspends the mandate.
+func (uc *directDebitUseCase) failed(ctx context.Context, run *models.CollectionRun, in *models.CollectionInstruction) error {
+	in.Status = models.CollectionFailed
+	hard := slices.Contains(hardReasonCodes, in.ReasonCode)
+	if !hard && in.Attempt < uc.policy.MaxAttempts {
+		retryOn := run.CollectionDate.AddDate(0, 0, uc.policy.RetryDays)
+		in.RetryOn = &retryOn
+		return uc.directDebitRepo.UpdateInstruction(ctx, in, openStatuses...)
+	}
+
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.directDebitRepo.UpdateInstruction(ctx, in, openStatuses...); err != nil {
+			return err
+		}
+		m, err := uc.directDebitRepo.GetMandate(ctx, in.MandateID)
+		if err != nil {
+			return err
+		}
+		m.DunningLevel++
+		if m.Status == models.MandateActive && (hard || (uc.policy.SuspendAfter > 0 && m.DunningLevel >= uc.policy.SuspendAfter)) {
+			m.Status = models.MandateSuspended
+			m.StatusReason = fmt.Sprintf("collection %s failed", in.EndToEndID)
+			if in.ReasonCode != "" {
+				m.StatusReason += ": " + in.ReasonCode
+			}
+		}
+		return uc.directDebitRepo.UpdateMandate(ctx, m)
+	})
+}
+
+// updateCounts recounts the run's outcomes and completes it once no
+// instruction awaits a result.
+func (uc *directDebitUseCase) updateCounts(ctx context.Context, runID int) error {
+	run, err := uc.directDebitRepo.GetRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	run.Collected, run.Failed = 0, 0
+	open := false
+	for _, in := range run.Items {
+		switch in.Status {
+		case models.CollectionCollected:
+			run.Collected++
+		case models.CollectionFailed:
+			run.Failed++
+		case models.CollectionPending, models.CollectionExported:
+			open = true
+		}
+	}
+	if !open {
+		run.Status = models.CollectionRunCompleted
+	}
+	return uc.directDebitRepo.UpdateRun(ctx, run)
+}
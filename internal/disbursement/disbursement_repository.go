@@ -0,0 +1,15 @@
+package disbursement
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type DisbursementRepository interface {
+	// Create records a tranche and updates the loan in one transaction:
+	// shiftDays moves the open schedule and the loan's start date (non-zero
+	// only for the first tranche) and activate flips the loan to active once it is fully disbursed.
+	Create(ctx context.Context, disbursement *models.Disbursement, shiftDays int, activate bool) error
+	GetByLoanID(ctx context.Context, loanID int) ([]models.Disbursement, error)
+}
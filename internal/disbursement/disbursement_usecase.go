@@ -0,0 +1,12 @@
+package disbursement
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type DisbursementUsecase interface {
+	Disburse(ctx context.Context, loanID int, req models.DisbursementRequest) (*models.Disbursement, error)
+	ListDisbursements(ctx context.Context, loanID int) ([]models.Disbursement, error)
+}
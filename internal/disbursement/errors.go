@@ -0,0 +1,8 @@
+package disbursement
+
+import "github.com/evrintobing17/loan-billing-system/pkg/apperror"
+
+var (
+	ErrFullyDisbursed   = apperror.New(apperror.Conflict, "loan_fully_disbursed", "loan is already fully disbursed")
+	ErrExceedsRemaining = apperror.New(apperror.Invalid, "disbursement_exceeds_remaining", "amount exceeds remaining disbursement")
+)
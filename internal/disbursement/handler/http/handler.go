@@ -0,0 +1,79 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/evrintobing17/loan-billing-system/internal/disbursement"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+type DisbursementHandler struct {
+	disbursementUC disbursement.DisbursementUsecase
+}
+
+func NewDisbursementHandler(uc disbursement.DisbursementUsecase) *DisbursementHandler {
+	return &DisbursementHandler{disbursementUC: uc}
+}
+
+// Disburse godoc
+// @Summary Record a disbursement tranche
+// @Description Record money sent to the borrower (admin only). The first tranche anchors the installment schedule; the loan becomes active once fully disbursed.
+// @Tags disbursements
+// @Accept json
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param request body models.DisbursementRequest true "Tranche details"
+// @Success 201 {object} models.Disbursement
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /loans/{id}/disbursements [post]
+func (h *DisbursementHandler) Disburse(c *gin.Context) {
+	loanID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid loan id"))
+		return
+	}
+
+	var req models.DisbursementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+
+	disb, err := h.disbursementUC.Disburse(c.Request.Context(), loanID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, disb)
+}
+
+// ListDisbursements godoc
+// @Summary List disbursement tranches of a loan
+// @Tags disbursements
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Success 200 {array} models.Disbursement
+// @Failure 404 {object} models.Problem
+// @Router /loans/{id}/disbursements [get]
+func (h *DisbursementHandler) ListDisbursements(c *gin.Context) {
+	loanID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid loan id"))
+		return
+	}
+
+	disbursements, err := h.disbursementUC.ListDisbursements(c.Request.Context(), loanID)
+	if err != nil {
+		c.Error(apperror.Default(err, apperror.NotFound))
+		return
+	}
+	c.JSON(http.StatusOK, disbursements)
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/evrintobing17/loan-billing-system/internal/disbursement"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+type disbursementRepository struct {
+	DB *sql.DB
+}
+
+func NewDisbursementRepository(DB *sql.DB) disbursement.DisbursementRepository {
+	return &disbursementRepository{
+		DB: DB,
+	}
+}
+
+// Create implements [disbursement.DisbursementRepository].
+func (d *disbursementRepository) Create(ctx context.Context, disb *models.Disbursement, shiftDays int, activate bool) error {
+	return postgres.RunInTx(ctx, d.DB, func(tx postgres.DBTX) error {
+		query := `INSERT INTO disbursements (loan_id, amount, disbursement_date, channel, reference)
+                  VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+		err := tx.QueryRowContext(ctx, query, disb.LoanID, disb.Amount, disb.DisbursementDate,
+			disb.Channel, disb.Reference).Scan(&disb.ID, &disb.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		// Re-anchor the schedule, and the start date it is counted from, on
+		// the first disbursement date
+		if shiftDays != 0 {
+			_, err = tx.ExecContext(ctx,
+				`UPDATE installments SET due_date = due_date + $2::int WHERE loan_id = $1 AND paid IS FALSE`,
+				disb.LoanID, shiftDays)
+			if err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx,
+				`UPDATE loan_charges SET due_date = due_date + $2::int WHERE loan_id = $1 AND paid IS FALSE`,
+				disb.LoanID, shiftDays)
+			if err != nil {
+				return err
+			}
+		}
+
+		status := models.LoanStatusPendingDisbursement
+		if activate {
+			status = models.LoanStatusActive
+		}
+		_, err = tx.ExecContext(ctx,
+			`UPDATE loans
+             SET disbursed_amount = disbursed_amount + $2,
+                 first_disbursement_date = COALESCE(first_disbursement_date, $3),
+                 status = $4,
+                 start_date = start_date + $5::int
+             WHERE id = $1`,
+			disb.LoanID, disb.Amount, disb.DisbursementDate, status, shiftDays)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// GetByLoanID returns the tranches of a loan in disbursement order.
+func (d *disbursementRepository) GetByLoanID(ctx context.Context, loanID int) ([]models.Disbursement, error) {
+	query := `SELECT id, loan_id, amount, disbursement_date, channel, reference, created_at
+              FROM disbursements
+              WHERE loan_id = $1
+              ORDER BY disbursement_date, id`
+	rows, err := postgres.Conn(ctx, d.DB).QueryContext(ctx, query, loanID)
+	if err != nil {
+		return nil, fmt.Errorf("query disbursements: %w", err)
+	}
+	defer rows.Close()
+
+	var disbursements []models.Disbursement
+	for rows.Next() {
+		var disb models.Disbursement
+		err := rows.Scan(
+			&disb.ID,
+			&disb.LoanID,
+			&disb.Amount,
+			&disb.DisbursementDate,
+			&disb.Channel,
+			&disb.Reference,
+			&disb.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan disbursement: %w", err)
+		}
+		disbursements = append(disbursements, disb)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return disbursements, nil
+}
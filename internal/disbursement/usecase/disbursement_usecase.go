@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/accounting"
+	"github.com/evrintobing17/loan-billing-system/internal/disbursement"
+	"github.com/evrintobing17/loan-billing-system/internal/loan"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+type disbursementUseCase struct {
+	disbursementRepo disbursement.DisbursementRepository
+	loanRepo         loan.LoanRepository
+	accountingUC     accounting.AccountingUsecase
+	tx               postgres.Transactor
+	clock            clock.Clock
+}
+
+func NewDisbursementUseCase(dr disbursement.DisbursementRepository, lr loan.LoanRepository, auc accounting.AccountingUsecase, tx postgres.Transactor, clk clock.Clock) disbursement.DisbursementUsecase {
+	return &disbursementUseCase{
+		disbursementRepo: dr,
+		loanRepo:         lr,
+		accountingUC:     auc,
+		tx:               tx,
+		clock:            clk,
+	}
+}
+
+// Disburse records a tranche. The first tranche re-anchors the installment
+// schedule on its date; the loan becomes active once the net disbursement has
+// been sent in full. The loan is locked while the tranche is checked and
+// recorded, so concurrent tranches cannot shift the schedule twice or send
+// more than the net disbursement.
+func (uc *disbursementUseCase) Disburse(ctx context.Context, loanID int, req models.DisbursementRequest) (*models.Disbursement, error) {
+	date := clock.Today(ctx, uc.clock)
+	if req.DisbursementDate != "" {
+		var err error
+		date, err = time.Parse("2006-01-02", req.DisbursementDate)
+		if err != nil {
+			return nil, apperror.Invalidf("invalid disbursement_date format, use YYYY-MM-DD")
+		}
+	}
+
+	disb := &models.Disbursement{
+		LoanID:           loanID,
+		Amount:           req.Amount,
+		DisbursementDate: date,
+		Channel:          req.Channel,
+		Reference:        req.Reference,
+	}
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		loan, err := uc.loanRepo.GetForUpdate(ctx, loanID)
+		if err != nil {
+			return err
+		}
+		if loan.Status != models.LoanStatusPendingDisbursement {
+			return disbursement.ErrFullyDisbursed
+		}
+		remaining := loan.NetDisbursement - loan.DisbursedAmount
+		if req.Amount > remaining+0.005 {
+			return disbursement.ErrExceedsRemaining.Withf("amount exceeds remaining disbursement of %.2f", remaining)
+		}
+
+		var shiftDays int
+		if loan.FirstDisbursementDate == nil {
+			shiftDays = int(date.Sub(clock.Date(loan.StartDate)).Hours() / 24)
+		}
+		activate := loan.DisbursedAmount+req.Amount >= loan.NetDisbursement-0.005
+
+		if err := uc.disbursementRepo.Create(ctx, disb, shiftDays, activate); err != nil {
+			return err
+		}
+		return uc.accountingUC.RecordDisbursement(ctx, disb)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return disb, nil
+}
+
+func (uc *disbursementUseCase) ListDisbursements(ctx context.Context, loanID int) ([]models.Disbursement, error) {
+	if _, err := uc.loanRepo.GetByID(ctx, loanID); err != nil {
+		return nil, err
+	}
+	return uc.disbursementRepo.GetByLoanID(ctx, loanID)
+}
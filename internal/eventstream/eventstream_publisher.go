@@ -0,0 +1,13 @@
+package eventstream
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// Publisher hands events to the message broker. Publish returns once the
+// broker has accepted the event.
+type Publisher interface {
+	Publish(ctx context.Context, e *models.Event) error
+}
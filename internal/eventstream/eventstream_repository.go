@@ -0,0 +1,21 @@
+package eventstream
+
+import (
+	"context"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// EventRepository reads the outbox that repositories append events to with
+// [outbox.Append].
+type EventRepository interface {
+	// Claim takes up to limit unpublished events due by now, in the order
+	// they were recorded, holding them until lease so that concurrent relays
+	// do not publish them too.
+	Claim(ctx context.Context, now, lease time.Time, limit int) ([]models.OutboxEvent, error)
+	Update(ctx context.Context, e *models.OutboxEvent) error
+	// Release makes the events due again at the given time.
+	Release(ctx context.Context, ids []int64, at time.Time) error
+	List(ctx context.Context, filter models.EventFilter, limit int) ([]models.OutboxEvent, error)
+}
@@ -0,0 +1,15 @@
+package eventstream
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// EventStreamUsecase publishes the domain events in the outbox.
+type EventStreamUsecase interface {
+	// Relay publishes the events that are due, stopping at the first one the
+	// publisher rejects so that later events do not overtake it.
+	Relay(ctx context.Context) (*models.EventRelay, error)
+	List(ctx context.Context, filter models.EventFilter) ([]models.OutboxEvent, error)
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/evrintobing17/loan-billing-system/internal/eventstream"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+type EventStreamHandler struct {
+	eventStreamUC eventstream.EventStreamUsecase
+}
+
+func NewEventStreamHandler(uc eventstream.EventStreamUsecase) *EventStreamHandler {
+	return &EventStreamHandler{eventStreamUC: uc}
+}
+
+// ListEvents godoc
+// @Summary List domain events (admin)
+// @Description The 200 most recent events in the outbox, newest first, with their delivery state.
+// @Tags events
+// @Produce json
+// @Param type query string false "Filter by type, e.g. payment.received"
+// @Param loan_id query int false "Filter by loan"
+// @Param status query string false "Filter by status: pending or published"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {array} models.OutboxEvent
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /events [get]
+func (h *EventStreamHandler) ListEvents(c *gin.Context) {
+	filter := models.EventFilter{
+		Type:   c.Query("type"),
+		Status: c.Query("status"),
+	}
+	if s := c.Query("loan_id"); s != "" {
+		loanID, err := strconv.Atoi(s)
+		if err != nil {
+			c.Error(apperror.Invalidf("invalid loan_id"))
+			return
+		}
+		filter.LoanID = loanID
+	}
+
+	events, err := h.eventStreamUC.List(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+// Relay godoc
+// @Summary Publish pending domain events (admin)
+// @Description Publishes the events that are due now, as the background relay does. Stops at the first event the broker rejects; it is retried later.
+// @Tags events
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.EventRelay
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /events/relay [post]
+func (h *EventStreamHandler) Relay(c *gin.Context) {
+	result, err := h.eventStreamUC.Relay(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,26 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/internal/eventstream"
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type multi []eventstream.Publisher
+
+// NewMulti hands each event to all the publishers in turn. An event one of
+// them rejects is published again to all of them on the next try, so each
+// must tolerate duplicates.
+func NewMulti(publishers ...eventstream.Publisher) eventstream.Publisher {
+	return multi(publishers)
+}
+
+func (m multi) Publish(ctx context.Context, e *models.Event) error {
+	for _, p := range m {
+		if err := p.Publish(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/evrintobing17/loan-billing-system/internal/eventstream"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/go-redis/redis/v8"
+)
+
+type redisStream struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+// NewRedisStream appends events to one Redis stream. Each entry has the
+// event's id, type and version as fields for routing, and the whole event as
+// JSON in the event field. The stream is trimmed to about maxLen entries;
+// zero keeps them all.
+func NewRedisStream(client *redis.Client, stream string, maxLen int64) eventstream.Publisher {
+	return &redisStream{client: client, stream: stream, maxLen: maxLen}
+}
+
+func (r *redisStream) Publish(ctx context.Context, e *models.Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	args := &redis.XAddArgs{
+		Stream: r.stream,
+		Values: map[string]interface{}{
+			"id":      e.ID,
+			"type":    e.Type,
+			"version": e.Version,
+			"event":   body,
+		},
+	}
+	if r.maxLen > 0 {
+		args.MaxLen, args.Approx = r.maxLen, true
+	}
+	return r.client.XAdd(ctx, args).Err()
+}
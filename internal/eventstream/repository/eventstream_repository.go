@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/eventstream"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+	"github.com/lib/pq"
+)
+
+type eventRepository struct {
+	DB *sql.DB
+}
+
+func NewEventRepository(DB *sql.DB) eventstream.EventRepository {
+	return &eventRepository{
+		DB: DB,
+	}
+}
+
+const eventColumns = `id, event_type, version, aggregate_type, aggregate_id, occurred_at, data, attempts, last_error,
+                      next_attempt_at, published_at`
+
+func scanEvent(row interface{ Scan(...any) error }, e *models.OutboxEvent) error {
+	var data []byte
+	var lastError sql.NullString
+	err := row.Scan(
+		&e.ID,
+		&e.Type,
+		&e.Version,
+		&e.AggregateType,
+		&e.AggregateID,
+		&e.OccurredAt,
+		&data,
+		&e.Attempts,
+		&lastError,
+		&e.NextAttemptAt,
+		&e.PublishedAt,
+	)
+	e.Data, e.LastError = data, lastError.String
+	return err
+}
+
+// Claim implements [eventstream.EventRepository]. An event waits while an
+// earlier one of the same aggregate is held back, so that each loan's
+// events are published in order.
+func (r *eventRepository) Claim(ctx context.Context, now, lease time.Time, limit int) ([]models.OutboxEvent, error) {
+	query := `UPDATE outbox_events SET next_attempt_at = $2
+              WHERE id IN (SELECT e.id FROM outbox_events e
+                           WHERE e.published_at IS NULL AND e.next_attempt_at <= $1
+                             AND NOT EXISTS (SELECT 1 FROM outbox_events o
+                                             WHERE o.aggregate_type = e.aggregate_type AND o.aggregate_id = e.aggregate_id
+                                               AND o.id < e.id AND o.published_at IS NULL AND o.next_attempt_at > $1)
+                           ORDER BY e.id
+                           LIMIT $3
+                           FOR UPDATE SKIP LOCKED)
+              RETURNING ` + eventColumns
+	events, err := r.query(ctx, query, now, lease, limit)
+	if err != nil {
+		return nil, err
+	}
+	// RETURNING has no order of its own.
+	slices.SortFunc(events, func(a, b models.OutboxEvent) int { return cmp.Compare(a.ID, b.ID) })
+	return events, nil
+}
+
+// List returns the most recent events first.
+func (r *eventRepository) List(ctx context.Context, filter models.EventFilter, limit int) ([]models.OutboxEvent, error) {
+	var loanID string
+	if filter.LoanID != 0 {
+		loanID = strconv.Itoa(filter.LoanID)
+	}
+	query := `SELECT ` + eventColumns + ` FROM outbox_events
+              WHERE ($1 = '' OR event_type = $1)
+                AND ($2 = '' OR (aggregate_type = 'loan' AND aggregate_id = $2))
+                AND ($3 = '' OR ($3 = 'pending' AND published_at IS NULL) OR ($3 = 'published' AND published_at IS NOT NULL))
+              ORDER BY id DESC
+              LIMIT $4`
+	return r.query(ctx, query, filter.Type, loanID, filter.Status, limit)
+}
+
+func (r *eventRepository) query(ctx context.Context, query string, args ...any) ([]models.OutboxEvent, error) {
+	rows, err := postgres.Conn(ctx, r.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.OutboxEvent
+	for rows.Next() {
+		var e models.OutboxEvent
+		if err := scanEvent(rows, &e); err != nil {
+			return nil, fmt.Errorf("scan outbox event: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func (r *eventRepository) Update(ctx context.Context, e *models.OutboxEvent) error {
+	query := `UPDATE outbox_events
+              SET attempts = $2, last_error = NULLIF($3, ''), next_attempt_at = $4, published_at = $5
+              WHERE id = $1`
+	_, err := postgres.Conn(ctx, r.DB).ExecContext(ctx, query, e.ID, e.Attempts, e.LastError, e.NextAttemptAt, e.PublishedAt)
+	if err != nil {
+		return fmt.Errorf("update outbox event: %w", err)
+	}
+	return nil
+}
+
+// Release implements [eventstream.EventRepository].
+func (r *eventRepository) Release(ctx context.Context, ids []int64, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := postgres.Conn(ctx, r.DB).ExecContext(ctx,
+		`UPDATE outbox_events SET next_attempt_at = $2 WHERE id = ANY($1)`, pq.Array(ids), at)
+	if err != nil {
+		return fmt.Errorf("release outbox events: %w", err)
+	}
+	return nil
+}
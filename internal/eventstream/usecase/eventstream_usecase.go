@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/eventstream"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+)
+
+const (
+	listLimit  = 200
+	relayBatch = 100
+	// relayLease is how long a claimed event is held before another relay
+	// may take it, should this one die mid-publish.
+	relayLease = time.Minute
+	// maxBackoff caps the doubling delay between tries of an event.
+	maxBackoff = 5 * time.Minute
+)
+
+type eventStreamUseCase struct {
+	eventRepo eventstream.EventRepository
+	publisher eventstream.Publisher
+	clock     clock.Clock
+}
+
+// NewEventStreamUseCase builds the relay publishing the outbox through the
+// publisher.
+func NewEventStreamUseCase(er eventstream.EventRepository, pub eventstream.Publisher, clk clock.Clock) eventstream.EventStreamUsecase {
+	return &eventStreamUseCase{
+		eventRepo: er,
+		publisher: pub,
+		clock:     clk,
+	}
+}
+
+// Relay implements [eventstream.EventStreamUsecase]. An event is marked
+// published only after the publisher accepted it, so a crash in between
+// publishes it again: delivery is at least once.
+func (uc *eventStreamUseCase) Relay(ctx context.Context) (*models.EventRelay, error) {
+	result := &models.EventRelay{}
+	for {
+		now := uc.clock.Now()
+		batch, err := uc.eventRepo.Claim(ctx, now, now.Add(relayLease), relayBatch)
+		if err != nil {
+			return result, err
+		}
+		for i := range batch {
+			e := &batch[i]
+			e.Attempts++
+			if err := uc.publisher.Publish(ctx, &e.Event); err != nil {
+				return result, uc.fail(ctx, batch[i:], err)
+			}
+			published := uc.clock.Now()
+			e.LastError, e.PublishedAt = "", &published
+			if err := uc.eventRepo.Update(ctx, e); err != nil {
+				return result, err
+			}
+			result.Published++
+		}
+		if len(batch) < relayBatch {
+			return result, nil
+		}
+	}
+}
+
+// fail holds the first event back for a doubling delay and releases the rest
+// of the batch; their loans' later events wait behind it.
+func (uc *eventStreamUseCase) fail(ctx context.Context, rest []models.OutboxEvent, publishErr error) error {
+	e := &rest[0]
+	backoff := maxBackoff
+	if e.Attempts < 10 {
+		backoff = min(time.Second<<e.Attempts, maxBackoff)
+	}
+	now := uc.clock.Now()
+	e.LastError, e.NextAttemptAt = publishErr.Error(), now.Add(backoff)
+	if err := uc.eventRepo.Update(ctx, e); err != nil {
+		return err
+	}
+
+	ids := make([]int64, 0, len(rest)-1)
+	for _, r := range rest[1:] {
+		ids = append(ids, r.ID)
+	}
+	if err := uc.eventRepo.Release(ctx, ids, now); err != nil {
+		return err
+	}
+	return fmt.Errorf("publish event %d: %w", e.ID, publishErr)
+}
+
+func (uc *eventStreamUseCase) List(ctx context.Context, filter models.EventFilter) ([]models.OutboxEvent, error) {
+	if filter.Status != "" && filter.Status != models.EventPending && filter.Status != models.EventPublished {
+		return nil, apperror.Invalidf("status must be pending or published")
+	}
+	events, err := uc.eventRepo.List(ctx, filter, listLimit)
+	if err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = []models.OutboxEvent{}
+	}
+	return events, nil
+}
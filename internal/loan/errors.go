@@ -0,0 +1,19 @@
+package loan
+
+import "github.com/evrintobing17/loan-billing-system/pkg/apperror"
+
+var (
+	ErrNotFound                = apperror.New(apperror.NotFound, "loan_not_found", "loan not found")
+	ErrNotActive               = apperror.New(apperror.Conflict, "loan_not_active", "loan is not active")
+	ErrInvalidVirtualAccount   = apperror.New(apperror.Invalid, "invalid_virtual_account", "virtual account number is malformed or its check digit is wrong")
+	ErrUnknownProduct          = apperror.New(apperror.Invalid, "unknown_product", "loan product does not exist")
+	ErrScheduleVersionNotFound = apperror.New(apperror.NotFound, "schedule_version_not_found", "schedule version does not exist")
+	ErrNothingToRestructure    = apperror.New(apperror.Unprocessable, "nothing_to_restructure", "loan has no open installments to restructure")
+	ErrArrearsNotCapitalised   = apperror.New(apperror.Unprocessable, "arrears_not_capitalised", "loan has arrears; settle them or capitalise them into the new schedule")
+	ErrScheduleChanged         = apperror.New(apperror.Conflict, "schedule_changed", "loan schedule changed, retry the restructure")
+	ErrBorrowerRequired        = apperror.New(apperror.Invalid, "borrower_id_required", "borrower_id is required while exposure limits are set")
+	ErrFeesExceedPrincipal     = apperror.New(apperror.Unprocessable, "fees_exceed_principal", "deducted fees leave nothing of the principal to disburse")
+	ErrRateOutOfRange          = apperror.New(apperror.Unprocessable, "rate_out_of_range", "loan terms give an APR or effective annual rate too large to disclose")
+	// ErrExposureLimitExceeded carries the breached limits in its "breaches" field.
+	ErrExposureLimitExceeded = apperror.New(apperror.Unprocessable, "exposure_limit_exceeded", "borrower exceeds exposure limits")
+)
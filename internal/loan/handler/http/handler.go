@@ -7,6 +7,8 @@ import (
 
 	"github.com/evrintobing17/loan-billing-system/internal/loan"
 	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/evrintobing17/loan-billing-system/pkg/middleware"
 	"github.com/gin-gonic/gin"
 )
 
@@ -20,52 +22,148 @@ func NewLoanHandler(uc loan.LoanUsecase) *LoanHandler {
 
 // CreateLoan godoc
 // @Summary Create a new loan
-// @Description Create a loan with given terms. Generates weekly installments.
+// @Description Book a loan directly, bypassing the application workflow (admin only). Generates weekly installments, applying the fees of the optional loan product. The loan stays pending until fully disbursed. Refused with 422 and the breached limits if the loan would exceed the borrower's exposure limits.
 // @Tags loans
 // @Accept json
 // @Produce json
+// @Param X-Admin-Key header string true "Admin key"
 // @Param request body models.CreateLoanRequest true "Loan details"
 // @Success 201 {object} models.Loan
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 500 {object} models.Problem
 // @Router /loans [post]
 func (h *LoanHandler) CreateLoan(c *gin.Context) {
 	var req models.CreateLoanRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.Default(err, apperror.Invalid))
 		return
 	}
 
-	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	startDate, err := parseStartDate(req.StartDate)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date format, use YYYY-MM-DD"})
+		c.Error(err)
 		return
 	}
 
-	loan, err := h.loanUC.CreateLoan(c.Request.Context(), req.Principal, req.InterestRate, req.TermWeeks, startDate)
+	loan, err := h.loanUC.CreateLoan(c.Request.Context(), loanTerms(req, startDate))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusCreated, loan)
 }
 
+// QuoteLoan godoc
+// @Summary Quote a loan without creating it
+// @Description Simulate a loan with the same input as loan creation. Returns the installment schedule and totals; nothing is persisted.
+// @Tags loans
+// @Accept json
+// @Produce json
+// @Param request body models.CreateLoanRequest true "Loan details"
+// @Success 200 {object} models.LoanQuote
+// @Failure 400 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /loans/quote [post]
+func (h *LoanHandler) QuoteLoan(c *gin.Context) {
+	var req models.CreateLoanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+
+	startDate, err := parseStartDate(req.StartDate)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	quote, err := h.loanUC.QuoteLoan(c.Request.Context(), loanTerms(req, startDate))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, quote)
+}
+
+func loanTerms(req models.CreateLoanRequest, startDate time.Time) models.LoanTerms {
+	return models.LoanTerms{
+		BorrowerID:   req.BorrowerID,
+		Principal:    req.Principal,
+		InterestRate: req.InterestRate,
+		TermWeeks:    req.TermWeeks,
+		StartDate:    startDate,
+		ProductCode:  req.ProductCode,
+	}
+}
+
+func parseStartDate(raw string) (time.Time, error) {
+	startDate, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return time.Time{}, apperror.Invalidf("invalid start_date format, use YYYY-MM-DD")
+	}
+	return startDate, nil
+}
+
+// GetLoan godoc
+// @Summary Get a loan
+// @Tags loans
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Success 200 {object} models.Loan
+// @Failure 404 {object} models.Problem
+// @Router /loans/{id} [get]
+func (h *LoanHandler) GetLoan(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid loan id"))
+		return
+	}
+	loan, err := h.loanUC.GetLoan(c.Request.Context(), id)
+	if err != nil {
+		c.Error(apperror.Default(err, apperror.NotFound))
+		return
+	}
+	c.JSON(http.StatusOK, loan)
+}
+
+// GetByVirtualAccount godoc
+// @Summary Look up a loan by virtual account
+// @Description Resolves a virtual account number to its loan. Spaces and dashes in the number are ignored; a wrong check digit is rejected without a lookup.
+// @Tags loans
+// @Produce json
+// @Param number path string true "Virtual account number"
+// @Success 200 {object} models.Loan
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Router /virtual-accounts/{number} [get]
+func (h *LoanHandler) GetByVirtualAccount(c *gin.Context) {
+	loan, err := h.loanUC.GetByVirtualAccount(c.Request.Context(), c.Param("number"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, loan)
+}
+
 // GetOutstanding godoc
 // @Summary Get outstanding amount for a loan
 // @Tags loans
 // @Param id path int true "Loan ID"
 // @Success 200 {object} map[string]float64
-// @Failure 404 {object} map[string]string
+// @Failure 404 {object} models.Problem
 // @Router /loans/{id}/outstanding [get]
 func (h *LoanHandler) GetOutstanding(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid loan id"})
+		c.Error(apperror.Invalidf("invalid loan id"))
 		return
 	}
 	outstanding, err := h.loanUC.GetOutstanding(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.Error(apperror.Default(err, apperror.NotFound))
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"outstanding": outstanding})
@@ -73,21 +171,129 @@ func (h *LoanHandler) GetOutstanding(c *gin.Context) {
 
 // IsDelinquent godoc
 // @Summary Check if a borrower is delinquent
+// @Description Classify the loan as current, past_due or delinquent as of the business date, flagging restructured loans.
 // @Tags loans
 // @Param id path int true "Loan ID"
-// @Success 200 {object} map[string]bool
-// @Failure 404 {object} map[string]string
+// @Param X-As-Of-Date header string false "Business date override, YYYY-MM-DD (admin only)"
+// @Param X-Admin-Key header string false "Admin key, required with X-As-Of-Date"
+// @Success 200 {object} models.DelinquencyStatus
+// @Failure 404 {object} models.Problem
 // @Router /loans/{id}/delinquent [get]
 func (h *LoanHandler) IsDelinquent(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid loan id"})
+		c.Error(apperror.Invalidf("invalid loan id"))
+		return
+	}
+	status, err := h.loanUC.ClassifyDelinquency(c.Request.Context(), id)
+	if err != nil {
+		c.Error(apperror.Default(err, apperror.NotFound))
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// RestructureLoan godoc
+// @Summary Restructure a loan's remaining schedule
+// @Description Close the open installments of an active loan and generate a new schedule from the remaining principal (admin only). Supports a new term and rate, a payment holiday and capitalised arrears. The old schedule is kept under its version number.
+// @Tags loans
+// @Accept json
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param X-User-ID header string true "Requesting user"
+// @Param request body models.RestructureRequest true "New terms"
+// @Success 201 {object} models.Restructure
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Router /loans/{id}/restructure [post]
+func (h *LoanHandler) RestructureLoan(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid loan id"))
+		return
+	}
+	actor := middleware.Actor(c)
+	if actor == "" {
+		c.Error(middleware.ErrActorRequired)
+		return
+	}
+
+	var req models.RestructureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+
+	restructure, err := h.loanUC.RestructureLoan(c.Request.Context(), id, models.RestructureTerms{
+		TermWeeks:         req.TermWeeks,
+		InterestRate:      req.InterestRate,
+		HolidayWeeks:      req.HolidayWeeks,
+		CapitaliseArrears: req.CapitaliseArrears,
+		Reason:            req.Reason,
+		RequestedBy:       actor,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, restructure)
+}
+
+// GetSchedule godoc
+// @Summary Get a loan's installment schedule
+// @Description Return the current schedule, or an earlier version kept for audit.
+// @Tags loans
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Param version query int false "Schedule version, defaults to the current one"
+// @Success 200 {object} models.LoanSchedule
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Router /loans/{id}/schedule [get]
+func (h *LoanHandler) GetSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid loan id"))
+		return
+	}
+	version := 0
+	if raw := c.Query("version"); raw != "" {
+		version, err = strconv.Atoi(raw)
+		if err != nil {
+			c.Error(apperror.Invalidf("invalid version"))
+			return
+		}
+	}
+	schedule, err := h.loanUC.GetSchedule(c.Request.Context(), id, version)
+	if err != nil {
+		c.Error(apperror.Default(err, apperror.NotFound))
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// GetRestructures godoc
+// @Summary List restructures of a loan
+// @Tags loans
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Success 200 {array} models.Restructure
+// @Failure 404 {object} models.Problem
+// @Router /loans/{id}/restructures [get]
+func (h *LoanHandler) GetRestructures(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid loan id"))
 		return
 	}
-	delinquent, err := h.loanUC.IsDelinquent(c.Request.Context(), id)
+	restructures, err := h.loanUC.GetRestructures(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.Error(apperror.Default(err, apperror.NotFound))
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"delinquent": delinquent})
+	c.JSON(http.StatusOK, restructures)
 }
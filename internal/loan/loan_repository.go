@@ -2,13 +2,42 @@ package loan
 
 import (
 	"context"
+	"time"
 
 	"github.com/evrintobing17/loan-billing-system/models"
 )
 
 type LoanRepository interface {
-	Create(ctx context.Context, loan *models.Loan, installments []models.Installment) error
+	Create(ctx context.Context, loan *models.Loan, installments []models.Installment, charges []models.LoanCharge) error
 	GetByID(ctx context.Context, id int) (*models.Loan, error)
+	// GetForUpdate reads the loan and locks its row until the surrounding
+	// transaction ends, so that checks on its state hold until the change
+	// made on them commits.
+	GetForUpdate(ctx context.Context, id int) (*models.Loan, error)
+	GetByVirtualAccount(ctx context.Context, number string) (*models.Loan, error)
+	// SetVirtualAccount assigns a loan its number once; it returns
+	// sql.ErrNoRows if the loan has one already.
+	SetVirtualAccount(ctx context.Context, loanID int, number string) error
+	ListWithoutVirtualAccount(ctx context.Context) ([]int, error)
+	ListByStatus(ctx context.Context, status string) ([]models.Loan, error)
+	ListByBorrower(ctx context.Context, borrowerID string) ([]models.Loan, error)
+	// MaxDaysPaidLate returns the most days any installment of the
+	// borrower's loans was paid after it fell due; reversed payments do not
+	// count.
+	MaxDaysPaidLate(ctx context.Context, borrowerID string) (int, error)
+	// LockBorrower serialises work on the borrower's loans until the surrounding transaction ends.
+	LockBorrower(ctx context.Context, borrowerID string) error
 	GetInstallments(ctx context.Context, loanID int) ([]models.Installment, error)
-	UpdateInstallmentsPaid(ctx context.Context, installmentIDs []int) error
+	GetCharges(ctx context.Context, loanID int) ([]models.LoanCharge, error)
+	WriteOff(ctx context.Context, loanID int, amount float64, date time.Time) error
+	// AddRecovery returns sql.ErrNoRows when the recoveries would exceed the
+	// written-off balance.
+	AddRecovery(ctx context.Context, loanID int, amount float64) error
+	LinkRefinance(ctx context.Context, previousLoanID, newLoanID int) error
+	GetScheduleVersion(ctx context.Context, loanID, version int) ([]models.Installment, []models.LoanCharge, error)
+	Restructure(ctx context.Context, restructure *models.Restructure, installments []models.Installment, charges []models.LoanCharge) error
+	GetRestructures(ctx context.Context, loanID int) ([]models.Restructure, error)
+	// RecordDelinquent records the loan.delinquent event once per
+	// delinquency, which is told apart by the oldest installment left unpaid.
+	RecordDelinquent(ctx context.Context, d models.LoanDelinquency, oldestUnpaid models.Installment, businessDate time.Time) error
 }
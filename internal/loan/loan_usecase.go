@@ -8,7 +8,25 @@ import (
 )
 
 type LoanUsecase interface {
-	CreateLoan(ctx context.Context, principal, rate float64, termWeeks int, startDate time.Time) (*models.Loan, error)
+	CreateLoan(ctx context.Context, terms models.LoanTerms) (*models.Loan, error)
+	QuoteLoan(ctx context.Context, terms models.LoanTerms) (*models.LoanQuote, error)
+	GetLoan(ctx context.Context, loanID int) (*models.Loan, error)
+	// GetByVirtualAccount resolves a virtual account number, spaces and
+	// dashes allowed, to its loan.
+	GetByVirtualAccount(ctx context.Context, number string) (*models.Loan, error)
+	// AssignVirtualAccounts gives loans booked before virtual accounts
+	// existed their number and returns how many it assigned.
+	AssignVirtualAccounts(ctx context.Context) (int, error)
 	GetOutstanding(ctx context.Context, loanID int) (float64, error)
 	IsDelinquent(ctx context.Context, loanID int) (bool, error)
+	GetDaysPastDue(ctx context.Context, loanID int) (int, error)
+	ClassifyDelinquency(ctx context.Context, loanID int) (*models.DelinquencyStatus, error)
+	// SweepDelinquency classifies every active loan as of businessDate (today
+	// when zero) and publishes the loans found delinquent.
+	SweepDelinquency(ctx context.Context, businessDate time.Time) (*models.DelinquencySweep, error)
+	RestructureLoan(ctx context.Context, loanID int, terms models.RestructureTerms) (*models.Restructure, error)
+	// GetSchedule returns the given schedule version, or the current one when version is 0.
+	GetSchedule(ctx context.Context, loanID, version int) (*models.LoanSchedule, error)
+	GetRestructures(ctx context.Context, loanID int) ([]models.Restructure, error)
+	GetBorrowerExposure(ctx context.Context, borrowerID string) (*models.BorrowerExposure, error)
 }
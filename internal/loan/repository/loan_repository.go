@@ -5,10 +5,12 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/evrintobing17/loan-billing-system/internal/loan"
 	"github.com/evrintobing17/loan-billing-system/models"
-	"github.com/lib/pq"
+	"github.com/evrintobing17/loan-billing-system/pkg/outbox"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
 )
 
 type loanRepository struct {
@@ -21,65 +23,313 @@ func NewLoanRepository(DB *sql.DB) loan.LoanRepository {
 	}
 }
 
-// Create implements [loan.LoanRepository].
-func (l *loanRepository) Create(ctx context.Context, loan *models.Loan, installments []models.Installment) error {
-	tx, err := l.DB.BeginTx(ctx, nil)
+const loanColumns = `id, borrower_id, principal, interest_rate, term_weeks, weekly_amount, start_date, is_active, created_at,
+                     apr, effective_annual_rate, product_id, origination_fee, net_disbursement,
+                     status, disbursed_amount, first_disbursement_date,
+                     written_off_amount, written_off_date, recovered_amount,
+                     schedule_version, restructured, refinances_loan_id, refinanced_by_loan_id, virtual_account`
+
+func scanLoan(row interface{ Scan(...any) error }, loan *models.Loan) error {
+	var productID, refinances, refinancedBy sql.NullInt64
+	var firstDisbursement, writtenOffDate sql.NullTime
+	var virtualAccount sql.NullString
+	err := row.Scan(
+		&loan.ID,
+		&loan.BorrowerID,
+		&loan.Principal,
+		&loan.InterestRate,
+		&loan.TermWeeks,
+		&loan.WeeklyAmount,
+		&loan.StartDate,
+		&loan.IsActive,
+		&loan.CreatedAt,
+		&loan.APR,
+		&loan.EffectiveAnnualRate,
+		&productID,
+		&loan.OriginationFee,
+		&loan.NetDisbursement,
+		&loan.Status,
+		&loan.DisbursedAmount,
+		&firstDisbursement,
+		&loan.WrittenOffAmount,
+		&writtenOffDate,
+		&loan.RecoveredAmount,
+		&loan.ScheduleVersion,
+		&loan.Restructured,
+		&refinances,
+		&refinancedBy,
+		&virtualAccount,
+	)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback()
+	loan.VirtualAccount = virtualAccount.String
+	if productID.Valid {
+		id := int(productID.Int64)
+		loan.ProductID = &id
+	}
+	if firstDisbursement.Valid {
+		loan.FirstDisbursementDate = &firstDisbursement.Time
+	}
+	if writtenOffDate.Valid {
+		loan.WrittenOffDate = &writtenOffDate.Time
+	}
+	if refinances.Valid {
+		id := int(refinances.Int64)
+		loan.RefinancesLoanID = &id
+	}
+	if refinancedBy.Valid {
+		id := int(refinancedBy.Int64)
+		loan.RefinancedByLoanID = &id
+	}
+	return nil
+}
 
-	// Insert loan
-	query := `INSERT INTO loans (principal, interest_rate, term_weeks, weekly_amount, start_date, is_active) 
-              VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
-	err = tx.QueryRowContext(ctx, query, loan.Principal, loan.InterestRate, loan.TermWeeks,
-		loan.WeeklyAmount, loan.StartDate, true).Scan(&loan.ID, &loan.CreatedAt)
+// Create implements [loan.LoanRepository]. The loan.created event goes to
+// the outbox with the loan.
+func (l *loanRepository) Create(ctx context.Context, loan *models.Loan, installments []models.Installment, charges []models.LoanCharge) error {
+	return postgres.RunInTx(ctx, l.DB, func(tx postgres.DBTX) error {
+		// Insert loan
+		query := `INSERT INTO loans (principal, interest_rate, term_weeks, weekly_amount, start_date, is_active,
+                                     apr, effective_annual_rate, product_id, origination_fee, net_disbursement, status,
+                                     borrower_id) 
+                  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, created_at`
+		err := tx.QueryRowContext(ctx, query, loan.Principal, loan.InterestRate, loan.TermWeeks,
+			loan.WeeklyAmount, loan.StartDate, true, loan.APR, loan.EffectiveAnnualRate,
+			loan.ProductID, loan.OriginationFee, loan.NetDisbursement, loan.Status,
+			loan.BorrowerID).Scan(&loan.ID, &loan.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		loan.ScheduleVersion = 1
+		if err := insertSchedule(ctx, tx, loan.ID, loan.ScheduleVersion, installments, charges); err != nil {
+			return err
+		}
+
+		created, err := outbox.LoanEvent(models.EventLoanCreated, 1, loan.ID, loan.CreatedAt, models.LoanCreatedV1{
+			LoanID:          loan.ID,
+			BorrowerID:      loan.BorrowerID,
+			ProductID:       loan.ProductID,
+			Principal:       loan.Principal,
+			InterestRate:    loan.InterestRate,
+			TermWeeks:       loan.TermWeeks,
+			WeeklyAmount:    loan.WeeklyAmount,
+			StartDate:       loan.StartDate,
+			NetDisbursement: loan.NetDisbursement,
+			Status:          loan.Status,
+		})
+		if err != nil {
+			return err
+		}
+		return outbox.Append(ctx, tx, created)
+	})
+}
+
+// RecordDelinquent implements [loan.LoanRepository].
+func (l *loanRepository) RecordDelinquent(ctx context.Context, d models.LoanDelinquency, oldestUnpaid models.Installment, businessDate time.Time) error {
+	e, err := outbox.LoanEvent(models.EventLoanDelinquent, 1, d.LoanID, businessDate, models.LoanDelinquentV1{
+		LoanID:                    d.LoanID,
+		BorrowerID:                d.BorrowerID,
+		BusinessDate:              businessDate,
+		DaysPastDue:               d.DaysPastDue,
+		OldestUnpaidInstallmentID: oldestUnpaid.ID,
+	})
 	if err != nil {
 		return err
 	}
+	e.DedupKey = fmt.Sprintf("%s:%d:%d", models.EventLoanDelinquent, d.LoanID, oldestUnpaid.ID)
+	return outbox.Append(ctx, postgres.Conn(ctx, l.DB), e)
+}
 
-	// Insert installments
+// insertSchedule stores installments and separately charged fees as the given
+// schedule version of a loan.
+func insertSchedule(ctx context.Context, tx postgres.DBTX, loanID, version int, installments []models.Installment, charges []models.LoanCharge) error {
 	for _, inst := range installments {
-		_, err = tx.ExecContext(ctx,
-			`INSERT INTO installments (loan_id, week_number, due_date, amount) VALUES ($1, $2, $3, $4)`,
-			loan.ID, inst.WeekNumber, inst.DueDate, inst.Amount)
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO installments (loan_id, schedule_version, week_number, due_date, amount, principal_amount, interest_amount, fee_amount)
+             VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			loanID, version, inst.WeekNumber, inst.DueDate, inst.Amount, inst.PrincipalAmount, inst.InterestAmount, inst.FeeAmount)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Insert separately charged fees
+	for _, charge := range charges {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO loan_charges (loan_id, schedule_version, fee_type, due_date, amount) VALUES ($1, $2, $3, $4, $5)`,
+			loanID, version, charge.FeeType, charge.DueDate, charge.Amount)
 		if err != nil {
 			return err
 		}
 	}
-	return tx.Commit()
+	return nil
 }
 
+// GetByID returns loan.ErrNotFound, which also matches sql.ErrNoRows, for an
+// unknown id.
 func (l *loanRepository) GetByID(ctx context.Context, id int) (*models.Loan, error) {
-	var loan models.Loan
-	query := `SELECT id, principal, interest_rate, term_weeks, weekly_amount, start_date, is_active, created_at 
-              FROM loans WHERE id = $1 AND is_active IS TRUE`
-	err := l.DB.QueryRowContext(ctx, query, id).Scan(
-		&loan.ID,
-		&loan.Principal,
-		&loan.InterestRate,
-		&loan.TermWeeks,
-		&loan.WeeklyAmount,
-		&loan.StartDate,
-		&loan.IsActive,
-		&loan.CreatedAt,
-	)
+	return l.get(ctx, `SELECT `+loanColumns+` FROM loans WHERE id = $1`, id)
+}
+
+// GetForUpdate implements [loan.LoanRepository].
+func (l *loanRepository) GetForUpdate(ctx context.Context, id int) (*models.Loan, error) {
+	return l.get(ctx, `SELECT `+loanColumns+` FROM loans WHERE id = $1 FOR UPDATE`, id)
+}
+
+func (l *loanRepository) get(ctx context.Context, query string, id int) (*models.Loan, error) {
+	var found models.Loan
+	err := scanLoan(postgres.Conn(ctx, l.DB).QueryRowContext(ctx, query, id), &found)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, err // caller can check with errors.Is
+			return nil, loan.ErrNotFound.Wrap(err)
 		}
 		return nil, fmt.Errorf("query loan by id: %w", err)
 	}
-	return &loan, nil
+	return &found, nil
+}
+
+// GetByVirtualAccount returns loan.ErrNotFound for an unknown number.
+func (l *loanRepository) GetByVirtualAccount(ctx context.Context, number string) (*models.Loan, error) {
+	var found models.Loan
+	query := `SELECT ` + loanColumns + ` 
+              FROM loans WHERE virtual_account = $1`
+	err := scanLoan(postgres.Conn(ctx, l.DB).QueryRowContext(ctx, query, number), &found)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, loan.ErrNotFound.Wrap(err)
+		}
+		return nil, fmt.Errorf("query loan by virtual account: %w", err)
+	}
+	return &found, nil
+}
+
+// SetVirtualAccount implements [loan.LoanRepository].
+func (l *loanRepository) SetVirtualAccount(ctx context.Context, loanID int, number string) error {
+	res, err := postgres.Conn(ctx, l.DB).ExecContext(ctx,
+		`UPDATE loans SET virtual_account = $2 WHERE id = $1 AND virtual_account IS NULL`, loanID, number)
+	if err != nil {
+		return fmt.Errorf("set virtual account: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ListWithoutVirtualAccount returns the IDs of loans booked before virtual
+// accounts were introduced.
+func (l *loanRepository) ListWithoutVirtualAccount(ctx context.Context) ([]int, error) {
+	rows, err := postgres.Conn(ctx, l.DB).QueryContext(ctx, `SELECT id FROM loans WHERE virtual_account IS NULL ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("query loans without virtual account: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan loan id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return ids, nil
+}
+
+// ListByStatus returns the loans in the given status, ordered by id.
+func (l *loanRepository) ListByStatus(ctx context.Context, status string) ([]models.Loan, error) {
+	return l.list(ctx, `WHERE status = $1`, status)
+}
+
+// ListByBorrower returns all loans of a borrower, whatever their status, ordered by id.
+func (l *loanRepository) ListByBorrower(ctx context.Context, borrowerID string) ([]models.Loan, error) {
+	return l.list(ctx, `WHERE borrower_id = $1`, borrowerID)
+}
+
+// MaxDaysPaidLate implements [loan.LoanRepository]. Installments superseded
+// by a restructure count too: they are part of the borrower's record.
+func (l *loanRepository) MaxDaysPaidLate(ctx context.Context, borrowerID string) (int, error) {
+	query := `SELECT COALESCE(MAX(p.payment_date::date - i.due_date), 0)
+              FROM loans l
+              JOIN installments i ON i.loan_id = l.id
+              JOIN payment_installments pi ON pi.installment_id = i.id
+              JOIN payments p ON p.id = pi.payment_id
+              WHERE l.borrower_id = $1 AND p.reversed_at IS NULL`
+	var days int
+	if err := postgres.Conn(ctx, l.DB).QueryRowContext(ctx, query, borrowerID).Scan(&days); err != nil {
+		return 0, fmt.Errorf("query days paid late: %w", err)
+	}
+	return max(days, 0), nil
+}
+
+func (l *loanRepository) LockBorrower(ctx context.Context, borrowerID string) error {
+	_, err := postgres.Conn(ctx, l.DB).ExecContext(ctx,
+		`SELECT pg_advisory_xact_lock(hashtext('borrower'), hashtext($1))`, borrowerID)
+	if err != nil {
+		return fmt.Errorf("lock borrower: %w", err)
+	}
+	return nil
+}
+
+func (l *loanRepository) list(ctx context.Context, filter string, args ...any) ([]models.Loan, error) {
+	query := `SELECT ` + loanColumns + `
+              FROM loans ` + filter + ` ORDER BY id`
+	rows, err := postgres.Conn(ctx, l.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query loans: %w", err)
+	}
+	defer rows.Close()
+
+	var loans []models.Loan
+	for rows.Next() {
+		var loan models.Loan
+		if err := scanLoan(rows, &loan); err != nil {
+			return nil, fmt.Errorf("scan loan: %w", err)
+		}
+		loans = append(loans, loan)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return loans, nil
 }
 
-// GetInstallments retrieves all installments for a given loan, ordered by week_number.
+// GetInstallments retrieves the installments of the loan's current schedule, ordered by week_number.
 func (l *loanRepository) GetInstallments(ctx context.Context, loanID int) ([]models.Installment, error) {
-	query := `SELECT id, loan_id, week_number, due_date, amount, paid 
+	return l.queryInstallments(ctx, `
+              AND schedule_version = (SELECT schedule_version FROM loans WHERE id = $1)`, loanID)
+}
+
+// GetScheduleVersion retrieves the installments and charges of one schedule
+// version, including superseded ones kept for audit.
+func (l *loanRepository) GetScheduleVersion(ctx context.Context, loanID, version int) ([]models.Installment, []models.LoanCharge, error) {
+	installments, err := l.queryInstallments(ctx, ` AND schedule_version = $2`, loanID, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	charges, err := l.queryCharges(ctx, ` AND schedule_version = $2`, loanID, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	return installments, charges, nil
+}
+
+func (l *loanRepository) queryInstallments(ctx context.Context, filter string, args ...any) ([]models.Installment, error) {
+	query := `SELECT id, loan_id, week_number, due_date, amount, paid, principal_amount, interest_amount, fee_amount,
+                     schedule_version, closed
               FROM installments 
-              WHERE loan_id = $1 
+              WHERE loan_id = $1` + filter + `
               ORDER BY week_number`
-	rows, err := l.DB.QueryContext(ctx, query, loanID)
+	rows, err := postgres.Conn(ctx, l.DB).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query installments: %w", err)
 	}
@@ -95,6 +345,11 @@ func (l *loanRepository) GetInstallments(ctx context.Context, loanID int) ([]mod
 			&inst.DueDate,
 			&inst.Amount,
 			&inst.Paid,
+			&inst.PrincipalAmount,
+			&inst.InterestAmount,
+			&inst.FeeAmount,
+			&inst.ScheduleVersion,
+			&inst.Closed,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan installment: %w", err)
@@ -107,17 +362,202 @@ func (l *loanRepository) GetInstallments(ctx context.Context, loanID int) ([]mod
 	return installments, nil
 }
 
-// UpdateInstallmentsPaid marks the given installment IDs as paid.
-// This method is typically called within a transaction from the payment repository.
-func (l *loanRepository) UpdateInstallmentsPaid(ctx context.Context, installmentIDs []int) error {
-	if len(installmentIDs) == 0 {
-		return nil // nothing to do
+// GetCharges retrieves the separately charged fees of the loan's current schedule, ordered by due date.
+func (l *loanRepository) GetCharges(ctx context.Context, loanID int) ([]models.LoanCharge, error) {
+	return l.queryCharges(ctx, `
+              AND schedule_version = (SELECT schedule_version FROM loans WHERE id = $1)`, loanID)
+}
+
+func (l *loanRepository) queryCharges(ctx context.Context, filter string, args ...any) ([]models.LoanCharge, error) {
+	query := `SELECT id, loan_id, fee_type, due_date, amount, paid, schedule_version, closed
+              FROM loan_charges
+              WHERE loan_id = $1` + filter + `
+              ORDER BY due_date, id`
+	rows, err := postgres.Conn(ctx, l.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query charges: %w", err)
 	}
+	defer rows.Close()
+
+	var charges []models.LoanCharge
+	for rows.Next() {
+		var charge models.LoanCharge
+		err := rows.Scan(
+			&charge.ID,
+			&charge.LoanID,
+			&charge.FeeType,
+			&charge.DueDate,
+			&charge.Amount,
+			&charge.Paid,
+			&charge.ScheduleVersion,
+			&charge.Closed,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan charge: %w", err)
+		}
+		charges = append(charges, charge)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return charges, nil
+}
 
-	query := `UPDATE installments SET paid = true WHERE id = ANY($1)`
-	_, err := l.DB.ExecContext(ctx, query, pq.Array(installmentIDs))
+// WriteOff moves an active loan off the book with the given written-off
+// balance. It returns loan.ErrNotActive if the loan is not active.
+func (l *loanRepository) WriteOff(ctx context.Context, loanID int, amount float64, date time.Time) error {
+	query := `UPDATE loans
+              SET status = $2, is_active = false, written_off_amount = $3, written_off_date = $4
+              WHERE id = $1 AND status = $5`
+	res, err := postgres.Conn(ctx, l.DB).ExecContext(ctx, query, loanID, models.LoanStatusWrittenOff, amount, date,
+		models.LoanStatusActive)
+	if err != nil {
+		return fmt.Errorf("write off loan: %w", err)
+	}
+	n, err := res.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("update installments paid: %w", err)
+		return fmt.Errorf("write off loan: %w", err)
+	}
+	if n == 0 {
+		return loan.ErrNotActive.Withf("only active loans can be written off")
 	}
 	return nil
 }
+
+// LinkRefinance closes a settled loan as refinanced and links it with the
+// loan that replaced it. It returns loan.ErrNotActive if the previous loan is
+// no longer active or was refinanced already.
+func (l *loanRepository) LinkRefinance(ctx context.Context, previousLoanID, newLoanID int) error {
+	return postgres.RunInTx(ctx, l.DB, func(tx postgres.DBTX) error {
+		res, err := tx.ExecContext(ctx,
+			`UPDATE loans SET status = $2, is_active = false, refinanced_by_loan_id = $3
+             WHERE id = $1 AND status = $4 AND refinanced_by_loan_id IS NULL`,
+			previousLoanID, models.LoanStatusRefinanced, newLoanID, models.LoanStatusActive)
+		if err != nil {
+			return fmt.Errorf("close refinanced loan: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("close refinanced loan: %w", err)
+		}
+		if n == 0 {
+			return loan.ErrNotActive.Withf("only active loans can be topped up")
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE loans SET refinances_loan_id = $2 WHERE id = $1`, newLoanID, previousLoanID)
+		if err != nil {
+			return fmt.Errorf("link refinancing loan: %w", err)
+		}
+		return nil
+	})
+}
+
+// AddRecovery adds a recovery collected on a written-off loan. It returns
+// sql.ErrNoRows, changing nothing, when the recoveries would exceed the
+// written-off balance.
+func (l *loanRepository) AddRecovery(ctx context.Context, loanID int, amount float64) error {
+	query := `UPDATE loans SET recovered_amount = recovered_amount + $2
+              WHERE id = $1 AND recovered_amount + $2 <= written_off_amount + 0.005`
+	res, err := postgres.Conn(ctx, l.DB).ExecContext(ctx, query, loanID, amount)
+	if err != nil {
+		return fmt.Errorf("add recovery: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("add recovery: %w", err)
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Restructure closes the open items of the current schedule, stores the new
+// schedule under the next version and records the restructure.
+func (l *loanRepository) Restructure(ctx context.Context, r *models.Restructure, installments []models.Installment, charges []models.LoanCharge) error {
+	return postgres.RunInTx(ctx, l.DB, func(tx postgres.DBTX) error {
+		// Lock the loan so concurrent restructures cannot claim the same version.
+		var current int
+		err := tx.QueryRowContext(ctx, `SELECT schedule_version FROM loans WHERE id = $1 FOR UPDATE`, r.LoanID).Scan(&current)
+		if err != nil {
+			return err
+		}
+		if current != r.FromVersion {
+			return loan.ErrScheduleChanged
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`UPDATE installments SET closed = true WHERE loan_id = $1 AND schedule_version = $2 AND paid IS FALSE`,
+			r.LoanID, r.FromVersion)
+		if err != nil {
+			return fmt.Errorf("close installments: %w", err)
+		}
+		_, err = tx.ExecContext(ctx,
+			`UPDATE loan_charges SET closed = true WHERE loan_id = $1 AND schedule_version = $2 AND paid IS FALSE`,
+			r.LoanID, r.FromVersion)
+		if err != nil {
+			return fmt.Errorf("close charges: %w", err)
+		}
+		if err := insertSchedule(ctx, tx, r.LoanID, r.ToVersion, installments, charges); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			`UPDATE loans SET schedule_version = $2, restructured = true WHERE id = $1`, r.LoanID, r.ToVersion)
+		if err != nil {
+			return fmt.Errorf("update schedule version: %w", err)
+		}
+
+		query := `INSERT INTO loan_restructures (loan_id, from_version, to_version, restructure_date, term_weeks, interest_rate,
+                                                 holiday_weeks, remaining_principal, capitalised_arrears, new_principal,
+                                                 weekly_amount, recognised_interest, reason, requested_by)
+                  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id, created_at`
+		return tx.QueryRowContext(ctx, query, r.LoanID, r.FromVersion, r.ToVersion, r.RestructureDate, r.TermWeeks,
+			r.InterestRate, r.HolidayWeeks, r.RemainingPrincipal, r.CapitalisedArrears, r.NewPrincipal,
+			r.WeeklyAmount, r.RecognisedInterest, r.Reason, r.RequestedBy).Scan(&r.ID, &r.CreatedAt)
+	})
+}
+
+// GetRestructures returns the loan's restructures, oldest first.
+func (l *loanRepository) GetRestructures(ctx context.Context, loanID int) ([]models.Restructure, error) {
+	query := `SELECT id, loan_id, from_version, to_version, restructure_date, term_weeks, interest_rate, holiday_weeks,
+                     remaining_principal, capitalised_arrears, new_principal, weekly_amount, recognised_interest,
+                     reason, requested_by, created_at
+              FROM loan_restructures
+              WHERE loan_id = $1
+              ORDER BY to_version`
+	rows, err := postgres.Conn(ctx, l.DB).QueryContext(ctx, query, loanID)
+	if err != nil {
+		return nil, fmt.Errorf("query restructures: %w", err)
+	}
+	defer rows.Close()
+
+	var restructures []models.Restructure
+	for rows.Next() {
+		var r models.Restructure
+		err := rows.Scan(
+			&r.ID,
+			&r.LoanID,
+			&r.FromVersion,
+			&r.ToVersion,
+			&r.RestructureDate,
+			&r.TermWeeks,
+			&r.InterestRate,
+			&r.HolidayWeeks,
+			&r.RemainingPrincipal,
+			&r.CapitalisedArrears,
+			&r.NewPrincipal,
+			&r.WeeklyAmount,
+			&r.RecognisedInterest,
+			&r.Reason,
+			&r.RequestedBy,
+			&r.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan restructure: %w", err)
+		}
+		restructures = append(restructures, r)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return restructures, nil
+}
@@ -2,88 +2,812 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
 	"time"
 
+	"github.com/evrintobing17/loan-billing-system/internal/accounting"
+	"github.com/evrintobing17/loan-billing-system/internal/borrower"
 	"github.com/evrintobing17/loan-billing-system/internal/loan"
+	"github.com/evrintobing17/loan-billing-system/internal/product"
 	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+	"github.com/evrintobing17/loan-billing-system/pkg/finance"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+	"github.com/evrintobing17/loan-billing-system/pkg/virtualaccount"
 )
 
 type loanUseCase struct {
-	loanRepo loan.LoanRepository
+	loanRepo     loan.LoanRepository
+	productRepo  product.ProductRepository
+	borrowerRepo borrower.BorrowerRepository
+	accountingUC accounting.AccountingUsecase
+	tx           postgres.Transactor
+	clock        clock.Clock
+	// limits apply to borrowers without limits of their own.
+	limits models.ExposureLimits
+	// vaPrefix starts the virtual account numbers of new loans.
+	vaPrefix string
 }
 
-func NewLoanUseCase(loanRepo loan.LoanRepository) loan.LoanUsecase {
-	return &loanUseCase{loanRepo: loanRepo}
+func NewLoanUseCase(loanRepo loan.LoanRepository, productRepo product.ProductRepository, borrowerRepo borrower.BorrowerRepository, accountingUC accounting.AccountingUsecase, tx postgres.Transactor, clk clock.Clock, limits models.ExposureLimits, vaPrefix string) loan.LoanUsecase {
+	return &loanUseCase{
+		loanRepo:     loanRepo,
+		productRepo:  productRepo,
+		borrowerRepo: borrowerRepo,
+		accountingUC: accountingUC,
+		tx:           tx,
+		clock:        clk,
+		limits:       limits,
+		vaPrefix:     vaPrefix,
+	}
 }
 
-func (uc *loanUseCase) CreateLoan(ctx context.Context, principal, rate float64, termWeeks int, startDate time.Time) (*models.Loan, error) {
-	weeklyAmount := calculateWeeklyAmount(principal, rate, termWeeks)
-
-	loan := &models.Loan{
-		Principal:    principal,
-		InterestRate: rate,
-		TermWeeks:    termWeeks,
-		WeeklyAmount: weeklyAmount,
-		StartDate:    startDate,
-		IsActive:     true,
+func (uc *loanUseCase) CreateLoan(ctx context.Context, terms models.LoanTerms) (*models.Loan, error) {
+	prod, err := uc.lookupProduct(ctx, terms.ProductCode)
+	if err != nil {
+		return nil, err
 	}
-	installments := generateInstallments(loan)
-	err := uc.loanRepo.Create(ctx, loan, installments)
+	loan, installments, charges, err := buildSchedule(terms, prod)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.checkExposure(ctx, loan, prod); err != nil {
+			return err
+		}
+		if err := uc.loanRepo.Create(ctx, loan, installments, charges); err != nil {
+			return err
+		}
+		number, err := virtualaccount.New(uc.vaPrefix, loan.ID)
+		if err != nil {
+			return fmt.Errorf("assign virtual account: %w", err)
+		}
+		loan.VirtualAccount = number
+		if err := uc.loanRepo.SetVirtualAccount(ctx, loan.ID, loan.VirtualAccount); err != nil {
+			return err
+		}
+		return uc.accountingUC.RecordLoanBooked(ctx, loan)
+	})
 
 	return loan, err
 }
 
+// QuoteLoan simulates CreateLoan without persisting anything. It shares
+// buildSchedule with CreateLoan so quotes and booked loans always agree.
+func (uc *loanUseCase) QuoteLoan(ctx context.Context, terms models.LoanTerms) (*models.LoanQuote, error) {
+	prod, err := uc.lookupProduct(ctx, terms.ProductCode)
+	if err != nil {
+		return nil, err
+	}
+	loan, installments, charges, err := buildSchedule(terms, prod)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalInterest, totalRepayable, totalFees float64
+	for _, inst := range installments {
+		totalInterest += inst.InterestAmount
+		totalRepayable += inst.Amount
+		totalFees += inst.FeeAmount
+	}
+	for _, charge := range charges {
+		totalRepayable += charge.Amount
+		totalFees += charge.Amount
+	}
+	totalFees += loan.OriginationFee
+
+	return &models.LoanQuote{
+		Principal:           loan.Principal,
+		InterestRate:        loan.InterestRate,
+		TermWeeks:           loan.TermWeeks,
+		WeeklyAmount:        loan.WeeklyAmount,
+		StartDate:           loan.StartDate,
+		TotalInterest:       round2(totalInterest),
+		TotalRepayable:      round2(totalRepayable),
+		APR:                 loan.APR,
+		EffectiveAnnualRate: loan.EffectiveAnnualRate,
+		ProductCode:         terms.ProductCode,
+		OriginationFee:      loan.OriginationFee,
+		NetDisbursement:     loan.NetDisbursement,
+		TotalFees:           round2(totalFees),
+		Installments:        installments,
+		Charges:             charges,
+	}, nil
+}
+
+func (uc *loanUseCase) GetLoan(ctx context.Context, loanID int) (*models.Loan, error) {
+	return uc.loanRepo.GetByID(ctx, loanID)
+}
+
+func (uc *loanUseCase) GetByVirtualAccount(ctx context.Context, number string) (*models.Loan, error) {
+	number = virtualaccount.Normalize(number)
+	if !virtualaccount.Valid(number) {
+		return nil, loan.ErrInvalidVirtualAccount
+	}
+	return uc.loanRepo.GetByVirtualAccount(ctx, number)
+}
+
+// AssignVirtualAccounts implements [loan.LoanUsecase]. A loan that got its
+// number concurrently is skipped.
+func (uc *loanUseCase) AssignVirtualAccounts(ctx context.Context) (int, error) {
+	ids, err := uc.loanRepo.ListWithoutVirtualAccount(ctx)
+	if err != nil {
+		return 0, err
+	}
+	assigned := 0
+	for _, id := range ids {
+		number, err := virtualaccount.New(uc.vaPrefix, id)
+		if err != nil {
+			return assigned, fmt.Errorf("assign virtual account to loan %d: %w", id, err)
+		}
+		err = uc.loanRepo.SetVirtualAccount(ctx, id, number)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return assigned, err
+		}
+		assigned++
+	}
+	return assigned, nil
+}
+
+// GetOutstanding returns the unpaid installments plus unpaid separate charges.
+// A written-off loan has nothing outstanding on the active book; its balance
+// is tracked as WrittenOffAmount instead.
 func (uc *loanUseCase) GetOutstanding(ctx context.Context, loanID int) (float64, error) {
+	loan, err := uc.loanRepo.GetByID(ctx, loanID)
+	if err != nil {
+		return 0, err
+	}
+	if loan.Status == models.LoanStatusWrittenOff {
+		return 0, nil
+	}
 	installments, err := uc.loanRepo.GetInstallments(ctx, loanID)
 	if err != nil {
 		return 0, err
 	}
+	charges, err := uc.loanRepo.GetCharges(ctx, loanID)
+	if err != nil {
+		return 0, err
+	}
 	var outstanding float64
 	for _, inst := range installments {
 		if !inst.Paid {
 			outstanding += inst.Amount
 		}
 	}
+	for _, charge := range charges {
+		if !charge.Paid {
+			outstanding += charge.Amount
+		}
+	}
 	return outstanding, nil
 }
 
 func (uc *loanUseCase) IsDelinquent(ctx context.Context, loanID int) (bool, error) {
-	installments, err := uc.loanRepo.GetInstallments(ctx, loanID)
+	unpaidPastDue, err := uc.unpaidPastDue(ctx, loanID)
 	if err != nil {
 		return false, err
 	}
-	today := time.Now().Truncate(24 * time.Hour)
+	// check for consecutive weeks
+	for i := 1; i < len(unpaidPastDue); i++ {
+		if unpaidPastDue[i].WeekNumber == unpaidPastDue[i-1].WeekNumber+1 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetDaysPastDue returns how many days the oldest unpaid installment is overdue.
+func (uc *loanUseCase) GetDaysPastDue(ctx context.Context, loanID int) (int, error) {
+	unpaidPastDue, err := uc.unpaidPastDue(ctx, loanID)
+	if err != nil {
+		return 0, err
+	}
+	if len(unpaidPastDue) == 0 {
+		return 0, nil
+	}
+	today := clock.Today(ctx, uc.clock)
+	return int(today.Sub(clock.Date(unpaidPastDue[0].DueDate)).Hours() / 24), nil
+}
+
+// ClassifyDelinquency reports how far behind a loan is, flagging loans whose
+// schedule has been restructured.
+func (uc *loanUseCase) ClassifyDelinquency(ctx context.Context, loanID int) (*models.DelinquencyStatus, error) {
+	loan, err := uc.loanRepo.GetByID(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	delinquent, err := uc.IsDelinquent(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	dpd, err := uc.GetDaysPastDue(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.DelinquencyStatus{
+		Delinquent:      delinquent,
+		Classification:  models.DelinquencyCurrent,
+		DaysPastDue:     dpd,
+		Restructured:    loan.Restructured,
+		ScheduleVersion: loan.ScheduleVersion,
+	}
+	switch {
+	case delinquent:
+		status.Classification = models.DelinquencyDelinquent
+	case dpd > 0:
+		status.Classification = models.DelinquencyPastDue
+	}
+	return status, nil
+}
+
+// SweepDelinquency implements [loan.LoanUsecase]. A loan that cannot be
+// classified is reported in Errors and does not stop the sweep. Delinquent
+// loans are recorded as loan.delinquent events.
+func (uc *loanUseCase) SweepDelinquency(ctx context.Context, businessDate time.Time) (*models.DelinquencySweep, error) {
+	if businessDate.IsZero() {
+		businessDate = clock.Today(ctx, uc.clock)
+	}
+	businessDate = clock.Date(businessDate)
+	ctx = clock.WithAsOfDate(ctx, businessDate)
+
+	loans, err := uc.loanRepo.ListByStatus(ctx, models.LoanStatusActive)
+	if err != nil {
+		return nil, err
+	}
+
+	sweep := &models.DelinquencySweep{BusinessDate: businessDate, Loans: []models.LoanDelinquency{}}
+	for _, l := range loans {
+		sweep.LoansProcessed++
+		status, err := uc.ClassifyDelinquency(ctx, l.ID)
+		if err != nil {
+			sweep.Errors = append(sweep.Errors, fmt.Sprintf("loan %d: %v", l.ID, err))
+			continue
+		}
+		switch status.Classification {
+		case models.DelinquencyDelinquent:
+			sweep.Delinquent++
+		case models.DelinquencyPastDue:
+			sweep.PastDue++
+		default:
+			sweep.Current++
+		}
+		d := models.LoanDelinquency{
+			LoanID:            l.ID,
+			BorrowerID:        l.BorrowerID,
+			DelinquencyStatus: *status,
+		}
+		sweep.Loans = append(sweep.Loans, d)
+		if status.Delinquent {
+			if err := uc.recordDelinquent(ctx, d, businessDate); err != nil {
+				sweep.Errors = append(sweep.Errors, fmt.Sprintf("loan %d: %v", l.ID, err))
+			}
+		}
+	}
+	return sweep, nil
+}
+
+// recordDelinquent publishes that the loan is delinquent. Sweeps repeat
+// daily, so the event is keyed on the oldest unpaid installment: a loan that
+// catches up and falls behind again is reported again.
+func (uc *loanUseCase) recordDelinquent(ctx context.Context, d models.LoanDelinquency, businessDate time.Time) error {
+	unpaidPastDue, err := uc.unpaidPastDue(ctx, d.LoanID)
+	if err != nil {
+		return err
+	}
+	if len(unpaidPastDue) == 0 {
+		return nil
+	}
+	return uc.loanRepo.RecordDelinquent(ctx, d, unpaidPastDue[0], businessDate)
+}
+
+// RestructureLoan replaces the open part of an active loan's schedule. The
+// new schedule amortises the remaining principal, plus overdue interest and
+// fees when arrears are capitalised, over the new term using the same flat
+// amortization as CreateLoan. A payment holiday defers the first new
+// installment. The old schedule is kept, with its open items closed. The
+// loan is locked before its open items are totalled, so a payment committed
+// meanwhile is not restructured again.
+func (uc *loanUseCase) RestructureLoan(ctx context.Context, loanID int, terms models.RestructureTerms) (*models.Restructure, error) {
+	var r *models.Restructure
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		existing, err := uc.loanRepo.GetForUpdate(ctx, loanID)
+		if err != nil {
+			return err
+		}
+		if existing.Status != models.LoanStatusActive {
+			return loan.ErrNotActive.Withf("only active loans can be restructured")
+		}
+		installments, err := uc.loanRepo.GetInstallments(ctx, loanID)
+		if err != nil {
+			return err
+		}
+		charges, err := uc.loanRepo.GetCharges(ctx, loanID)
+		if err != nil {
+			return err
+		}
+
+		today := clock.Today(ctx, uc.clock)
+		var remainingPrincipal, arrearsInterest, arrearsFees float64
+		for _, inst := range installments {
+			if inst.Paid {
+				continue
+			}
+			remainingPrincipal += inst.PrincipalAmount
+			if !inst.DueDate.After(today) {
+				arrearsInterest += inst.InterestAmount
+				arrearsFees += inst.FeeAmount
+			}
+		}
+		for _, charge := range charges {
+			if !charge.Paid && !charge.DueDate.After(today) {
+				arrearsFees += charge.Amount
+			}
+		}
+		if remainingPrincipal <= 0 {
+			return loan.ErrNothingToRestructure
+		}
+		if arrearsInterest+arrearsFees > 0 && !terms.CapitaliseArrears {
+			return loan.ErrArrearsNotCapitalised
+		}
+
+		rate := existing.InterestRate
+		if terms.InterestRate != nil {
+			rate = *terms.InterestRate
+		}
+		newPrincipal := remainingPrincipal + arrearsInterest + arrearsFees
+		schedule := &models.Loan{
+			Principal:    newPrincipal,
+			InterestRate: rate,
+			TermWeeks:    terms.TermWeeks,
+			WeeklyAmount: calculateWeeklyAmount(newPrincipal, rate, terms.TermWeeks),
+			StartDate:    today.AddDate(0, 0, terms.HolidayWeeks*7),
+		}
+		newInstallments := generateInstallments(schedule)
+		servicingFees, err := uc.servicingFees(ctx, existing.ProductID)
+		if err != nil {
+			return err
+		}
+		newCharges := applyServicingFees(newInstallments, servicingFees, newPrincipal)
+
+		r = &models.Restructure{
+			LoanID:             loanID,
+			FromVersion:        existing.ScheduleVersion,
+			ToVersion:          existing.ScheduleVersion + 1,
+			RestructureDate:    today,
+			TermWeeks:          terms.TermWeeks,
+			InterestRate:       rate,
+			HolidayWeeks:       terms.HolidayWeeks,
+			RemainingPrincipal: remainingPrincipal,
+			CapitalisedArrears: arrearsInterest + arrearsFees,
+			NewPrincipal:       newPrincipal,
+			WeeklyAmount:       newInstallments[0].Amount,
+			Reason:             terms.Reason,
+			RequestedBy:        terms.RequestedBy,
+		}
+		if err := uc.accountingUC.RecordRestructure(ctx, r, arrearsInterest, arrearsFees); err != nil {
+			return err
+		}
+		recognised, err := uc.accountingUC.RecognisedInterest(ctx, loanID)
+		if err != nil {
+			return err
+		}
+		r.RecognisedInterest = recognised
+		return uc.loanRepo.Restructure(ctx, r, newInstallments, newCharges)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (uc *loanUseCase) GetSchedule(ctx context.Context, loanID, version int) (*models.LoanSchedule, error) {
+	existing, err := uc.loanRepo.GetByID(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	if version == 0 {
+		version = existing.ScheduleVersion
+	}
+	if version < 1 || version > existing.ScheduleVersion {
+		return nil, loan.ErrScheduleVersionNotFound.Withf("schedule version %d does not exist", version)
+	}
+	installments, charges, err := uc.loanRepo.GetScheduleVersion(ctx, loanID, version)
+	if err != nil {
+		return nil, err
+	}
+	return &models.LoanSchedule{
+		LoanID:       loanID,
+		Version:      version,
+		Current:      version == existing.ScheduleVersion,
+		Installments: installments,
+		Charges:      charges,
+	}, nil
+}
+
+func (uc *loanUseCase) GetRestructures(ctx context.Context, loanID int) ([]models.Restructure, error) {
+	if _, err := uc.loanRepo.GetByID(ctx, loanID); err != nil {
+		return nil, err
+	}
+	return uc.loanRepo.GetRestructures(ctx, loanID)
+}
+
+// GetBorrowerExposure totals the borrower's loans. Pending loans count at
+// their principal; written-off loans count at their unrecovered balance but
+// not as active loans. Refinanced and fully paid loans do not count towards
+// the balance, but their late payments do count towards the history.
+func (uc *loanUseCase) GetBorrowerExposure(ctx context.Context, borrowerID string) (*models.BorrowerExposure, error) {
+	limits, err := uc.borrowerLimits(ctx, borrowerID)
+	if err != nil {
+		return nil, err
+	}
+	exposure := &models.BorrowerExposure{
+		BorrowerID: borrowerID,
+		Products:   []models.ProductExposure{},
+		Limits:     limits,
+	}
+	loans, err := uc.loanRepo.ListByBorrower(ctx, borrowerID)
+	if err != nil {
+		return nil, err
+	}
+	if exposure.MaxDaysPaidLate, err = uc.loanRepo.MaxDaysPaidLate(ctx, borrowerID); err != nil {
+		return nil, err
+	}
+	for _, l := range loans {
+		if l.Restructured {
+			exposure.RestructuredLoans++
+		}
+		var outstanding float64
+		switch l.Status {
+		case models.LoanStatusWrittenOff:
+			exposure.WrittenOffLoans++
+			exposure.Outstanding += l.WrittenOffAmount - l.RecoveredAmount
+			continue
+		case models.LoanStatusRefinanced:
+			continue
+		case models.LoanStatusPendingDisbursement:
+			outstanding = l.Principal
+		default:
+			outstanding, err = uc.GetOutstanding(ctx, l.ID)
+			if err != nil {
+				return nil, err
+			}
+			if outstanding <= 0 {
+				continue
+			}
+			status, err := uc.ClassifyDelinquency(ctx, l.ID)
+			if err != nil {
+				return nil, err
+			}
+			if status.Delinquent {
+				exposure.DelinquentLoans++
+			}
+			exposure.MaxDaysPastDue = max(exposure.MaxDaysPastDue, status.DaysPastDue)
+		}
+
+		exposure.ActiveLoans++
+		exposure.Outstanding += outstanding
+		if l.ProductID != nil {
+			addProductExposure(exposure, *l.ProductID, outstanding)
+		}
+	}
+	exposure.Outstanding = round2(exposure.Outstanding)
+	return exposure, nil
+}
+
+// checkExposure refuses a loan that would take its borrower over their own or
+// the product's limits. It holds a per-borrower lock until the transaction
+// ends so concurrent loans cannot each slip under a limit.
+func (uc *loanUseCase) checkExposure(ctx context.Context, newLoan *models.Loan, prod *models.LoanProduct) error {
+	if newLoan.BorrowerID == "" {
+		// Without a borrower there is nothing to total the limits against,
+		// so a loan could only bypass them.
+		if !uc.limits.IsZero() || (prod != nil && !prod.Limits.IsZero()) {
+			return loan.ErrBorrowerRequired
+		}
+		return nil
+	}
+	if err := uc.loanRepo.LockBorrower(ctx, newLoan.BorrowerID); err != nil {
+		return err
+	}
+	exposure, err := uc.GetBorrowerExposure(ctx, newLoan.BorrowerID)
+	if err != nil {
+		return err
+	}
+
+	breaches := limitBreaches(exposure.Limits, models.LimitScopeBorrower, "",
+		exposure.Outstanding+newLoan.Principal, exposure.ActiveLoans+1, exposure.DelinquentLoans)
+	if prod != nil {
+		pe := exposure.Product(prod.ID)
+		breaches = append(breaches, limitBreaches(prod.Limits, models.LimitScopeProduct, prod.Code,
+			pe.Outstanding+newLoan.Principal, pe.ActiveLoans+1, exposure.DelinquentLoans)...)
+	}
+	if len(breaches) > 0 {
+		limits := make([]string, len(breaches))
+		for i, b := range breaches {
+			limits[i] = b.Scope + " " + b.Limit
+		}
+		return loan.ErrExposureLimitExceeded.
+			Withf("borrower %s exceeds exposure limits: %s", newLoan.BorrowerID, strings.Join(limits, ", ")).
+			WithField("borrower_id", newLoan.BorrowerID).
+			WithField("breaches", breaches)
+	}
+	return nil
+}
+
+// borrowerLimits returns the borrower's own limits, or the defaults.
+func (uc *loanUseCase) borrowerLimits(ctx context.Context, borrowerID string) (models.ExposureLimits, error) {
+	limit, err := uc.borrowerRepo.GetLimit(ctx, borrowerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uc.limits, nil
+	}
+	if err != nil {
+		return models.ExposureLimits{}, err
+	}
+	return limit.ExposureLimits, nil
+}
+
+// unpaidPastDue returns the unpaid installments due on or before the business
+// date, in week order.
+func (uc *loanUseCase) unpaidPastDue(ctx context.Context, loanID int) ([]models.Installment, error) {
+	loan, err := uc.loanRepo.GetByID(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	// Nothing falls due before the money is sent, or once the loan has been
+	// written off.
+	if loan.Status != models.LoanStatusActive {
+		return nil, nil
+	}
+	installments, err := uc.loanRepo.GetInstallments(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	today := clock.Today(ctx, uc.clock)
 	var unpaidPastDue []models.Installment
 	for _, inst := range installments {
 		if !inst.Paid && !inst.DueDate.After(today) {
 			unpaidPastDue = append(unpaidPastDue, inst)
 		}
 	}
-	// check for consecutive weeks
-	for i := 1; i < len(unpaidPastDue); i++ {
-		if unpaidPastDue[i].WeekNumber == unpaidPastDue[i-1].WeekNumber+1 {
-			return true, nil
+	return unpaidPastDue, nil
+}
+
+// lookupProduct resolves the product for a loan. Loans without a product
+// code carry no fees.
+func (uc *loanUseCase) lookupProduct(ctx context.Context, code string) (*models.LoanProduct, error) {
+	if code == "" {
+		return nil, nil
+	}
+	prod, err := uc.productRepo.GetByCode(ctx, code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, loan.ErrUnknownProduct.Withf("product %q does not exist", code)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("product %q: %w", code, err)
+	}
+	return prod, nil
+}
+
+// servicingFees returns the servicing fees of the loan's product, if any.
+func (uc *loanUseCase) servicingFees(ctx context.Context, productID *int) ([]models.ProductFee, error) {
+	if productID == nil {
+		return nil, nil
+	}
+	prod, err := uc.productRepo.GetByID(ctx, *productID)
+	if err != nil {
+		return nil, fmt.Errorf("product %d: %w", *productID, err)
+	}
+	var fees []models.ProductFee
+	for _, fee := range prod.Fees {
+		if fee.FeeType == models.FeeTypeServicing {
+			fees = append(fees, fee)
 		}
 	}
-	return false, nil
+	return fees, nil
 }
 
+const weeksPerYear = 52
+
+// maxDisclosureRate is the largest APR or effective annual rate, in percent,
+// the loans table can hold.
+const maxDisclosureRate = 1e16
+
 // Helper functions
+
+func addProductExposure(exposure *models.BorrowerExposure, productID int, outstanding float64) {
+	for i := range exposure.Products {
+		if exposure.Products[i].ProductID == productID {
+			exposure.Products[i].Outstanding = round2(exposure.Products[i].Outstanding + outstanding)
+			exposure.Products[i].ActiveLoans++
+			return
+		}
+	}
+	exposure.Products = append(exposure.Products, models.ProductExposure{
+		ProductID:   productID,
+		Outstanding: round2(outstanding),
+		ActiveLoans: 1,
+	})
+}
+
+// limitBreaches compares what the borrower would owe with a new loan against
+// one set of limits.
+func limitBreaches(limits models.ExposureLimits, scope, productCode string, outstanding float64, activeLoans, delinquentLoans int) []models.LimitBreach {
+	var breaches []models.LimitBreach
+	breach := func(limit string, ceiling, actual float64) {
+		breaches = append(breaches, models.LimitBreach{
+			Limit:       limit,
+			Scope:       scope,
+			ProductCode: productCode,
+			Max:         ceiling,
+			Actual:      actual,
+		})
+	}
+	if limits.MaxOutstanding > 0 && outstanding > limits.MaxOutstanding+0.005 {
+		breach(models.LimitMaxOutstanding, limits.MaxOutstanding, round2(outstanding))
+	}
+	if limits.MaxActiveLoans > 0 && activeLoans > limits.MaxActiveLoans {
+		breach(models.LimitMaxActiveLoans, float64(limits.MaxActiveLoans), float64(activeLoans))
+	}
+	if limits.BlockDelinquent && delinquentLoans > 0 {
+		breach(models.LimitBlockDelinquent, 0, float64(delinquentLoans))
+	}
+	return breaches
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// buildSchedule derives the loan, its installment schedule, separately charged
+// fees and its disclosure rates from the loan terms and product fees.
+func buildSchedule(terms models.LoanTerms, prod *models.LoanProduct) (*models.Loan, []models.Installment, []models.LoanCharge, error) {
+	var deducted, financed float64
+	var servicingFees []models.ProductFee
+	var productID *int
+	if prod != nil {
+		productID = &prod.ID
+		for _, fee := range prod.Fees {
+			switch fee.FeeType {
+			case models.FeeTypeOrigination:
+				if fee.Treatment == models.FeeTreatmentFinanced {
+					financed += feeAmount(fee, terms.Principal)
+				} else {
+					deducted += feeAmount(fee, terms.Principal)
+				}
+			case models.FeeTypeServicing:
+				servicingFees = append(servicingFees, fee)
+			}
+		}
+	}
+	if deducted >= terms.Principal {
+		return nil, nil, nil, loan.ErrFeesExceedPrincipal.Withf("deducted fees of %.2f leave nothing of the principal of %.2f to disburse", deducted, terms.Principal)
+	}
+
+	// Financed origination fees are added to the principal and bear interest.
+	principal := terms.Principal + financed
+	loan := &models.Loan{
+		BorrowerID:      terms.BorrowerID,
+		Principal:       principal,
+		InterestRate:    terms.InterestRate,
+		TermWeeks:       terms.TermWeeks,
+		WeeklyAmount:    calculateWeeklyAmount(principal, terms.InterestRate, terms.TermWeeks),
+		StartDate:       terms.StartDate,
+		IsActive:        true,
+		Status:          models.LoanStatusPendingDisbursement,
+		ProductID:       productID,
+		OriginationFee:  deducted + financed,
+		NetDisbursement: terms.Principal - deducted,
+	}
+	installments := generateInstallments(loan)
+	charges := applyServicingFees(installments, servicingFees, terms.Principal)
+
+	apr, effectiveRate, err := disclosureRates(loan.Principal, loan.OriginationFee, installments, charges)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("calculate apr: %w", err)
+	}
+	loan.APR = apr
+	loan.EffectiveAnnualRate = effectiveRate
+	return loan, installments, charges, nil
+}
+
+// disclosureRates solves the APR and effective annual rate from the weekly
+// cash flows: each installment plus any separate charge due the same day.
+// Upfront fees reduce the amount actually disbursed. Rates too large to store
+// are refused.
+func disclosureRates(principal, upfrontFees float64, installments []models.Installment, charges []models.LoanCharge) (float64, float64, error) {
+	amounts := make([]float64, len(installments))
+	for i, inst := range installments {
+		amounts[i] = inst.Amount
+		for _, charge := range charges {
+			if charge.DueDate.Equal(inst.DueDate) {
+				amounts[i] += charge.Amount
+			}
+		}
+	}
+	apr, effectiveRate, err := finance.DisclosureRates(principal, upfrontFees, amounts, weeksPerYear)
+	if err != nil {
+		return 0, 0, err
+	}
+	if apr >= maxDisclosureRate || effectiveRate >= maxDisclosureRate {
+		return 0, 0, loan.ErrRateOutOfRange.Withf("loan terms give an effective annual rate of %.4g%%, too large to disclose", effectiveRate)
+	}
+	return apr, effectiveRate, nil
+}
+
+// applyServicingFees adds installment-treated servicing fees into the
+// installments and returns the separately charged ones. A fee with
+// FrequencyWeeks n falls on every n-th installment.
+func applyServicingFees(installments []models.Installment, fees []models.ProductFee, principal float64) []models.LoanCharge {
+	var charges []models.LoanCharge
+	for _, fee := range fees {
+		amount := feeAmount(fee, principal)
+		frequency := fee.FrequencyWeeks
+		if frequency <= 0 {
+			frequency = 1
+		}
+		for i := range installments {
+			if installments[i].WeekNumber%frequency != 0 {
+				continue
+			}
+			if fee.Treatment == models.FeeTreatmentInstallment {
+				installments[i].FeeAmount += amount
+				installments[i].Amount += amount
+				continue
+			}
+			charges = append(charges, models.LoanCharge{
+				FeeType: fee.FeeType,
+				DueDate: installments[i].DueDate,
+				Amount:  amount,
+			})
+		}
+	}
+	return charges
+}
+
+func feeAmount(fee models.ProductFee, principal float64) float64 {
+	if fee.Calculation == models.FeeCalcPercent {
+		return round2(principal * fee.Amount / 100)
+	}
+	return fee.Amount
+}
+
 func calculateWeeklyAmount(principal, rate float64, weeks int) float64 {
-	interest := principal * rate / 100
-	total := principal + interest
-	return total / float64(weeks)
+	return round2(repayableAmount(principal, rate) / float64(weeks))
 }
 
+func repayableAmount(principal, rate float64) float64 {
+	return round2(principal + principal*rate/100)
+}
+
+// generateInstallments splits the loan into weekly installments of whole
+// cents. The last installment takes the rounding remainders, so the
+// installments add up to the principal and interest exactly.
 func generateInstallments(loan *models.Loan) []models.Installment {
-	installments := make([]models.Installment, loan.TermWeeks)
-	for i := 0; i < loan.TermWeeks; i++ {
+	weeks := loan.TermWeeks
+	installments := make([]models.Installment, weeks)
+	principalAmount := round2(loan.Principal / float64(weeks))
+	for i := 0; i < weeks; i++ {
 		dueDate := loan.StartDate.AddDate(0, 0, (i+1)*7)
+		amount, principal := loan.WeeklyAmount, principalAmount
+		if i == weeks-1 {
+			amount = round2(repayableAmount(loan.Principal, loan.InterestRate) - loan.WeeklyAmount*float64(weeks-1))
+			principal = round2(loan.Principal - principalAmount*float64(weeks-1))
+		}
 		installments[i] = models.Installment{
-			WeekNumber: i + 1,
-			DueDate:    dueDate,
-			Amount:     loan.WeeklyAmount,
-			Paid:       false,
+			WeekNumber:      i + 1,
+			DueDate:         dueDate,
+			Amount:          amount,
+			Paid:            false,
+			PrincipalAmount: principal,
+			InterestAmount:  round2(amount - principal),
 		}
 	}
 	return installments
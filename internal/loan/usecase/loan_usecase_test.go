@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/loan"
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// cents converts an amount to whole cents so sums can be compared exactly.
+func cents(v float64) int64 {
+	return int64(math.Round(v * 100))
+}
+
+func TestGenerateInstallmentsSumExactly(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal float64
+		rate      float64
+		weeks     int
+	}{
+		{name: "even split", principal: 5000000, rate: 10, weeks: 50},
+		{name: "thirds round down", principal: 100, rate: 0, weeks: 3},
+		{name: "thirds round up", principal: 200, rate: 0, weeks: 3},
+		{name: "odd rate", principal: 1234.56, rate: 7.77, weeks: 7},
+		{name: "cents principal", principal: 999.99, rate: 3.33, weeks: 13},
+		{name: "single week", principal: 1000, rate: 12.5, weeks: 1},
+		{name: "long term", principal: 10000000, rate: 19.99, weeks: 104},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &models.Loan{
+				Principal:    tt.principal,
+				InterestRate: tt.rate,
+				TermWeeks:    tt.weeks,
+				WeeklyAmount: calculateWeeklyAmount(tt.principal, tt.rate, tt.weeks),
+				StartDate:    time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+			}
+			installments := generateInstallments(l)
+			if len(installments) != tt.weeks {
+				t.Fatalf("generateInstallments() = %d installments, want %d", len(installments), tt.weeks)
+			}
+			var amount, principal, interest int64
+			for _, inst := range installments {
+				amount += cents(inst.Amount)
+				principal += cents(inst.PrincipalAmount)
+				interest += cents(inst.InterestAmount)
+				if cents(inst.PrincipalAmount)+cents(inst.InterestAmount) != cents(inst.Amount) {
+					t.Errorf("week %d: principal %.2f + interest %.2f != amount %.2f", inst.WeekNumber, inst.PrincipalAmount, inst.InterestAmount, inst.Amount)
+				}
+			}
+			if want := cents(repayableAmount(tt.principal, tt.rate)); amount != want {
+				t.Errorf("installments sum to %d cents, want %d", amount, want)
+			}
+			if want := cents(tt.principal); principal != want {
+				t.Errorf("principal sums to %d cents, want %d", principal, want)
+			}
+			if want := cents(repayableAmount(tt.principal, tt.rate) - tt.principal); interest != want {
+				t.Errorf("interest sums to %d cents, want %d", interest, want)
+			}
+			if last := installments[tt.weeks-1]; !last.DueDate.Equal(l.StartDate.AddDate(0, 0, 7*tt.weeks)) {
+				t.Errorf("last installment due %s, want %d weeks after the start", last.DueDate.Format("2006-01-02"), tt.weeks)
+			}
+		})
+	}
+}
+
+func TestBuildScheduleFees(t *testing.T) {
+	terms := models.LoanTerms{Principal: 1000, InterestRate: 10, TermWeeks: 4, StartDate: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		name             string
+		fees             []models.ProductFee
+		wantErr          error
+		wantPrincipal    float64
+		wantNet          float64
+		wantOrigination  float64
+		wantInstallments float64
+		wantCharges      float64
+	}{
+		{
+			name:             "no fees",
+			wantPrincipal:    1000,
+			wantNet:          1000,
+			wantInstallments: 1100,
+		},
+		{
+			name: "deducted and financed origination",
+			fees: []models.ProductFee{
+				{FeeType: models.FeeTypeOrigination, Calculation: models.FeeCalcFlat, Amount: 20, Treatment: models.FeeTreatmentDeducted},
+				{FeeType: models.FeeTypeOrigination, Calculation: models.FeeCalcPercent, Amount: 5, Treatment: models.FeeTreatmentFinanced},
+			},
+			wantPrincipal:    1050,
+			wantNet:          980,
+			wantOrigination:  70,
+			wantInstallments: 1155,
+		},
+		{
+			name: "servicing fees every second week",
+			fees: []models.ProductFee{
+				{FeeType: models.FeeTypeServicing, Calculation: models.FeeCalcFlat, Amount: 2.5, Treatment: models.FeeTreatmentInstallment, FrequencyWeeks: 2},
+				{FeeType: models.FeeTypeServicing, Calculation: models.FeeCalcPercent, Amount: 0.333, Treatment: models.FeeTreatmentSeparate, FrequencyWeeks: 2},
+			},
+			wantPrincipal:    1000,
+			wantNet:          1000,
+			wantInstallments: 1105,
+			wantCharges:      6.66,
+		},
+		{
+			name: "deducted fees equal to the principal",
+			fees: []models.ProductFee{
+				{FeeType: models.FeeTypeOrigination, Calculation: models.FeeCalcFlat, Amount: 600, Treatment: models.FeeTreatmentDeducted},
+				{FeeType: models.FeeTypeOrigination, Calculation: models.FeeCalcPercent, Amount: 40, Treatment: models.FeeTreatmentDeducted},
+			},
+			wantErr: loan.ErrFeesExceedPrincipal,
+		},
+		{
+			name: "deducted fee above the principal",
+			fees: []models.ProductFee{
+				{FeeType: models.FeeTypeOrigination, Calculation: models.FeeCalcFlat, Amount: 1500, Treatment: models.FeeTreatmentDeducted},
+			},
+			wantErr: loan.ErrFeesExceedPrincipal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prod := &models.LoanProduct{ID: 3, Code: "STD", Fees: tt.fees}
+			l, installments, charges, err := buildSchedule(terms, prod)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("buildSchedule() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildSchedule() error = %v", err)
+			}
+			if l.Principal != tt.wantPrincipal || l.NetDisbursement != tt.wantNet || l.OriginationFee != tt.wantOrigination {
+				t.Errorf("loan principal %.2f, net %.2f, origination fee %.2f, want %.2f, %.2f, %.2f",
+					l.Principal, l.NetDisbursement, l.OriginationFee, tt.wantPrincipal, tt.wantNet, tt.wantOrigination)
+			}
+			var installmentTotal, chargeTotal int64
+			for _, inst := range installments {
+				installmentTotal += cents(inst.Amount)
+			}
+			for _, charge := range charges {
+				chargeTotal += cents(charge.Amount)
+			}
+			if installmentTotal != cents(tt.wantInstallments) {
+				t.Errorf("installments sum to %d cents, want %d", installmentTotal, cents(tt.wantInstallments))
+			}
+			if chargeTotal != cents(tt.wantCharges) {
+				t.Errorf("charges sum to %d cents, want %d", chargeTotal, cents(tt.wantCharges))
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package loanimport
+
+import "github.com/evrintobing17/loan-billing-system/pkg/apperror"
+
+var (
+	ErrJobNotFound = apperror.New(apperror.NotFound, "import_job_not_found", "import job not found")
+	ErrInvalidCSV  = apperror.New(apperror.Invalid, "invalid_csv", "file is not a valid loan import CSV")
+)
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/evrintobing17/loan-billing-system/internal/loanimport"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/evrintobing17/loan-billing-system/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type LoanImportHandler struct {
+	importUC loanimport.LoanImportUsecase
+}
+
+func NewLoanImportHandler(uc loanimport.LoanImportUsecase) *LoanImportHandler {
+	return &LoanImportHandler{importUC: uc}
+}
+
+// StartImport godoc
+// @Summary Start a bulk loan import (admin)
+// @Description Upload a CSV of legacy loans and their payment history. Each row is validated like a create-loan request, created, disbursed on its start date and has its payments replayed. The import runs in the background; poll the returned job. A dry run rolls every row back and only reports.
+// @Tags imports
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Loan CSV"
+// @Param dry_run query bool false "Validate and simulate without saving"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param X-User-ID header string true "Requesting user"
+// @Success 202 {object} models.LoanImportJob
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /imports/loans [post]
+func (h *LoanImportHandler) StartImport(c *gin.Context) {
+	actor := middleware.Actor(c)
+	if actor == "" {
+		c.Error(middleware.ErrActorRequired)
+		return
+	}
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid dry_run, use true or false"))
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.Error(apperror.Invalidf("file is required"))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer file.Close()
+
+	job, err := h.importUC.StartImport(c.Request.Context(), file, dryRun, actor)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetJob godoc
+// @Summary Get a loan import job (admin)
+// @Description Progress and per-row outcome of an import.
+// @Tags imports
+// @Produce json
+// @Param id path int true "Import job ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.LoanImportJob
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /imports/loans/{id} [get]
+func (h *LoanImportHandler) GetJob(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid import job id"))
+		return
+	}
+
+	job, err := h.importUC.GetJob(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,20 @@
+package loanimport
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type LoanImportRepository interface {
+	CreateJob(ctx context.Context, job *models.LoanImportJob) error
+	// SaveRow stores a row outcome and counts it on the job.
+	SaveRow(ctx context.Context, jobID int, row *models.LoanImportRow) error
+	// FinishJob stores the job's final status and error.
+	FinishJob(ctx context.Context, job *models.LoanImportJob) error
+	// GetJob returns the job with its rows.
+	GetJob(ctx context.Context, id int) (*models.LoanImportJob, error)
+	// FindImported returns the loan a legacy reference was imported as, or
+	// sql.ErrNoRows.
+	FindImported(ctx context.Context, ref string) (int, error)
+}
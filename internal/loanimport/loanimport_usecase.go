@@ -0,0 +1,20 @@
+package loanimport
+
+import (
+	"context"
+	"io"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// LoanImportUsecase loads legacy loans and their payment history from CSV.
+// Each row is created, disbursed and has its payments replayed in one
+// transaction; a dry run does the same and rolls it back.
+type LoanImportUsecase interface {
+	// Import runs an import to completion.
+	Import(ctx context.Context, csv io.Reader, dryRun bool, createdBy string) (*models.LoanImportJob, error)
+	// StartImport checks the file, records the job and processes it in the
+	// background. Poll GetJob for the outcome.
+	StartImport(ctx context.Context, csv io.Reader, dryRun bool, createdBy string) (*models.LoanImportJob, error)
+	GetJob(ctx context.Context, id int) (*models.LoanImportJob, error)
+}
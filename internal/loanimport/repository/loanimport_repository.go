@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evrintobing17/loan-billing-system/internal/loanimport"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+type loanImportRepository struct {
+	DB *sql.DB
+}
+
+func NewLoanImportRepository(DB *sql.DB) loanimport.LoanImportRepository {
+	return &loanImportRepository{
+		DB: DB,
+	}
+}
+
+// CreateJob implements [loanimport.LoanImportRepository].
+func (r *loanImportRepository) CreateJob(ctx context.Context, job *models.LoanImportJob) error {
+	query := `INSERT INTO loan_import_jobs (dry_run, status, total_rows, created_by)
+              VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+	return postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, job.DryRun, job.Status, job.TotalRows, job.CreatedBy).
+		Scan(&job.ID, &job.CreatedAt)
+}
+
+// SaveRow implements [loanimport.LoanImportRepository].
+func (r *loanImportRepository) SaveRow(ctx context.Context, jobID int, row *models.LoanImportRow) error {
+	return postgres.RunInTx(ctx, r.DB, func(tx postgres.DBTX) error {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO loan_import_rows (job_id, line, ref, status, loan_id, payments_replayed, error)
+             VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''))`,
+			jobID, row.Line, row.Ref, row.Status, row.LoanID, row.PaymentsReplayed, row.Error)
+		if err != nil {
+			return err
+		}
+
+		counter := "succeeded"
+		if row.Status == models.ImportRowFailed {
+			counter = "failed"
+		}
+		_, err = tx.ExecContext(ctx,
+			`UPDATE loan_import_jobs SET `+counter+` = `+counter+` + 1 WHERE id = $1`, jobID)
+		return err
+	})
+}
+
+// FinishJob implements [loanimport.LoanImportRepository].
+func (r *loanImportRepository) FinishJob(ctx context.Context, job *models.LoanImportJob) error {
+	query := `UPDATE loan_import_jobs SET status = $2, error = NULLIF($3, ''), finished_at = CURRENT_TIMESTAMP
+              WHERE id = $1 RETURNING finished_at`
+	return postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, job.ID, job.Status, job.Error).Scan(&job.FinishedAt)
+}
+
+func (r *loanImportRepository) GetJob(ctx context.Context, id int) (*models.LoanImportJob, error) {
+	var job models.LoanImportJob
+	var jobErr sql.NullString
+	query := `SELECT id, dry_run, status, total_rows, succeeded, failed, error, created_by, created_at, finished_at
+              FROM loan_import_jobs WHERE id = $1`
+	err := postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, id).Scan(
+		&job.ID,
+		&job.DryRun,
+		&job.Status,
+		&job.TotalRows,
+		&job.Succeeded,
+		&job.Failed,
+		&jobErr,
+		&job.CreatedBy,
+		&job.CreatedAt,
+		&job.FinishedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query import job: %w", err)
+	}
+	job.Error = jobErr.String
+
+	rows, err := postgres.Conn(ctx, r.DB).QueryContext(ctx,
+		`SELECT line, ref, status, loan_id, payments_replayed, error
+         FROM loan_import_rows WHERE job_id = $1 ORDER BY line`, id)
+	if err != nil {
+		return nil, fmt.Errorf("query import rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.LoanImportRow
+		var rowErr sql.NullString
+		if err := rows.Scan(&row.Line, &row.Ref, &row.Status, &row.LoanID, &row.PaymentsReplayed, &rowErr); err != nil {
+			return nil, fmt.Errorf("scan import row: %w", err)
+		}
+		row.Error = rowErr.String
+		job.Rows = append(job.Rows, row)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return &job, nil
+}
+
+// FindImported implements [loanimport.LoanImportRepository].
+func (r *loanImportRepository) FindImported(ctx context.Context, ref string) (int, error) {
+	var loanID int
+	query := `SELECT loan_id FROM loan_import_rows WHERE ref = $1 AND status = $2`
+	err := postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, ref, models.ImportRowImported).Scan(&loanID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("query imported loan: %w", err)
+	}
+	return loanID, nil
+}
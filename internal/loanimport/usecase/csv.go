@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/loanimport"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Import file columns. The payments column lists historical payments as
+// date:amount pairs separated by semicolons, e.g. "2026-01-12:110000;2026-01-19:110000".
+const (
+	colRef          = "ref"
+	colBorrowerID   = "borrower_id"
+	colPrincipal    = "principal"
+	colInterestRate = "interest_rate"
+	colTermWeeks    = "term_weeks"
+	colStartDate    = "start_date"
+	colProductCode  = "product_code"
+	colPayments     = "payments"
+)
+
+var (
+	requiredColumns = []string{colRef, colPrincipal, colInterestRate, colTermWeeks, colStartDate}
+	optionalColumns = []string{colBorrowerID, colProductCode, colPayments}
+)
+
+// record is one parsed CSV row. err is set when the row is invalid; the rest
+// of the file is still imported.
+type record struct {
+	line     int
+	ref      string
+	terms    models.LoanTerms
+	payments []historicalPayment
+	err      error
+}
+
+type historicalPayment struct {
+	date   time.Time
+	amount float64
+}
+
+// parseCSV reads the whole file. Only a missing or malformed header fails the
+// file; bad rows are returned with their error.
+func parseCSV(r io.Reader) ([]record, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, loanimport.ErrInvalidCSV.Withf("file is empty")
+		}
+		return nil, loanimport.ErrInvalidCSV.Wrap(err)
+	}
+	columns, err := columnIndex(header)
+	if err != nil {
+		return nil, err
+	}
+	reader.FieldsPerRecord = len(header)
+
+	var records []record
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if errors.Is(err, csv.ErrFieldCount) {
+			// A row with the wrong number of fields only fails that row.
+			rec := record{line: line, err: fmt.Errorf("expected %d fields, got %d", len(header), len(fields))}
+			if i := columns[colRef]; i < len(fields) {
+				rec.ref = strings.TrimSpace(fields[i])
+			}
+			records = append(records, rec)
+			continue
+		}
+		if err != nil {
+			return nil, loanimport.ErrInvalidCSV.Wrap(err)
+		}
+		get := func(col string) string {
+			if i, ok := columns[col]; ok {
+				return strings.TrimSpace(fields[i])
+			}
+			return ""
+		}
+		records = append(records, parseRecord(line, get))
+	}
+	return records, nil
+}
+
+func columnIndex(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(requiredColumns, name) && !slices.Contains(optionalColumns, name) {
+			return nil, loanimport.ErrInvalidCSV.Withf("unknown column %q", name)
+		}
+		if _, dup := columns[name]; dup {
+			return nil, loanimport.ErrInvalidCSV.Withf("duplicate column %q", name)
+		}
+		columns[name] = i
+	}
+	for _, col := range requiredColumns {
+		if _, ok := columns[col]; !ok {
+			return nil, loanimport.ErrInvalidCSV.Withf("missing column %q", col)
+		}
+	}
+	return columns, nil
+}
+
+// parseRecord checks a row against the same rules as a create-loan request.
+func parseRecord(line int, get func(string) string) record {
+	rec := record{line: line, ref: get(colRef)}
+	if rec.ref == "" {
+		rec.err = errors.New("ref is required")
+		return rec
+	}
+
+	req := models.CreateLoanRequest{
+		BorrowerID:  get(colBorrowerID),
+		StartDate:   get(colStartDate),
+		ProductCode: get(colProductCode),
+	}
+	var err error
+	if req.Principal, err = parseFloat(colPrincipal, get(colPrincipal)); err != nil {
+		rec.err = err
+		return rec
+	}
+	if req.InterestRate, err = parseFloat(colInterestRate, get(colInterestRate)); err != nil {
+		rec.err = err
+		return rec
+	}
+	if raw := get(colTermWeeks); raw != "" {
+		if req.TermWeeks, err = strconv.Atoi(raw); err != nil {
+			rec.err = fmt.Errorf("invalid %s %q", colTermWeeks, raw)
+			return rec
+		}
+	}
+	if req.StartDate == "" {
+		rec.err = errors.New("start_date is required")
+		return rec
+	}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		rec.err = err
+		return rec
+	}
+	startDate, _ := time.Parse("2006-01-02", req.StartDate)
+
+	rec.terms = models.LoanTerms{
+		BorrowerID:   req.BorrowerID,
+		Principal:    req.Principal,
+		InterestRate: req.InterestRate,
+		TermWeeks:    req.TermWeeks,
+		StartDate:    startDate,
+		ProductCode:  req.ProductCode,
+	}
+	rec.payments, rec.err = parsePayments(get(colPayments), startDate)
+	return rec
+}
+
+func parseFloat(col, raw string) (float64, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", col, raw)
+	}
+	return v, nil
+}
+
+// parsePayments reads the payments column, oldest payment first.
+func parsePayments(raw string, startDate time.Time) ([]historicalPayment, error) {
+	var payments []historicalPayment
+	for _, item := range strings.Split(raw, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		date, amount, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid payment %q, use YYYY-MM-DD:amount", item)
+		}
+		p := historicalPayment{}
+		var err error
+		if p.date, err = time.Parse("2006-01-02", strings.TrimSpace(date)); err != nil {
+			return nil, fmt.Errorf("invalid payment date %q", date)
+		}
+		if p.amount, err = strconv.ParseFloat(strings.TrimSpace(amount), 64); err != nil || p.amount <= 0 {
+			return nil, fmt.Errorf("invalid payment amount %q", amount)
+		}
+		if p.date.Before(startDate) {
+			return nil, fmt.Errorf("payment on %s is before the start date", date)
+		}
+		payments = append(payments, p)
+	}
+	sort.SliceStable(payments, func(i, j int) bool { return payments[i].date.Before(payments[j].date) })
+	return payments, nil
+}
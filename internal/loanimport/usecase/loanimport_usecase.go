@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/evrintobing17/loan-billing-system/internal/disbursement"
+	"github.com/evrintobing17/loan-billing-system/internal/loan"
+	"github.com/evrintobing17/loan-billing-system/internal/loanimport"
+	"github.com/evrintobing17/loan-billing-system/internal/notification"
+	"github.com/evrintobing17/loan-billing-system/internal/payment"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+// errDryRun rolls back a row that was imported in a dry run.
+var errDryRun = errors.New("dry run")
+
+type loanImportUseCase struct {
+	importRepo     loanimport.LoanImportRepository
+	loanUC         loan.LoanUsecase
+	disbursementUC disbursement.DisbursementUsecase
+	paymentUC      payment.PaymentUsecase
+	tx             postgres.Transactor
+}
+
+func NewLoanImportUseCase(ir loanimport.LoanImportRepository, luc loan.LoanUsecase, duc disbursement.DisbursementUsecase,
+	puc payment.PaymentUsecase, tx postgres.Transactor) loanimport.LoanImportUsecase {
+	return &loanImportUseCase{
+		importRepo:     ir,
+		loanUC:         luc,
+		disbursementUC: duc,
+		paymentUC:      puc,
+		tx:             tx,
+	}
+}
+
+func (uc *loanImportUseCase) Import(ctx context.Context, csv io.Reader, dryRun bool, createdBy string) (*models.LoanImportJob, error) {
+	job, records, err := uc.createJob(ctx, csv, dryRun, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	uc.run(ctx, job, records)
+	return uc.importRepo.GetJob(ctx, job.ID)
+}
+
+// StartImport implements [loanimport.LoanImportUsecase]. The job outlives the
+// request that started it.
+func (uc *loanImportUseCase) StartImport(ctx context.Context, csv io.Reader, dryRun bool, createdBy string) (*models.LoanImportJob, error) {
+	job, records, err := uc.createJob(ctx, csv, dryRun, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	go uc.run(context.WithoutCancel(ctx), job, records)
+	return job, nil
+}
+
+func (uc *loanImportUseCase) GetJob(ctx context.Context, id int) (*models.LoanImportJob, error) {
+	job, err := uc.importRepo.GetJob(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, loanimport.ErrJobNotFound.Wrap(err)
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func (uc *loanImportUseCase) createJob(ctx context.Context, csv io.Reader, dryRun bool, createdBy string) (*models.LoanImportJob, []record, error) {
+	records, err := parseCSV(csv)
+	if err != nil {
+		return nil, nil, err
+	}
+	job := &models.LoanImportJob{
+		DryRun:    dryRun,
+		Status:    models.ImportJobRunning,
+		TotalRows: len(records),
+		CreatedBy: createdBy,
+	}
+	if err := uc.importRepo.CreateJob(ctx, job); err != nil {
+		return nil, nil, err
+	}
+	return job, records, nil
+}
+
+// run imports the rows in file order and records the outcome of each. A
+// failing row does not stop the job; only failing to record outcomes does.
+func (uc *loanImportUseCase) run(ctx context.Context, job *models.LoanImportJob, records []record) {
+	job.Status = models.ImportJobCompleted
+	for _, rec := range records {
+		row := uc.importRow(ctx, job, rec)
+		if row.Status == models.ImportRowImported {
+			continue // saved with the loan
+		}
+		if err := uc.importRepo.SaveRow(ctx, job.ID, row); err != nil {
+			job.Status = models.ImportJobFailed
+			job.Error = fmt.Sprintf("line %d: %v", rec.line, err)
+			break
+		}
+	}
+	if err := uc.importRepo.FinishJob(ctx, job); err != nil {
+		log.Printf("loan import %d: finish job: %v", job.ID, err)
+	}
+}
+
+// importRow creates, disburses and replays the payments of one loan in a
+// single transaction. Loan creation and disbursement are dated on the start
+// date and each payment on its own date, so the schedule, journal and
+// delinquency come out as they would have live. Borrowers are not notified
+// of the replayed payments.
+func (uc *loanImportUseCase) importRow(ctx context.Context, job *models.LoanImportJob, rec record) *models.LoanImportRow {
+	row := &models.LoanImportRow{Line: rec.line, Ref: rec.ref, Status: models.ImportRowFailed}
+	if rec.err != nil {
+		row.Error = rec.err.Error()
+		return row
+	}
+	if loanID, err := uc.importRepo.FindImported(ctx, rec.ref); err == nil {
+		row.Error = fmt.Sprintf("already imported as loan %d", loanID)
+		return row
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		row.Error = err.Error()
+		return row
+	}
+
+	err := uc.tx.WithinTx(notification.WithoutNotifications(ctx), func(ctx context.Context) error {
+		startCtx := clock.WithAsOfDate(ctx, rec.terms.StartDate)
+		l, err := uc.loanUC.CreateLoan(startCtx, rec.terms)
+		if err != nil {
+			return err
+		}
+		_, err = uc.disbursementUC.Disburse(startCtx, l.ID, models.DisbursementRequest{
+			Amount:           l.NetDisbursement,
+			DisbursementDate: rec.terms.StartDate.Format("2006-01-02"),
+			Channel:          "import",
+			Reference:        "import:" + rec.ref,
+		})
+		if err != nil {
+			return fmt.Errorf("disburse: %w", err)
+		}
+
+		for i, p := range rec.payments {
+			// Keys are scoped to the job: a rolled-back dry run must not make
+			// a later run skip the payment as a duplicate.
+			key := fmt.Sprintf("import:%d:%s:%d", job.ID, rec.ref, i+1)
+			_, err := uc.paymentUC.MakePayment(clock.WithAsOfDate(ctx, p.date), l.ID, p.amount, key)
+			if err != nil {
+				return fmt.Errorf("payment of %.2f on %s: %w", p.amount, p.date.Format("2006-01-02"), err)
+			}
+			row.PaymentsReplayed++
+		}
+
+		if job.DryRun {
+			return errDryRun
+		}
+		row.Status = models.ImportRowImported
+		row.LoanID = &l.ID
+		return uc.importRepo.SaveRow(ctx, job.ID, row)
+	})
+	switch {
+	case errors.Is(err, errDryRun):
+		row.Status = models.ImportRowValid
+	case err != nil:
+		row.Status = models.ImportRowFailed
+		row.LoanID = nil
+		row.PaymentsReplayed = 0
+		row.Error = err.Error()
+	}
+	return row
+}
@@ -0,0 +1,57 @@
+package channel
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/evrintobing17/loan-billing-system/internal/notification"
+)
+
+// Config holds the settings of every channel; only those of the channels
+// built are needed.
+type Config struct {
+	SMTPHost      string
+	SMTPPort      int
+	SMTPUsername  string
+	SMTPPassword  string
+	SMTPFrom      string
+	SMSGatewayURL string
+	SMSAPIKey     string
+	SMSSender     string
+	// LogFile is appended to by the log channel; empty means standard
+	// output. It stays open for the life of the process.
+	LogFile string
+}
+
+// Build makes the named channels: email, sms and log.
+func Build(names []string, cfg Config) (map[string]notification.Channel, error) {
+	channels := make(map[string]notification.Channel, len(names))
+	for _, name := range names {
+		switch name {
+		case "email":
+			if cfg.SMTPHost == "" || cfg.SMTPFrom == "" {
+				return nil, fmt.Errorf("email channel needs SMTP_HOST and SMTP_FROM")
+			}
+			channels[name] = NewEmail(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+		case "sms":
+			if cfg.SMSGatewayURL == "" {
+				return nil, fmt.Errorf("sms channel needs SMS_GATEWAY_URL")
+			}
+			channels[name] = NewSMS(cfg.SMSGatewayURL, cfg.SMSAPIKey, cfg.SMSSender)
+		case "log":
+			var w io.Writer = os.Stdout
+			if cfg.LogFile != "" {
+				f, err := os.OpenFile(cfg.LogFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+				if err != nil {
+					return nil, fmt.Errorf("open notification log: %w", err)
+				}
+				w = f
+			}
+			channels[name] = NewLog(w)
+		default:
+			return nil, fmt.Errorf("unknown notification channel %q", name)
+		}
+	}
+	return channels, nil
+}
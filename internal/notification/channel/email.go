@@ -0,0 +1,58 @@
+package channel
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/notification"
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type email struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewEmail sends plain-text email through an SMTP server, authenticating
+// when a username is given. The server must offer STARTTLS for the
+// credentials to be sent.
+func NewEmail(host string, port int, username, password, from string) notification.Channel {
+	e := &email{addr: net.JoinHostPort(host, strconv.Itoa(port)), from: from}
+	if username != "" {
+		e.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return e
+}
+
+func (e *email) Recipient(_ string, contact *models.BorrowerContact) string {
+	if contact == nil {
+		return ""
+	}
+	return contact.Email
+}
+
+func (e *email) Short() bool { return false }
+
+func (e *email) Send(_ context.Context, to, subject, body string) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", e.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", headerSafe(subject)))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return smtp.SendMail(e.addr, e.auth, e.from, []string{to}, []byte(msg.String()))
+}
+
+// headerSafe keeps a header value on one line.
+func headerSafe(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,46 @@
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/notification"
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type logSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewLog writes each notification as a JSON line instead of sending it, for
+// local development and testing. It addresses borrowers by ID, so it works
+// without contact details.
+func NewLog(w io.Writer) notification.Channel {
+	return &logSink{w: w}
+}
+
+func (l *logSink) Recipient(borrowerID string, _ *models.BorrowerContact) string {
+	return borrowerID
+}
+
+func (l *logSink) Short() bool { return false }
+
+func (l *logSink) Send(_ context.Context, to, subject, body string) error {
+	line, err := json.Marshal(struct {
+		Time    time.Time `json:"time"`
+		To      string    `json:"to"`
+		Subject string    `json:"subject"`
+		Body    string    `json:"body"`
+	}{time.Now(), to, subject, body})
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.w.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,63 @@
+package channel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/notification"
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type sms struct {
+	url    string
+	apiKey string
+	sender string
+	client *http.Client
+}
+
+// NewSMS sends text messages through an HTTP SMS gateway. Each message is
+// posted as {"from", "to", "text"} JSON with the API key as bearer token;
+// any 2xx answer counts as accepted.
+func NewSMS(url, apiKey, sender string) notification.Channel {
+	return &sms{url: url, apiKey: apiKey, sender: sender, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *sms) Recipient(_ string, contact *models.BorrowerContact) string {
+	if contact == nil {
+		return ""
+	}
+	return contact.Phone
+}
+
+func (s *sms) Short() bool { return true }
+
+func (s *sms) Send(ctx context.Context, to, _, body string) error {
+	payload, err := json.Marshal(map[string]string{"from": s.sender, "to": to, "text": body})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sms gateway answered %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
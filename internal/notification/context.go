@@ -0,0 +1,17 @@
+package notification
+
+import "context"
+
+type suppressKey struct{}
+
+// WithoutNotifications returns a context in which nothing is queued for
+// borrowers, e.g. while replaying the history of imported loans.
+func WithoutNotifications(ctx context.Context) context.Context {
+	return context.WithValue(ctx, suppressKey{}, true)
+}
+
+// Suppressed reports whether ctx was made by WithoutNotifications.
+func Suppressed(ctx context.Context) bool {
+	suppressed, _ := ctx.Value(suppressKey{}).(bool)
+	return suppressed
+}
@@ -0,0 +1,9 @@
+package notification
+
+import "github.com/evrintobing17/loan-billing-system/pkg/apperror"
+
+var (
+	ErrNotificationNotFound = apperror.New(apperror.NotFound, "notification_not_found", "notification not found")
+	ErrContactNotFound      = apperror.New(apperror.NotFound, "contact_not_found", "borrower has no contact details")
+	ErrNotFailed            = apperror.New(apperror.Conflict, "notification_not_failed", "only failed notifications can be resent")
+)
@@ -0,0 +1,227 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/notification"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/evrintobing17/loan-billing-system/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationUC notification.NotificationUsecase
+}
+
+func NewNotificationHandler(uc notification.NotificationUsecase) *NotificationHandler {
+	return &NotificationHandler{notificationUC: uc}
+}
+
+// GetContact godoc
+// @Summary Get a borrower's contact details (admin)
+// @Tags notifications
+// @Produce json
+// @Param borrowerId path string true "Borrower ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.BorrowerContact
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /borrowers/{borrowerId}/contact [get]
+func (h *NotificationHandler) GetContact(c *gin.Context) {
+	contact, err := h.notificationUC.GetContact(c.Request.Context(), c.Param("borrowerId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, contact)
+}
+
+// SetContact godoc
+// @Summary Set a borrower's contact details (admin)
+// @Description Replaces the email address and E.164 phone number the borrower is notified at. Opting out stops all notifications to the borrower.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param borrowerId path string true "Borrower ID"
+// @Param request body models.SetContactRequest true "Contact details"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param X-User-ID header string true "Acting user"
+// @Success 200 {object} models.BorrowerContact
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /borrowers/{borrowerId}/contact [put]
+func (h *NotificationHandler) SetContact(c *gin.Context) {
+	actor := middleware.Actor(c)
+	if actor == "" {
+		c.Error(middleware.ErrActorRequired)
+		return
+	}
+	var req models.SetContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+
+	contact, err := h.notificationUC.SetContact(c.Request.Context(), c.Param("borrowerId"), req, actor)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, contact)
+}
+
+// Run godoc
+// @Summary Queue reminders and delinquency alerts (admin)
+// @Description Queues a reminder of every unpaid installment falling due the configured number of days after the business date, and an alert for every loan delinquent as of it. Each is queued once, so running again is harmless. Defaults to the current business date.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param request body models.RunNotificationsRequest false "Business date"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.NotificationRun
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /notifications/run [post]
+func (h *NotificationHandler) Run(c *gin.Context) {
+	var req models.RunNotificationsRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(apperror.Default(err, apperror.Invalid))
+			return
+		}
+	}
+
+	var businessDate time.Time
+	if req.BusinessDate != "" {
+		var err error
+		businessDate, err = time.Parse("2006-01-02", req.BusinessDate)
+		if err != nil {
+			c.Error(apperror.Invalidf("invalid business_date format, use YYYY-MM-DD"))
+			return
+		}
+	}
+
+	run, err := h.notificationUC.Run(c.Request.Context(), businessDate)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+// Dispatch godoc
+// @Summary Send pending notifications (admin)
+// @Description Sends the notifications that are due now, as the background dispatcher does. Failed sends are retried later until the attempts run out.
+// @Tags notifications
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.NotificationDispatch
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /notifications/dispatch [post]
+func (h *NotificationHandler) Dispatch(c *gin.Context) {
+	result, err := h.notificationUC.Dispatch(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// List godoc
+// @Summary List notifications (admin)
+// @Description The 200 most recent notifications, newest first.
+// @Tags notifications
+// @Produce json
+// @Param borrower_id query string false "Filter by borrower"
+// @Param loan_id query int false "Filter by loan"
+// @Param status query string false "Filter by status: pending, sent, failed or skipped"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {array} models.Notification
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /notifications [get]
+func (h *NotificationHandler) List(c *gin.Context) {
+	filter := models.NotificationFilter{
+		BorrowerID: c.Query("borrower_id"),
+		Status:     c.Query("status"),
+	}
+	if s := c.Query("loan_id"); s != "" {
+		loanID, err := strconv.Atoi(s)
+		if err != nil {
+			c.Error(apperror.Invalidf("invalid loan_id"))
+			return
+		}
+		filter.LoanID = loanID
+	}
+
+	notifications, err := h.notificationUC.List(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, notifications)
+}
+
+// Get godoc
+// @Summary Get a notification (admin)
+// @Tags notifications
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.Notification
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /notifications/{id} [get]
+func (h *NotificationHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid notification id"))
+		return
+	}
+
+	n, err := h.notificationUC.Get(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, n)
+}
+
+// Resend godoc
+// @Summary Resend a failed notification (admin)
+// @Description Puts a notification that ran out of attempts back in the queue with a fresh set of attempts.
+// @Tags notifications
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.Notification
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /notifications/{id}/resend [post]
+func (h *NotificationHandler) Resend(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid notification id"))
+		return
+	}
+
+	n, err := h.notificationUC.Resend(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, n)
+}
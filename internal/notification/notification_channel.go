@@ -0,0 +1,19 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// Channel delivers notifications, e.g. by email or SMS.
+type Channel interface {
+	// Recipient is the address to send to the borrower at; empty when the
+	// borrower has none for this channel. contact is nil for borrowers
+	// without contact details.
+	Recipient(borrowerID string, contact *models.BorrowerContact) string
+	// Short reports whether messages must fit an SMS. They then use the
+	// template's short text and have no subject.
+	Short() bool
+	Send(ctx context.Context, to, subject, body string) error
+}
@@ -0,0 +1,24 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type NotificationRepository interface {
+	GetContact(ctx context.Context, borrowerID string) (*models.BorrowerContact, error)
+	SaveContact(ctx context.Context, contact *models.BorrowerContact) error
+
+	// Create returns sql.ErrNoRows when a notification with the dedup key
+	// exists already.
+	Create(ctx context.Context, n *models.Notification) error
+	Get(ctx context.Context, id int) (*models.Notification, error)
+	List(ctx context.Context, filter models.NotificationFilter, limit int) ([]models.Notification, error)
+	// Claim takes up to limit pending notifications due by now, counting an
+	// attempt and holding them until lease so that concurrent dispatchers do
+	// not send them too.
+	Claim(ctx context.Context, now, lease time.Time, limit int) ([]models.Notification, error)
+	Update(ctx context.Context, n *models.Notification) error
+}
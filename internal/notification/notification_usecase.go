@@ -0,0 +1,31 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// NotificationUsecase queues notifications to borrowers and sends them.
+// Queuing and sending are separate so that a notification is only sent
+// once what it reports has committed.
+type NotificationUsecase interface {
+	GetContact(ctx context.Context, borrowerID string) (*models.BorrowerContact, error)
+	SetContact(ctx context.Context, borrowerID string, req models.SetContactRequest, actor string) (*models.BorrowerContact, error)
+
+	// PaymentMade queues a receipt for the payment. It implements
+	// [payment.PaymentObserver].
+	PaymentMade(ctx context.Context, p *models.Payment) error
+	// Run queues reminders of installments falling due soon and alerts for
+	// loans that became delinquent as of the business date; a zero date
+	// means today.
+	Run(ctx context.Context, businessDate time.Time) (*models.NotificationRun, error)
+	// Dispatch sends the pending notifications that are due.
+	Dispatch(ctx context.Context) (*models.NotificationDispatch, error)
+
+	List(ctx context.Context, filter models.NotificationFilter) ([]models.Notification, error)
+	Get(ctx context.Context, id int) (*models.Notification, error)
+	// Resend queues a failed notification again.
+	Resend(ctx context.Context, id int) (*models.Notification, error)
+}
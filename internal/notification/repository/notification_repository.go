@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/notification"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+type notificationRepository struct {
+	DB *sql.DB
+}
+
+func NewNotificationRepository(DB *sql.DB) notification.NotificationRepository {
+	return &notificationRepository{
+		DB: DB,
+	}
+}
+
+func (r *notificationRepository) GetContact(ctx context.Context, borrowerID string) (*models.BorrowerContact, error) {
+	var c models.BorrowerContact
+	var email, phone sql.NullString
+	query := `SELECT borrower_id, email, phone, opt_out, updated_by, updated_at
+              FROM borrower_contacts WHERE borrower_id = $1`
+	err := postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, borrowerID).Scan(
+		&c.BorrowerID,
+		&email,
+		&phone,
+		&c.OptOut,
+		&c.UpdatedBy,
+		&c.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query borrower contact: %w", err)
+	}
+	c.Email, c.Phone = email.String, phone.String
+	return &c, nil
+}
+
+// SaveContact inserts or replaces the borrower's contact details.
+func (r *notificationRepository) SaveContact(ctx context.Context, c *models.BorrowerContact) error {
+	query := `INSERT INTO borrower_contacts (borrower_id, email, phone, opt_out, updated_by)
+              VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5)
+              ON CONFLICT (borrower_id) DO UPDATE
+              SET email = EXCLUDED.email,
+                  phone = EXCLUDED.phone,
+                  opt_out = EXCLUDED.opt_out,
+                  updated_by = EXCLUDED.updated_by,
+                  updated_at = CURRENT_TIMESTAMP
+              RETURNING updated_at`
+	err := postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, c.BorrowerID, c.Email, c.Phone, c.OptOut, c.UpdatedBy).
+		Scan(&c.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("save borrower contact: %w", err)
+	}
+	return nil
+}
+
+const notificationColumns = `id, kind, borrower_id, loan_id, channel, recipient, dedup_key, subject, body, status,
+                             attempts, error, next_attempt_at, created_at, sent_at`
+
+func scanNotification(row interface{ Scan(...any) error }, n *models.Notification) error {
+	var recipient, subject, notificationErr sql.NullString
+	err := row.Scan(
+		&n.ID,
+		&n.Kind,
+		&n.BorrowerID,
+		&n.LoanID,
+		&n.Channel,
+		&recipient,
+		&n.DedupKey,
+		&subject,
+		&n.Body,
+		&n.Status,
+		&n.Attempts,
+		&notificationErr,
+		&n.NextAttemptAt,
+		&n.CreatedAt,
+		&n.SentAt,
+	)
+	n.Recipient, n.Subject, n.Error = recipient.String, subject.String, notificationErr.String
+	return err
+}
+
+// Create implements [notification.NotificationRepository].
+func (r *notificationRepository) Create(ctx context.Context, n *models.Notification) error {
+	query := `INSERT INTO notifications (kind, borrower_id, loan_id, channel, recipient, dedup_key, subject, body, status,
+                                         error, next_attempt_at)
+              VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, NULLIF($7, ''), $8, $9, NULLIF($10, ''), $11)
+              ON CONFLICT (dedup_key) DO NOTHING
+              RETURNING id, created_at`
+	return postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, n.Kind, n.BorrowerID, n.LoanID, n.Channel, n.Recipient,
+		n.DedupKey, n.Subject, n.Body, n.Status, n.Error, n.NextAttemptAt).Scan(&n.ID, &n.CreatedAt)
+}
+
+func (r *notificationRepository) Get(ctx context.Context, id int) (*models.Notification, error) {
+	var n models.Notification
+	query := `SELECT ` + notificationColumns + ` FROM notifications WHERE id = $1`
+	err := scanNotification(postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, id), &n)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query notification: %w", err)
+	}
+	return &n, nil
+}
+
+// List returns the most recent notifications first.
+func (r *notificationRepository) List(ctx context.Context, filter models.NotificationFilter, limit int) ([]models.Notification, error) {
+	query := `SELECT ` + notificationColumns + ` FROM notifications
+              WHERE ($1 = '' OR borrower_id = $1) AND ($2 = 0 OR loan_id = $2) AND ($3 = '' OR status = $3)
+              ORDER BY id DESC
+              LIMIT $4`
+	return r.query(ctx, query, filter.BorrowerID, filter.LoanID, filter.Status, limit)
+}
+
+// Claim implements [notification.NotificationRepository].
+func (r *notificationRepository) Claim(ctx context.Context, now, lease time.Time, limit int) ([]models.Notification, error) {
+	query := `UPDATE notifications SET attempts = attempts + 1, next_attempt_at = $2
+              WHERE id IN (SELECT id FROM notifications
+                           WHERE status = $3 AND next_attempt_at <= $1
+                           ORDER BY next_attempt_at, id
+                           LIMIT $4
+                           FOR UPDATE SKIP LOCKED)
+              RETURNING ` + notificationColumns
+	return r.query(ctx, query, now, lease, models.NotificationPending, limit)
+}
+
+func (r *notificationRepository) query(ctx context.Context, query string, args ...any) ([]models.Notification, error) {
+	rows, err := postgres.Conn(ctx, r.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []models.Notification
+	for rows.Next() {
+		var n models.Notification
+		if err := scanNotification(rows, &n); err != nil {
+			return nil, fmt.Errorf("scan notification: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
+
+func (r *notificationRepository) Update(ctx context.Context, n *models.Notification) error {
+	query := `UPDATE notifications
+              SET status = $2, attempts = $3, error = NULLIF($4, ''), next_attempt_at = $5, sent_at = $6
+              WHERE id = $1`
+	_, err := postgres.Conn(ctx, r.DB).ExecContext(ctx, query, n.ID, n.Status, n.Attempts, n.Error, n.NextAttemptAt, n.SentAt)
+	if err != nil {
+		return fmt.Errorf("update notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,352 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/loan"
+	"github.com/evrintobing17/loan-billing-system/internal/notification"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+)
+
+const (
+	listLimit     = 200
+	dispatchBatch = 100
+	// sendLease is how long a claimed notification is held before another
+	// dispatcher may take it, should this one die mid-send.
+	sendLease = 5 * time.Minute
+	// retryBackoff is multiplied by the attempts made so far.
+	retryBackoff = 5 * time.Minute
+)
+
+type notificationUseCase struct {
+	notificationRepo notification.NotificationRepository
+	loanRepo         loan.LoanRepository
+	loanUC           loan.LoanUsecase
+	clock            clock.Clock
+	channels         map[string]notification.Channel
+	reminderDays     int
+	maxAttempts      int
+}
+
+// NewNotificationUseCase builds borrower notifications sent on the channels
+// given by name. Reminders go out reminderDays before an installment falls
+// due; a notification that failed maxAttempts times is given up.
+func NewNotificationUseCase(nr notification.NotificationRepository, lr loan.LoanRepository, luc loan.LoanUsecase,
+	clk clock.Clock, channels map[string]notification.Channel, reminderDays, maxAttempts int) notification.NotificationUsecase {
+	return &notificationUseCase{
+		notificationRepo: nr,
+		loanRepo:         lr,
+		loanUC:           luc,
+		clock:            clk,
+		channels:         channels,
+		reminderDays:     reminderDays,
+		maxAttempts:      maxAttempts,
+	}
+}
+
+func (uc *notificationUseCase) GetContact(ctx context.Context, borrowerID string) (*models.BorrowerContact, error) {
+	c, err := uc.notificationRepo.GetContact(ctx, borrowerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notification.ErrContactNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+func (uc *notificationUseCase) SetContact(ctx context.Context, borrowerID string, req models.SetContactRequest, actor string) (*models.BorrowerContact, error) {
+	c := &models.BorrowerContact{
+		BorrowerID: borrowerID,
+		Email:      req.Email,
+		Phone:      req.Phone,
+		OptOut:     req.OptOut,
+		UpdatedBy:  actor,
+	}
+	if err := uc.notificationRepo.SaveContact(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// PaymentMade implements [notification.NotificationUsecase]. It runs inside
+// the payment's transaction, so the receipt is queued only if the payment
+// commits; a receipt that cannot be queued does not hold up the payment.
+func (uc *notificationUseCase) PaymentMade(ctx context.Context, p *models.Payment) error {
+	if notification.Suppressed(ctx) {
+		return nil
+	}
+	l, err := uc.loanRepo.GetByID(ctx, p.LoanID)
+	if err != nil {
+		return err
+	}
+	outstanding, err := uc.loanUC.GetOutstanding(ctx, p.LoanID)
+	if err != nil {
+		return err
+	}
+	_, err = uc.queue(ctx, models.NotificationPaymentReceipt, fmt.Sprint(p.ID), &message{
+		Loan:        l,
+		Payment:     p,
+		Outstanding: outstanding,
+	})
+	return err
+}
+
+// Run implements [notification.NotificationUsecase]. Each event is queued
+// once however often Run is repeated: a reminder per installment and an alert
+// per delinquency, keyed on the oldest installment left unpaid.
+func (uc *notificationUseCase) Run(ctx context.Context, businessDate time.Time) (*models.NotificationRun, error) {
+	if businessDate.IsZero() {
+		businessDate = clock.Today(ctx, uc.clock)
+	}
+	businessDate = clock.Date(businessDate)
+	ctx = clock.WithAsOfDate(ctx, businessDate)
+
+	run := &models.NotificationRun{BusinessDate: businessDate}
+
+	loans, err := uc.loanRepo.ListByStatus(ctx, models.LoanStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	remindBy := businessDate.AddDate(0, 0, uc.reminderDays)
+	for i := range loans {
+		n, err := uc.remind(ctx, &loans[i], businessDate, remindBy)
+		if err != nil {
+			run.Errors = append(run.Errors, fmt.Sprintf("loan %d: %v", loans[i].ID, err))
+			continue
+		}
+		run.Reminders += n
+	}
+
+	sweep, err := uc.loanUC.SweepDelinquency(ctx, businessDate)
+	if err != nil {
+		return nil, err
+	}
+	run.Errors = append(run.Errors, sweep.Errors...)
+	for _, d := range sweep.Loans {
+		if !d.Delinquent {
+			continue
+		}
+		n, err := uc.alert(ctx, d, businessDate)
+		if err != nil {
+			run.Errors = append(run.Errors, fmt.Sprintf("loan %d: %v", d.LoanID, err))
+			continue
+		}
+		run.Alerts += n
+	}
+	return run, nil
+}
+
+// remind queues reminders of the loan's unpaid installments due from today
+// to by and returns how many it queued. The window catches installments a
+// skipped run would have reminded of; the reminder keyed on the installment
+// keeps later runs from sending it again.
+func (uc *notificationUseCase) remind(ctx context.Context, l *models.Loan, today, by time.Time) (int, error) {
+	installments, err := uc.loanRepo.GetInstallments(ctx, l.ID)
+	if err != nil {
+		return 0, err
+	}
+	queued := 0
+	for i := range installments {
+		inst := &installments[i]
+		due := clock.Date(inst.DueDate)
+		if inst.Paid || inst.Closed || due.Before(today) || due.After(by) {
+			continue
+		}
+		n, err := uc.queue(ctx, models.NotificationDueReminder, fmt.Sprint(inst.ID), &message{Loan: l, Installment: inst})
+		if err != nil {
+			return queued, err
+		}
+		queued += n
+	}
+	return queued, nil
+}
+
+// alert queues the delinquency alert for the loan and returns how many it
+// queued; none once the borrower was alerted of the same arrears.
+func (uc *notificationUseCase) alert(ctx context.Context, d models.LoanDelinquency, date time.Time) (int, error) {
+	l, err := uc.loanRepo.GetByID(ctx, d.LoanID)
+	if err != nil {
+		return 0, err
+	}
+	installments, err := uc.loanRepo.GetInstallments(ctx, l.ID)
+	if err != nil {
+		return 0, err
+	}
+	charges, err := uc.loanRepo.GetCharges(ctx, l.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	var oldest *models.Installment
+	overdue := 0.0
+	for i := range installments {
+		inst := &installments[i]
+		if inst.Paid || inst.Closed || inst.DueDate.After(date) {
+			continue
+		}
+		if oldest == nil || inst.DueDate.Before(oldest.DueDate) {
+			oldest = inst
+		}
+		overdue += inst.Amount
+	}
+	if oldest == nil {
+		return 0, nil
+	}
+	for _, ch := range charges {
+		if !ch.Paid && !ch.Closed && !ch.DueDate.After(date) {
+			overdue += ch.Amount
+		}
+	}
+
+	return uc.queue(ctx, models.NotificationDelinquencyAlert, fmt.Sprintf("%d:%d", l.ID, oldest.ID), &message{
+		Loan:          l,
+		DaysPastDue:   d.DaysPastDue,
+		AmountOverdue: math.Round(overdue*100) / 100,
+	})
+}
+
+// queue stores the notification of the event on every channel and returns
+// how many were new. Borrowers who opted out or have no address for a
+// channel get a skipped notification, so the listing shows why nothing was
+// sent.
+func (uc *notificationUseCase) queue(ctx context.Context, kind, event string, msg *message) (int, error) {
+	if notification.Suppressed(ctx) {
+		return 0, nil
+	}
+	contact, err := uc.notificationRepo.GetContact(ctx, msg.Loan.BorrowerID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	names := make([]string, 0, len(uc.channels))
+	for name := range uc.channels {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	queued := 0
+	for _, name := range names {
+		ch := uc.channels[name]
+		subject, body, err := render(kind, ch.Short(), msg)
+		if err != nil {
+			return queued, fmt.Errorf("render %s notification: %w", kind, err)
+		}
+		n := &models.Notification{
+			Kind:          kind,
+			BorrowerID:    msg.Loan.BorrowerID,
+			LoanID:        msg.Loan.ID,
+			Channel:       name,
+			Recipient:     ch.Recipient(msg.Loan.BorrowerID, contact),
+			DedupKey:      fmt.Sprintf("%s:%s:%s", kind, event, name),
+			Subject:       subject,
+			Body:          body,
+			Status:        models.NotificationPending,
+			NextAttemptAt: uc.clock.Now(),
+		}
+		switch {
+		case contact != nil && contact.OptOut:
+			n.Status, n.Error = models.NotificationSkipped, "borrower opted out"
+		case n.Recipient == "":
+			n.Status, n.Error = models.NotificationSkipped, fmt.Sprintf("no %s recipient", name)
+		}
+		if err := uc.notificationRepo.Create(ctx, n); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return queued, err
+		}
+		queued++
+	}
+	return queued, nil
+}
+
+// Dispatch implements [notification.NotificationUsecase]. A failed send is
+// retried with a growing delay until maxAttempts is reached.
+func (uc *notificationUseCase) Dispatch(ctx context.Context) (*models.NotificationDispatch, error) {
+	result := &models.NotificationDispatch{}
+	for {
+		now := uc.clock.Now()
+		batch, err := uc.notificationRepo.Claim(ctx, now, now.Add(sendLease), dispatchBatch)
+		if err != nil {
+			return result, err
+		}
+		for i := range batch {
+			if err := uc.send(ctx, &batch[i], result); err != nil {
+				return result, err
+			}
+		}
+		if len(batch) < dispatchBatch {
+			return result, nil
+		}
+	}
+}
+
+func (uc *notificationUseCase) send(ctx context.Context, n *models.Notification, result *models.NotificationDispatch) error {
+	var err error
+	if ch, ok := uc.channels[n.Channel]; ok {
+		err = ch.Send(ctx, n.Recipient, n.Subject, n.Body)
+	} else {
+		err = fmt.Errorf("channel %s is not configured", n.Channel)
+	}
+
+	now := uc.clock.Now()
+	switch {
+	case err == nil:
+		n.Status, n.Error, n.SentAt = models.NotificationSent, "", &now
+		result.Sent++
+	case n.Attempts >= uc.maxAttempts:
+		n.Status, n.Error = models.NotificationFailed, err.Error()
+		result.Failed++
+	default:
+		n.Error, n.NextAttemptAt = err.Error(), now.Add(time.Duration(n.Attempts)*retryBackoff)
+		result.Retrying++
+	}
+	return uc.notificationRepo.Update(ctx, n)
+}
+
+func (uc *notificationUseCase) List(ctx context.Context, filter models.NotificationFilter) ([]models.Notification, error) {
+	notifications, err := uc.notificationRepo.List(ctx, filter, listLimit)
+	if err != nil {
+		return nil, err
+	}
+	if notifications == nil {
+		notifications = []models.Notification{}
+	}
+	return notifications, nil
+}
+
+func (uc *notificationUseCase) Get(ctx context.Context, id int) (*models.Notification, error) {
+	n, err := uc.notificationRepo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notification.ErrNotificationNotFound
+		}
+		return nil, err
+	}
+	return n, nil
+}
+
+// Resend implements [notification.NotificationUsecase]. The notification
+// gets a fresh set of attempts and goes out with the next dispatch.
+func (uc *notificationUseCase) Resend(ctx context.Context, id int) (*models.Notification, error) {
+	n, err := uc.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if n.Status != models.NotificationFailed {
+		return nil, notification.ErrNotFailed
+	}
+	n.Status, n.Attempts, n.Error, n.NextAttemptAt = models.NotificationPending, 0, "", uc.clock.Now()
+	if err := uc.notificationRepo.Update(ctx, n); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// Each template file is named after its notification kind and defines a
+// "subject", a "body" for email and a "short" text for SMS.
+//
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = parseTemplates(models.NotificationDueReminder, models.NotificationPaymentReceipt, models.NotificationDelinquencyAlert)
+
+func parseTemplates(kinds ...string) map[string]*template.Template {
+	funcs := template.FuncMap{
+		"money": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+		"date":  func(t time.Time) string { return t.Format("2 Jan 2006") },
+	}
+	parsed := make(map[string]*template.Template, len(kinds))
+	for _, kind := range kinds {
+		parsed[kind] = template.Must(template.New(kind).Funcs(funcs).ParseFS(templateFS, "templates/"+kind+".tmpl"))
+	}
+	return parsed
+}
+
+// message is what templates are rendered with; fields a kind does not use
+// are left empty.
+type message struct {
+	Loan          *models.Loan
+	Installment   *models.Installment
+	Payment       *models.Payment
+	Outstanding   float64
+	DaysPastDue   int
+	AmountOverdue float64
+}
+
+// render renders a notification of the kind, the short text for channels
+// that take it.
+func render(kind string, short bool, msg *message) (subject, body string, err error) {
+	t := templates[kind]
+	if t == nil {
+		return "", "", fmt.Errorf("no template for %s notifications", kind)
+	}
+
+	var sb strings.Builder
+	if short {
+		if err := t.ExecuteTemplate(&sb, "short", msg); err != nil {
+			return "", "", err
+		}
+		return "", strings.TrimSpace(sb.String()), nil
+	}
+	if err := t.ExecuteTemplate(&sb, "subject", msg); err != nil {
+		return "", "", err
+	}
+	subject = strings.TrimSpace(sb.String())
+	sb.Reset()
+	if err := t.ExecuteTemplate(&sb, "body", msg); err != nil {
+		return "", "", err
+	}
+	return subject, strings.TrimSpace(sb.String()), nil
+}
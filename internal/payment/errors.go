@@ -0,0 +1,17 @@
+package payment
+
+import "github.com/evrintobing17/loan-billing-system/pkg/apperror"
+
+var (
+	ErrInvalidAmount            = apperror.New(apperror.Invalid, "invalid_amount", "amount must be positive")
+	ErrNothingDue               = apperror.New(apperror.Unprocessable, "nothing_due", "no installments are due for payment")
+	ErrAmountMismatch           = apperror.New(apperror.Unprocessable, "amount_mismatch", "payment amount must cover all overdue installments")
+	ErrExceedsWrittenOffBalance = apperror.New(apperror.Unprocessable, "amount_exceeds_written_off_balance", "amount exceeds written-off balance")
+	ErrNothingToSettle          = apperror.New(apperror.Unprocessable, "nothing_to_settle", "loan has nothing outstanding to settle")
+	ErrIdempotencyKeyRequired   = apperror.New(apperror.Invalid, "idempotency_key_required", "Idempotency-Key header required")
+	ErrNotFound                 = apperror.New(apperror.NotFound, "payment_not_found", "payment not found")
+	ErrAlreadyReversed          = apperror.New(apperror.Conflict, "payment_already_reversed", "payment has already been reversed")
+	ErrNotReversible            = apperror.New(apperror.Unprocessable, "payment_not_reversible", "payment cannot be reversed")
+	ErrDueItemsChanged          = apperror.New(apperror.Conflict, "due_items_changed", "installments or charges were settled or closed meanwhile, retry the payment")
+	ErrReversalReasonRequired   = apperror.New(apperror.Invalid, "reversal_reason_required", "a reason is required to reverse a payment")
+)
@@ -6,6 +6,7 @@ import (
 
 	"github.com/evrintobing17/loan-billing-system/internal/payment"
 	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,47 +20,150 @@ func NewPaymentHandler(uc payment.PaymentUsecase) *PaymentHandler {
 
 // MakePayment godoc
 // @Summary Make a payment against a loan
-// @Description Process a payment. Idempotency-Key header prevents duplicates.
+// @Description Process a payment. Idempotency-Key header prevents duplicates. Payments on written-off loans are booked as recoveries.
 // @Tags payments
 // @Accept json
 // @Produce json
 // @Param id path int true "Loan ID"
 // @Param request body models.PaymentRequest true "Payment amount"
 // @Param Idempotency-Key header string true "Unique idempotency key"
+// @Param X-As-Of-Date header string false "Business date override, YYYY-MM-DD (admin only)"
+// @Param X-Admin-Key header string false "Admin key, required with X-As-Of-Date"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 500 {object} models.Problem
 // @Router /loans/{id}/payments [post]
 func (h *PaymentHandler) MakePayment(c *gin.Context) {
 	var req models.PaymentRequest
 	loanID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid loan id"})
+		c.Error(apperror.Invalidf("invalid loan id"))
 		return
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.Default(err, apperror.Invalid))
 		return
 	}
 
 	idempotencyKey := c.GetHeader("Idempotency-Key")
 	if idempotencyKey == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key header required"})
+		c.Error(payment.ErrIdempotencyKeyRequired)
 		return
 	}
 
-	err = h.paymentUC.MakePayment(c.Request.Context(), loanID, req.Amount, idempotencyKey)
+	_, err = h.paymentUC.MakePayment(c.Request.Context(), loanID, req.Amount, idempotencyKey)
 	if err != nil {
-		if err.Error() == "amount must be a positive multiple of weekly installment" ||
-			err.Error() == "amount exceeds total outstanding" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "payment processed"})
 }
+
+// PayVirtualAccount godoc
+// @Summary Make a payment to a virtual account
+// @Description Same as paying the loan directly, addressed by the loan's virtual account number instead of its ID.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param number path string true "Virtual account number"
+// @Param request body models.PaymentRequest true "Payment amount"
+// @Param Idempotency-Key header string true "Unique idempotency key"
+// @Param X-As-Of-Date header string false "Business date override, YYYY-MM-DD (admin only)"
+// @Param X-Admin-Key header string false "Admin key, required with X-As-Of-Date"
+// @Success 200 {object} map[string]any
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /virtual-accounts/{number}/payments [post]
+func (h *PaymentHandler) PayVirtualAccount(c *gin.Context) {
+	var req models.PaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+
+	idempotencyKey := c.GetHeader("Idempotency-Key")
+	if idempotencyKey == "" {
+		c.Error(payment.ErrIdempotencyKeyRequired)
+		return
+	}
+
+	l, err := h.paymentUC.PayVirtualAccount(c.Request.Context(), c.Param("number"), req.Amount, idempotencyKey)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "payment processed", "loan_id": l.ID, "virtual_account": l.VirtualAccount})
+}
+
+// ListPayments godoc
+// @Summary List a loan's payments
+// @Description Payments made on a loan, oldest first, including reversed ones.
+// @Tags payments
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Success 200 {array} models.Payment
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /loans/{id}/payments [get]
+func (h *PaymentHandler) ListPayments(c *gin.Context) {
+	loanID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid loan id"))
+		return
+	}
+
+	payments, err := h.paymentUC.ListPayments(c.Request.Context(), loanID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, payments)
+}
+
+// ReversePayment godoc
+// @Summary Reverse a payment (admin)
+// @Description Reopens the installments and charges the payment settled and reverses its journal entry. Settlements and payments made before a restructure cannot be reversed.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param id path int true "Payment ID"
+// @Param request body models.ReversePaymentRequest true "Reversal reason"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.Payment
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /payments/{id}/reverse [post]
+func (h *PaymentHandler) ReversePayment(c *gin.Context) {
+	paymentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid payment id"))
+		return
+	}
+
+	var req models.ReversePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+
+	reversed, err := h.paymentUC.ReversePayment(c.Request.Context(), paymentID, req.Reason)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, reversed)
+}
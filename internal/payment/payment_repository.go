@@ -7,6 +7,16 @@ import (
 )
 
 type PaymentRepository interface {
-	Create(ctx context.Context, payment *models.Payment, installmentIDs []int) error
+	// Create stores the payment and settles the given installments and
+	// charges. It returns ErrDueItemsChanged when any of them is no longer open.
+	Create(ctx context.Context, payment *models.Payment, installmentIDs, chargeIDs []int) error
 	GetByIdempotencyKey(ctx context.Context, key string) (*models.Payment, error)
+	GetByID(ctx context.Context, id int) (*models.Payment, error)
+	ListByLoanID(ctx context.Context, loanID int) ([]models.Payment, error)
+	// ScheduleVersions returns the schedule versions of the installments and
+	// charges a payment settled.
+	ScheduleVersions(ctx context.Context, paymentID int) ([]int, error)
+	// Reverse marks the payment reversed and reopens what it settled. It
+	// returns sql.ErrNoRows when the payment is missing or already reversed.
+	Reverse(ctx context.Context, paymentID int, reason string) error
 }
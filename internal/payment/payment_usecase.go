@@ -1,7 +1,35 @@
 package payment
 
-import "context"
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
 
 type PaymentUsecase interface {
-	MakePayment(ctx context.Context, loanID int, amount float64, idempotencyKey string) error
+	// MakePayment pays the installments and charges due, or recovers on a
+	// written-off loan. A key used before returns that payment and pays
+	// nothing.
+	MakePayment(ctx context.Context, loanID int, amount float64, idempotencyKey string) (*models.Payment, error)
+	// PayVirtualAccount makes a payment to the loan a virtual account number
+	// belongs to and returns that loan.
+	PayVirtualAccount(ctx context.Context, number string, amount float64, idempotencyKey string) (*models.Loan, error)
+	// AmountDue is what an installment payment on an active loan must be as
+	// of the business date; zero when nothing is due.
+	AmountDue(ctx context.Context, loanID int) (float64, error)
+	// SettleLoan pays off every open installment and charge of an active loan,
+	// due or not, with an internal settlement payment.
+	SettleLoan(ctx context.Context, loanID int) (*models.Payment, error)
+	ListPayments(ctx context.Context, loanID int) ([]models.Payment, error)
+	// ReversePayment undoes a payment: what it settled is due again and its
+	// journal entry is reversed. Settlements and payments made before a
+	// restructure cannot be reversed.
+	ReversePayment(ctx context.Context, paymentID int, reason string) (*models.Payment, error)
+}
+
+// PaymentObserver is told about each installment payment inside the
+// transaction that records it. Its writes commit with the payment, but its
+// failure is only logged: it never rolls the payment back.
+type PaymentObserver interface {
+	PaymentMade(ctx context.Context, p *models.Payment) error
 }
@@ -3,9 +3,13 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 
 	"github.com/evrintobing17/loan-billing-system/internal/payment"
 	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/outbox"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
 	"github.com/lib/pq"
 )
 
@@ -19,44 +23,228 @@ func NewPaymentRepository(DB *sql.DB) payment.PaymentRepository {
 	}
 }
 
-// Create implements [payment.PaymentRepository].
-func (p *paymentRepository) Create(ctx context.Context, payment *models.Payment, installmentIDs []int) error {
-	tx, err := p.DB.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-	query := `INSERT INTO payments (loan_id, amount, idempotency_key) 
-              VALUES ($1, $2, $3) RETURNING id, payment_date`
-	err = tx.QueryRowContext(ctx, query, payment.LoanID, payment.Amount, payment.IdempotencyKey).
-		Scan(&payment.ID, &payment.PaymentDate)
-	if err != nil {
-		return err
-	}
+const paymentColumns = `id, loan_id, amount, payment_date, idempotency_key, payment_type, reversed_at, reversal_reason`
 
-	// Mark installments as paid
-	if len(installmentIDs) > 0 {
-		// Update installments set paid=true, payment_id = ?
-		_, err = tx.ExecContext(ctx,
-			`UPDATE installments SET paid = true WHERE id = ANY($1)`, pq.Array(installmentIDs))
+func scanPayment(row interface{ Scan(...any) error }, p *models.Payment) error {
+	return row.Scan(
+		&p.ID,
+		&p.LoanID,
+		&p.Amount,
+		&p.PaymentDate,
+		&p.IdempotencyKey,
+		&p.PaymentType,
+		&p.ReversedAt,
+		&p.ReversalReason,
+	)
+}
+
+// Create implements [payment.PaymentRepository]. A zero PaymentDate is
+// stamped with the current time. The payment.received event, and an
+// installment.settled event per installment, go to the outbox with it. Only
+// open items are settled; payment.ErrDueItemsChanged is returned when any of
+// them was paid or closed meanwhile.
+func (p *paymentRepository) Create(ctx context.Context, pay *models.Payment, installmentIDs, chargeIDs []int) error {
+	return postgres.RunInTx(ctx, p.DB, func(tx postgres.DBTX) error {
+		query := `INSERT INTO payments (loan_id, amount, idempotency_key, payment_type, payment_date)
+                  VALUES ($1, $2, $3, $4, COALESCE($5, CURRENT_TIMESTAMP)) RETURNING id, payment_date`
+		paymentDate := sql.NullTime{Time: pay.PaymentDate, Valid: !pay.PaymentDate.IsZero()}
+		err := tx.QueryRowContext(ctx, query, pay.LoanID, pay.Amount, pay.IdempotencyKey, pay.PaymentType, paymentDate).
+			Scan(&pay.ID, &pay.PaymentDate)
 		if err != nil {
 			return err
 		}
 
-		// Link payment to installments
-		for _, instID := range installmentIDs {
-			_, err = tx.ExecContext(ctx,
-				`INSERT INTO payment_installments (payment_id, installment_id) VALUES ($1, $2)`,
-				payment.ID, instID)
+		received, err := outbox.LoanEvent(models.EventPaymentReceived, 1, pay.LoanID, pay.PaymentDate, models.PaymentReceivedV1{
+			PaymentID:      pay.ID,
+			LoanID:         pay.LoanID,
+			Amount:         pay.Amount,
+			PaymentType:    pay.PaymentType,
+			PaymentDate:    pay.PaymentDate,
+			InstallmentIDs: nonNil(installmentIDs),
+			ChargeIDs:      nonNil(chargeIDs),
+		})
+		if err != nil {
+			return err
+		}
+		events := []models.OutboxEvent{received}
+
+		// Mark installments as paid
+		if len(installmentIDs) > 0 {
+			rows, err := tx.QueryContext(ctx,
+				`UPDATE installments SET paid = true WHERE id = ANY($1) AND paid IS FALSE AND closed IS FALSE
+                 RETURNING id, week_number, due_date, amount`, pq.Array(installmentIDs))
+			if err != nil {
+				return err
+			}
+			for rows.Next() {
+				s := models.InstallmentSettledV1{LoanID: pay.LoanID, PaymentID: pay.ID}
+				if err := rows.Scan(&s.InstallmentID, &s.WeekNumber, &s.DueDate, &s.Amount); err != nil {
+					rows.Close()
+					return err
+				}
+				e, err := outbox.LoanEvent(models.EventInstallmentSettled, 1, pay.LoanID, pay.PaymentDate, s)
+				if err != nil {
+					rows.Close()
+					return err
+				}
+				events = append(events, e)
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			if len(events)-1 != len(installmentIDs) {
+				return payment.ErrDueItemsChanged
+			}
+
+			// Link payment to installments
+			for _, instID := range installmentIDs {
+				_, err = tx.ExecContext(ctx,
+					`INSERT INTO payment_installments (payment_id, installment_id) VALUES ($1, $2)`,
+					pay.ID, instID)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		// Mark separately charged fees as paid
+		if len(chargeIDs) > 0 {
+			res, err := tx.ExecContext(ctx,
+				`UPDATE loan_charges SET paid = true WHERE id = ANY($1) AND paid IS FALSE AND closed IS FALSE`,
+				pq.Array(chargeIDs))
+			if err != nil {
+				return err
+			}
+			n, err := res.RowsAffected()
 			if err != nil {
 				return err
 			}
+			if int(n) != len(chargeIDs) {
+				return payment.ErrDueItemsChanged
+			}
+
+			for _, chargeID := range chargeIDs {
+				_, err = tx.ExecContext(ctx,
+					`INSERT INTO payment_charges (payment_id, charge_id) VALUES ($1, $2)`,
+					pay.ID, chargeID)
+				if err != nil {
+					return err
+				}
+			}
 		}
+		return outbox.Append(ctx, tx, events...)
+	})
+}
+
+// nonNil makes a nil slice marshal as [] rather than null.
+func nonNil(ids []int) []int {
+	if ids == nil {
+		return []int{}
 	}
-	return tx.Commit()
+	return ids
 }
 
-// GetByIdempotencyKey implements [payment.PaymentRepository].
+// GetByIdempotencyKey implements [payment.PaymentRepository]. It returns the
+// latest payment made with the key.
 func (p *paymentRepository) GetByIdempotencyKey(ctx context.Context, key string) (*models.Payment, error) {
-	panic("unimplemented")
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE idempotency_key = $1 ORDER BY id DESC LIMIT 1`
+	return p.getOne(ctx, query, key)
+}
+
+func (p *paymentRepository) GetByID(ctx context.Context, id int) (*models.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE id = $1`
+	return p.getOne(ctx, query, id)
+}
+
+func (p *paymentRepository) getOne(ctx context.Context, query string, arg any) (*models.Payment, error) {
+	var payment models.Payment
+	err := scanPayment(postgres.Conn(ctx, p.DB).QueryRowContext(ctx, query, arg), &payment)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query payment: %w", err)
+	}
+	return &payment, nil
+}
+
+func (p *paymentRepository) ListByLoanID(ctx context.Context, loanID int) ([]models.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE loan_id = $1 ORDER BY id`
+	rows, err := postgres.Conn(ctx, p.DB).QueryContext(ctx, query, loanID)
+	if err != nil {
+		return nil, fmt.Errorf("query payments: %w", err)
+	}
+	defer rows.Close()
+
+	var payments []models.Payment
+	for rows.Next() {
+		var payment models.Payment
+		if err := scanPayment(rows, &payment); err != nil {
+			return nil, fmt.Errorf("scan payment: %w", err)
+		}
+		payments = append(payments, payment)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return payments, nil
+}
+
+// ScheduleVersions implements [payment.PaymentRepository].
+func (p *paymentRepository) ScheduleVersions(ctx context.Context, paymentID int) ([]int, error) {
+	query := `SELECT i.schedule_version FROM payment_installments pi
+              JOIN installments i ON i.id = pi.installment_id WHERE pi.payment_id = $1
+              UNION
+              SELECT c.schedule_version FROM payment_charges pc
+              JOIN loan_charges c ON c.id = pc.charge_id WHERE pc.payment_id = $1`
+	rows, err := postgres.Conn(ctx, p.DB).QueryContext(ctx, query, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("query schedule versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []int
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return nil, fmt.Errorf("scan schedule version: %w", err)
+		}
+		versions = append(versions, v)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return versions, nil
+}
+
+// Reverse implements [payment.PaymentRepository]. The payment links are kept
+// so the history still shows what the payment once settled.
+func (p *paymentRepository) Reverse(ctx context.Context, paymentID int, reason string) error {
+	return postgres.RunInTx(ctx, p.DB, func(tx postgres.DBTX) error {
+		res, err := tx.ExecContext(ctx,
+			`UPDATE payments SET reversed_at = CURRENT_TIMESTAMP, reversal_reason = $2
+             WHERE id = $1 AND reversed_at IS NULL`, paymentID, reason)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return sql.ErrNoRows
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`UPDATE installments SET paid = false
+             WHERE id IN (SELECT installment_id FROM payment_installments WHERE payment_id = $1)`, paymentID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			`UPDATE loan_charges SET paid = false
+             WHERE id IN (SELECT charge_id FROM payment_charges WHERE payment_id = $1)`, paymentID)
+		return err
+	})
 }
@@ -2,101 +2,373 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"log"
+	"math"
 	"time"
 
+	"github.com/evrintobing17/loan-billing-system/internal/accounting"
 	"github.com/evrintobing17/loan-billing-system/internal/loan"
 	"github.com/evrintobing17/loan-billing-system/internal/payment"
 	"github.com/evrintobing17/loan-billing-system/models"
-	"github.com/evrintobing17/loan-billing-system/pkg/idempotency"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+	"github.com/evrintobing17/loan-billing-system/pkg/virtualaccount"
 )
 
 type paymentUseCase struct {
-	paymentRepo payment.PaymentRepository
-	loanRepo    loan.LoanRepository
-	idempStore  idempotency.Store
+	paymentRepo  payment.PaymentRepository
+	loanRepo     loan.LoanRepository
+	accountingUC accounting.AccountingUsecase
+	tx           postgres.Transactor
+	clock        clock.Clock
+	observers    []payment.PaymentObserver
 }
 
-func NewPaymentUseCase(pr payment.PaymentRepository, lr loan.LoanRepository, idemp idempotency.Store) payment.PaymentUsecase {
+// NewPaymentUseCase builds the payment usecase. Observers are told about
+// every installment payment before it commits.
+func NewPaymentUseCase(pr payment.PaymentRepository, lr loan.LoanRepository, auc accounting.AccountingUsecase, tx postgres.Transactor,
+	clk clock.Clock, observers ...payment.PaymentObserver) payment.PaymentUsecase {
 	return &paymentUseCase{
-		paymentRepo: pr,
-		loanRepo:    lr,
-		idempStore:  idemp,
+		paymentRepo:  pr,
+		loanRepo:     lr,
+		accountingUC: auc,
+		tx:           tx,
+		clock:        clk,
+		observers:    observers,
 	}
 }
 
-func (uc *paymentUseCase) MakePayment(ctx context.Context, loanID int, amount float64, idempotencyKey string) error {
+func (uc *paymentUseCase) PayVirtualAccount(ctx context.Context, number string, amount float64, idempotencyKey string) (*models.Loan, error) {
+	number = virtualaccount.Normalize(number)
+	if !virtualaccount.Valid(number) {
+		return nil, loan.ErrInvalidVirtualAccount
+	}
+	l, err := uc.loanRepo.GetByVirtualAccount(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.MakePayment(ctx, l.ID, amount, idempotencyKey); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// MakePayment implements [payment.PaymentUsecase]. Keys are checked against
+// the payments table, so a key is never paid twice however late the retry.
+func (uc *paymentUseCase) MakePayment(ctx context.Context, loanID int, amount float64, idempotencyKey string) (*models.Payment, error) {
 	// Idempotency check
 	if idempotencyKey != "" {
-		exists, err := uc.idempStore.Exists(ctx, idempotencyKey)
+		p, err := uc.paymentRepo.GetByIdempotencyKey(ctx, idempotencyKey)
+		if err == nil {
+			// Already processed, silently succeed
+			return p, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	// Validate loan. It is locked before its due items are totalled, so a
+	// concurrent payment, settlement or restructure cannot change them
+	// before this payment commits.
+	var made *models.Payment
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		l, err := uc.loanRepo.GetForUpdate(ctx, loanID)
 		if err != nil {
 			return err
 		}
-		if exists {
-			// Already processed, silently succeed
-			return nil
+		if amount <= 0 {
+			return payment.ErrInvalidAmount
+		}
+		if l.Status == models.LoanStatusWrittenOff {
+			made, err = uc.makeRecovery(ctx, l, amount, idempotencyKey)
+			return err
+		}
+		if l.Status != models.LoanStatusActive {
+			return loan.ErrNotActive
+		}
+
+		due, err := uc.due(ctx, loanID)
+		if err != nil {
+			return err
+		}
+		if len(due.instIDs) == 0 && len(due.chargeIDs) == 0 {
+			// No installments are due – cannot pay ahead
+			return payment.ErrNothingDue
+		}
+		if math.Abs(amount-due.total) > 0.005 {
+			return payment.ErrAmountMismatch.Withf("payment amount must cover all overdue installments: %.2f due", due.total)
 		}
-	}
 
-	// Validate loan
-	loan, err := uc.loanRepo.GetByID(ctx, loanID)
+		// Create payment record and mark installments as paid
+		made = &models.Payment{
+			LoanID:         loanID,
+			Amount:         amount,
+			IdempotencyKey: idempotencyKey,
+			PaymentType:    models.PaymentTypeInstallment,
+			PaymentDate:    backdated(ctx),
+		}
+		if err := uc.paymentRepo.Create(ctx, made, due.instIDs, due.chargeIDs); err != nil {
+			return err
+		}
+		if err := uc.accountingUC.RecordPayment(ctx, made, due.split); err != nil {
+			return err
+		}
+		for _, o := range uc.observers {
+			err := postgres.Savepoint(ctx, "payment_observer", func(ctx context.Context) error {
+				return o.PaymentMade(ctx, made)
+			})
+			if err != nil {
+				log.Printf("payment %d: notify observer: %v", made.ID, err)
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return err
-	}
-	if amount <= 0 || int(amount*100)%int(loan.WeeklyAmount*100) != 0 {
-		return errors.New("amount must be a positive multiple of weekly installment")
+		return nil, err
 	}
+	return made, nil
+}
 
-	// Get all installments for the loan
+// dueItems are the unpaid installments and charges due by the business date,
+// which an installment payment must cover exactly.
+type dueItems struct {
+	instIDs, chargeIDs []int
+	total              float64
+	split              models.PaymentSplit
+}
+
+func (uc *paymentUseCase) due(ctx context.Context, loanID int) (*dueItems, error) {
+	// Get all installments and separately charged fees for the loan
 	installments, err := uc.loanRepo.GetInstallments(ctx, loanID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	charges, err := uc.loanRepo.GetCharges(ctx, loanID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Filter unpaid installments that are due (due_date <= today)
-	today := time.Now().Truncate(24 * time.Hour)
-	var dueUnpaid []models.Installment
-	var totalDue float64
+	// Filter unpaid installments and charges that are due (due_date <= today)
+	today := clock.Today(ctx, uc.clock)
+	due := &dueItems{}
+	unpaid := 0
 	for _, inst := range installments {
-		if !inst.Paid && !inst.DueDate.After(today) {
-			dueUnpaid = append(dueUnpaid, inst)
-			totalDue += inst.Amount
-
+		if inst.Paid {
+			continue
+		}
+		unpaid++
+		if !inst.DueDate.After(today) {
+			due.instIDs = append(due.instIDs, inst.ID)
+			due.total += inst.Amount
+			due.split.Principal += inst.PrincipalAmount
+			due.split.Interest += inst.InterestAmount
+			due.split.Fees += inst.FeeAmount
 		}
 	}
+	due.split.Final = unpaid > 0 && len(due.instIDs) == unpaid
+	for _, charge := range charges {
+		if !charge.Paid && !charge.DueDate.After(today) {
+			due.chargeIDs = append(due.chargeIDs, charge.ID)
+			due.total += charge.Amount
+			due.split.Fees += charge.Amount
+		}
+	}
+	due.total = math.Round(due.total*100) / 100
+	return due, nil
+}
+
+// AmountDue implements [payment.PaymentUsecase].
+func (uc *paymentUseCase) AmountDue(ctx context.Context, loanID int) (float64, error) {
+	l, err := uc.loanRepo.GetByID(ctx, loanID)
+	if err != nil {
+		return 0, err
+	}
+	if l.Status != models.LoanStatusActive {
+		return 0, loan.ErrNotActive
+	}
+	due, err := uc.due(ctx, loanID)
+	if err != nil {
+		return 0, err
+	}
+	return due.total, nil
+}
+
+// SettleLoan implements [payment.PaymentUsecase]. The payment is keyed on the
+// loan so a loan can only be settled once. The loan is locked before the
+// payoff is totalled, so a concurrent payment or restructure cannot make it
+// stale.
+func (uc *paymentUseCase) SettleLoan(ctx context.Context, loanID int) (*models.Payment, error) {
+	var settlement *models.Payment
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		l, err := uc.loanRepo.GetForUpdate(ctx, loanID)
+		if err != nil {
+			return err
+		}
+		if l.Status != models.LoanStatusActive {
+			return loan.ErrNotActive
+		}
+		installments, err := uc.loanRepo.GetInstallments(ctx, loanID)
+		if err != nil {
+			return err
+		}
+		charges, err := uc.loanRepo.GetCharges(ctx, loanID)
+		if err != nil {
+			return err
+		}
 
-	fmt.Println(dueUnpaid)
+		var instIDs, chargeIDs []int
+		var total float64
+		split := models.PaymentSplit{Final: true}
+		for _, inst := range installments {
+			if !inst.Paid {
+				instIDs = append(instIDs, inst.ID)
+				total += inst.Amount
+				split.Principal += inst.PrincipalAmount
+				split.Interest += inst.InterestAmount
+				split.Fees += inst.FeeAmount
+			}
+		}
+		for _, charge := range charges {
+			if !charge.Paid {
+				chargeIDs = append(chargeIDs, charge.ID)
+				total += charge.Amount
+				split.Fees += charge.Amount
+			}
+		}
+		if len(instIDs) == 0 && len(chargeIDs) == 0 {
+			return payment.ErrNothingToSettle
+		}
 
-	if len(dueUnpaid) > 0 {
-		if amount != totalDue {
-			return errors.New("payment amount must cover all overdue installments")
+		settlement = &models.Payment{
+			LoanID:         loanID,
+			Amount:         math.Round(total*100) / 100,
+			IdempotencyKey: fmt.Sprintf("settlement:loan:%d", loanID),
+			PaymentType:    models.PaymentTypeSettlement,
 		}
-	} else {
-		// No installments are due – cannot pay ahead
-		return errors.New("no installments are due for payment")
+		if err := uc.paymentRepo.Create(ctx, settlement, instIDs, chargeIDs); err != nil {
+			return err
+		}
+		return uc.accountingUC.RecordPayment(ctx, settlement, split)
+	})
+	if err != nil {
+		return nil, err
 	}
+	return settlement, nil
+}
 
-	// Prepare IDs of all overdue installments to mark as paid
-	var instIDs []int
-	for _, inst := range dueUnpaid {
-		instIDs = append(instIDs, inst.ID)
+// backdated returns the business date override of ctx, or zero when the
+// payment is made today, so payments posted as of a past date carry that date.
+func backdated(ctx context.Context) time.Time {
+	date, _ := clock.AsOfDate(ctx)
+	return date
+}
+
+func (uc *paymentUseCase) ListPayments(ctx context.Context, loanID int) ([]models.Payment, error) {
+	if _, err := uc.loanRepo.GetByID(ctx, loanID); err != nil {
+		return nil, err
 	}
+	return uc.paymentRepo.ListByLoanID(ctx, loanID)
+}
 
-	// Create payment record and mark installments as paid
-	payment := &models.Payment{
-		LoanID:         loanID,
-		Amount:         amount,
-		IdempotencyKey: idempotencyKey,
+// ReversePayment implements [payment.PaymentUsecase]. A reversed recovery
+// reduces the amount recovered on the written-off loan.
+func (uc *paymentUseCase) ReversePayment(ctx context.Context, paymentID int, reason string) (*models.Payment, error) {
+	if reason == "" {
+		return nil, payment.ErrReversalReasonRequired
+	}
+	p, err := uc.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, payment.ErrNotFound.Wrap(err)
+		}
+		return nil, err
+	}
+	if p.ReversedAt != nil {
+		return nil, payment.ErrAlreadyReversed
 	}
-	err = uc.paymentRepo.Create(ctx, payment, instIDs)
+	l, err := uc.loanRepo.GetByID(ctx, p.LoanID)
 	if err != nil {
+		return nil, err
+	}
+
+	switch p.PaymentType {
+	case models.PaymentTypeSettlement:
+		return nil, payment.ErrNotReversible.Withf("settlement payments cannot be reversed")
+	case models.PaymentTypeRecovery:
+		if l.Status != models.LoanStatusWrittenOff {
+			return nil, payment.ErrNotReversible.Withf("loan is no longer written off")
+		}
+	default:
+		if l.Status != models.LoanStatusActive {
+			return nil, loan.ErrNotActive
+		}
+		versions, err := uc.paymentRepo.ScheduleVersions(ctx, p.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range versions {
+			if v != l.ScheduleVersion {
+				return nil, payment.ErrNotReversible.Withf("payment settled installments of a schedule replaced by a restructure")
+			}
+		}
+	}
+
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.paymentRepo.Reverse(ctx, p.ID, reason); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return payment.ErrAlreadyReversed
+			}
+			return err
+		}
+		if p.PaymentType == models.PaymentTypeRecovery {
+			if err := uc.loanRepo.AddRecovery(ctx, l.ID, -p.Amount); err != nil {
+				return err
+			}
+		}
+		_, err := uc.accountingUC.ReversePayment(ctx, p, reason)
 		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return uc.paymentRepo.GetByID(ctx, p.ID)
+}
 
-	// Store idempotency key in Redis
-	if idempotencyKey != "" {
-		_ = uc.idempStore.Store(ctx, idempotencyKey, payment.ID, 24*time.Hour)
+// makeRecovery collects money on a written-off loan. Recoveries settle no
+// installments; they reduce the written-off balance and are booked as
+// recovery income. MakePayment calls it with the loan locked, and the
+// balance is checked again as the recovery is added.
+func (uc *paymentUseCase) makeRecovery(ctx context.Context, loan *models.Loan, amount float64, idempotencyKey string) (*models.Payment, error) {
+	remaining := loan.WrittenOffAmount - loan.RecoveredAmount
+	if amount > remaining+0.005 {
+		return nil, payment.ErrExceedsWrittenOffBalance
+	}
+
+	recovery := &models.Payment{
+		LoanID:         loan.ID,
+		Amount:         amount,
+		IdempotencyKey: idempotencyKey,
+		PaymentType:    models.PaymentTypeRecovery,
+		PaymentDate:    backdated(ctx),
+	}
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.paymentRepo.Create(ctx, recovery, nil, nil); err != nil {
+			return err
+		}
+		if err := uc.loanRepo.AddRecovery(ctx, loan.ID, amount); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return payment.ErrExceedsWrittenOffBalance
+			}
+			return err
+		}
+		return uc.accountingUC.RecordRecovery(ctx, recovery)
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return recovery, nil
 }
@@ -0,0 +1,9 @@
+package product
+
+import "github.com/evrintobing17/loan-billing-system/pkg/apperror"
+
+var (
+	ErrCodeTaken        = apperror.New(apperror.Conflict, "product_code_taken", "product code is already in use")
+	ErrFeeOutOfRange    = apperror.New(apperror.Unprocessable, "fee_out_of_range", "percentage fee must be at most 100, and below 100 when deducted")
+	ErrInvalidTreatment = apperror.New(apperror.Invalid, "invalid_fee_treatment", "fee treatment does not fit the fee type")
+)
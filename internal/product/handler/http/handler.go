@@ -0,0 +1,81 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/evrintobing17/loan-billing-system/internal/product"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+type ProductHandler struct {
+	productUC product.ProductUsecase
+}
+
+func NewProductHandler(uc product.ProductUsecase) *ProductHandler {
+	return &ProductHandler{productUC: uc}
+}
+
+// CreateProduct godoc
+// @Summary Create a loan product
+// @Description Create a loan product and its fee schedule (admin only).
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Param request body models.CreateProductRequest true "Product details"
+// @Success 201 {object} models.LoanProduct
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /products [post]
+func (h *ProductHandler) CreateProduct(c *gin.Context) {
+	var req models.CreateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+
+	product, err := h.productUC.CreateProduct(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, product)
+}
+
+// ListProducts godoc
+// @Summary List loan products
+// @Tags products
+// @Produce json
+// @Success 200 {array} models.LoanProduct
+// @Failure 500 {object} models.Problem
+// @Router /products [get]
+func (h *ProductHandler) ListProducts(c *gin.Context) {
+	products, err := h.productUC.ListProducts(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, products)
+}
+
+// GetProduct godoc
+// @Summary Get a loan product by code
+// @Tags products
+// @Produce json
+// @Param code path string true "Product code"
+// @Success 200 {object} models.LoanProduct
+// @Failure 404 {object} models.Problem
+// @Router /products/{code} [get]
+func (h *ProductHandler) GetProduct(c *gin.Context) {
+	product, err := h.productUC.GetProduct(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		c.Error(apperror.Default(err, apperror.NotFound))
+		return
+	}
+	c.JSON(http.StatusOK, product)
+}
@@ -0,0 +1,14 @@
+package product
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type ProductRepository interface {
+	Create(ctx context.Context, product *models.LoanProduct) error
+	GetByCode(ctx context.Context, code string) (*models.LoanProduct, error)
+	GetByID(ctx context.Context, id int) (*models.LoanProduct, error)
+	List(ctx context.Context) ([]models.LoanProduct, error)
+}
@@ -0,0 +1,13 @@
+package product
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type ProductUsecase interface {
+	CreateProduct(ctx context.Context, req models.CreateProductRequest) (*models.LoanProduct, error)
+	GetProduct(ctx context.Context, code string) (*models.LoanProduct, error)
+	ListProducts(ctx context.Context) ([]models.LoanProduct, error)
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evrintobing17/loan-billing-system/internal/product"
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type productRepository struct {
+	DB *sql.DB
+}
+
+func NewProductRepository(DB *sql.DB) product.ProductRepository {
+	return &productRepository{
+		DB: DB,
+	}
+}
+
+// Create inserts the product together with its fees.
+func (p *productRepository) Create(ctx context.Context, prod *models.LoanProduct) error {
+	tx, err := p.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO loan_products (code, name, max_borrower_outstanding, max_borrower_active_loans, block_delinquent)
+         VALUES ($1, $2, $3, $4, $5)
+         ON CONFLICT (code) DO NOTHING
+         RETURNING id, created_at`,
+		prod.Code, prod.Name, prod.Limits.MaxOutstanding, prod.Limits.MaxActiveLoans,
+		prod.Limits.BlockDelinquent).Scan(&prod.ID, &prod.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return product.ErrCodeTaken
+	}
+	if err != nil {
+		return err
+	}
+
+	for i := range prod.Fees {
+		fee := &prod.Fees[i]
+		fee.ProductID = prod.ID
+		err = tx.QueryRowContext(ctx,
+			`INSERT INTO product_fees (product_id, fee_type, calculation, amount, treatment, frequency_weeks)
+             VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+			fee.ProductID, fee.FeeType, fee.Calculation, fee.Amount, fee.Treatment, fee.FrequencyWeeks).Scan(&fee.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (p *productRepository) GetByCode(ctx context.Context, code string) (*models.LoanProduct, error) {
+	return p.getOne(ctx, "code", code)
+}
+
+func (p *productRepository) GetByID(ctx context.Context, id int) (*models.LoanProduct, error) {
+	return p.getOne(ctx, "id", id)
+}
+
+// getOne loads a single product with its fees by the given unique column.
+func (p *productRepository) getOne(ctx context.Context, column string, value any) (*models.LoanProduct, error) {
+	var product models.LoanProduct
+	err := scanProduct(p.DB.QueryRowContext(ctx,
+		`SELECT `+productColumns+` FROM loan_products WHERE `+column+` = $1`, value), &product)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query product by %s: %w", column, err)
+	}
+
+	product.Fees, err = p.getFees(ctx, product.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (p *productRepository) List(ctx context.Context) ([]models.LoanProduct, error) {
+	rows, err := p.DB.QueryContext(ctx, `SELECT `+productColumns+` FROM loan_products ORDER BY code`)
+	if err != nil {
+		return nil, fmt.Errorf("query products: %w", err)
+	}
+	defer rows.Close()
+
+	var products []models.LoanProduct
+	for rows.Next() {
+		var product models.LoanProduct
+		if err := scanProduct(rows, &product); err != nil {
+			return nil, fmt.Errorf("scan product: %w", err)
+		}
+		products = append(products, product)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	for i := range products {
+		products[i].Fees, err = p.getFees(ctx, products[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return products, nil
+}
+
+const productColumns = `id, code, name, max_borrower_outstanding, max_borrower_active_loans, block_delinquent, created_at`
+
+func scanProduct(row interface{ Scan(...any) error }, product *models.LoanProduct) error {
+	return row.Scan(&product.ID, &product.Code, &product.Name, &product.Limits.MaxOutstanding,
+		&product.Limits.MaxActiveLoans, &product.Limits.BlockDelinquent, &product.CreatedAt)
+}
+
+func (p *productRepository) getFees(ctx context.Context, productID int) ([]models.ProductFee, error) {
+	rows, err := p.DB.QueryContext(ctx,
+		`SELECT id, product_id, fee_type, calculation, amount, treatment, frequency_weeks
+         FROM product_fees WHERE product_id = $1 ORDER BY id`, productID)
+	if err != nil {
+		return nil, fmt.Errorf("query product fees: %w", err)
+	}
+	defer rows.Close()
+
+	var fees []models.ProductFee
+	for rows.Next() {
+		var fee models.ProductFee
+		err := rows.Scan(&fee.ID, &fee.ProductID, &fee.FeeType, &fee.Calculation,
+			&fee.Amount, &fee.Treatment, &fee.FrequencyWeeks)
+		if err != nil {
+			return nil, fmt.Errorf("scan product fee: %w", err)
+		}
+		fees = append(fees, fee)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return fees, nil
+}
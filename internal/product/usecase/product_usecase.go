@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/internal/product"
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type productUseCase struct {
+	productRepo product.ProductRepository
+}
+
+func NewProductUseCase(productRepo product.ProductRepository) product.ProductUsecase {
+	return &productUseCase{productRepo: productRepo}
+}
+
+func (uc *productUseCase) CreateProduct(ctx context.Context, req models.CreateProductRequest) (*models.LoanProduct, error) {
+	product := &models.LoanProduct{
+		Code:   req.Code,
+		Name:   req.Name,
+		Limits: req.Limits,
+	}
+	for _, f := range req.Fees {
+		fee := models.ProductFee{
+			FeeType:        f.FeeType,
+			Calculation:    f.Calculation,
+			Amount:         f.Amount,
+			Treatment:      f.Treatment,
+			FrequencyWeeks: f.FrequencyWeeks,
+		}
+		if err := validateFee(&fee); err != nil {
+			return nil, err
+		}
+		product.Fees = append(product.Fees, fee)
+	}
+
+	if err := uc.productRepo.Create(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func (uc *productUseCase) GetProduct(ctx context.Context, code string) (*models.LoanProduct, error) {
+	return uc.productRepo.GetByCode(ctx, code)
+}
+
+func (uc *productUseCase) ListProducts(ctx context.Context) ([]models.LoanProduct, error) {
+	return uc.productRepo.List(ctx)
+}
+
+// validateFee checks that the treatment fits the fee type and the percentage
+// is in range, and fills in defaults. Flat fees are checked against the
+// principal when a loan is booked.
+func validateFee(fee *models.ProductFee) error {
+	if fee.Calculation == models.FeeCalcPercent {
+		if fee.Amount > 100 {
+			return product.ErrFeeOutOfRange.Withf("percentage fee of %.2f is above 100", fee.Amount)
+		}
+		if fee.Treatment == models.FeeTreatmentDeducted && fee.Amount >= 100 {
+			return product.ErrFeeOutOfRange.Withf("a deducted percentage fee must be below 100")
+		}
+	}
+	switch fee.FeeType {
+	case models.FeeTypeOrigination:
+		if fee.Treatment != models.FeeTreatmentDeducted && fee.Treatment != models.FeeTreatmentFinanced {
+			return product.ErrInvalidTreatment.Withf("origination fee treatment must be %q or %q", models.FeeTreatmentDeducted, models.FeeTreatmentFinanced)
+		}
+		fee.FrequencyWeeks = 0
+	case models.FeeTypeServicing:
+		if fee.Treatment != models.FeeTreatmentInstallment && fee.Treatment != models.FeeTreatmentSeparate {
+			return product.ErrInvalidTreatment.Withf("servicing fee treatment must be %q or %q", models.FeeTreatmentInstallment, models.FeeTreatmentSeparate)
+		}
+		if fee.FrequencyWeeks == 0 {
+			fee.FrequencyWeeks = 1
+		}
+	}
+	return nil
+}
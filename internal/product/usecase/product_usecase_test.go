@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evrintobing17/loan-billing-system/internal/product"
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+func TestValidateFee(t *testing.T) {
+	tests := []struct {
+		name          string
+		fee           models.ProductFee
+		wantErr       error
+		wantFrequency int
+	}{
+		{
+			name: "financed fee of 100 percent",
+			fee:  models.ProductFee{FeeType: models.FeeTypeOrigination, Calculation: models.FeeCalcPercent, Amount: 100, Treatment: models.FeeTreatmentFinanced},
+		},
+		{
+			name:    "percentage above 100",
+			fee:     models.ProductFee{FeeType: models.FeeTypeOrigination, Calculation: models.FeeCalcPercent, Amount: 100.01, Treatment: models.FeeTreatmentFinanced},
+			wantErr: product.ErrFeeOutOfRange,
+		},
+		{
+			name: "deducted fee just below 100 percent",
+			fee:  models.ProductFee{FeeType: models.FeeTypeOrigination, Calculation: models.FeeCalcPercent, Amount: 99.99, Treatment: models.FeeTreatmentDeducted},
+		},
+		{
+			name:    "deducted fee of 100 percent",
+			fee:     models.ProductFee{FeeType: models.FeeTypeOrigination, Calculation: models.FeeCalcPercent, Amount: 100, Treatment: models.FeeTreatmentDeducted},
+			wantErr: product.ErrFeeOutOfRange,
+		},
+		{
+			name: "flat fee above 100",
+			fee:  models.ProductFee{FeeType: models.FeeTypeOrigination, Calculation: models.FeeCalcFlat, Amount: 25000, Treatment: models.FeeTreatmentDeducted},
+		},
+		{
+			name:    "origination fee added to installments",
+			fee:     models.ProductFee{FeeType: models.FeeTypeOrigination, Calculation: models.FeeCalcFlat, Amount: 10, Treatment: models.FeeTreatmentInstallment},
+			wantErr: product.ErrInvalidTreatment,
+		},
+		{
+			name:    "servicing fee deducted",
+			fee:     models.ProductFee{FeeType: models.FeeTypeServicing, Calculation: models.FeeCalcFlat, Amount: 10, Treatment: models.FeeTreatmentDeducted},
+			wantErr: product.ErrInvalidTreatment,
+		},
+		{
+			name:          "servicing fee defaults to weekly",
+			fee:           models.ProductFee{FeeType: models.FeeTypeServicing, Calculation: models.FeeCalcFlat, Amount: 10, Treatment: models.FeeTreatmentSeparate},
+			wantFrequency: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee := tt.fee
+			err := validateFee(&fee)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateFee() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && fee.FrequencyWeeks != tt.wantFrequency {
+				t.Errorf("FrequencyWeeks = %d, want %d", fee.FrequencyWeeks, tt.wantFrequency)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package statement
+
+import "github.com/evrintobing17/loan-billing-system/pkg/apperror"
+
+var (
+	ErrInvalidStatement  = apperror.New(apperror.Invalid, "invalid_statement", "file is not a readable bank statement")
+	ErrStatementNotFound = apperror.New(apperror.NotFound, "statement_not_found", "bank statement not found")
+	ErrEntryNotFound     = apperror.New(apperror.NotFound, "statement_entry_not_found", "statement entry not found")
+	ErrEntryNotQueued    = apperror.New(apperror.Conflict, "entry_not_queued", "statement entry is not awaiting reconciliation")
+	ErrCurrencyMismatch  = apperror.New(apperror.Unprocessable, "currency_mismatch", "money received is not in the loan currency")
+)
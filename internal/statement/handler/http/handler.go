@@ -0,0 +1,195 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/evrintobing17/loan-billing-system/internal/statement"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/evrintobing17/loan-billing-system/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type StatementHandler struct {
+	statementUC statement.StatementUsecase
+}
+
+func NewStatementHandler(uc statement.StatementUsecase) *StatementHandler {
+	return &StatementHandler{statementUC: uc}
+}
+
+// ImportStatement godoc
+// @Summary Import a bank statement (admin)
+// @Description Upload a CSV, MT940 or camt.053 statement. Credits whose reference names a loan are posted as payments as of their booking date; the rest go to the reconciliation queue. Transactions already imported are skipped.
+// @Tags statements
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Bank statement"
+// @Param format formData string false "csv, mt940 or camt053; detected when empty"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param X-User-ID header string true "Requesting user"
+// @Success 201 {object} models.BankStatement
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /statements [post]
+func (h *StatementHandler) ImportStatement(c *gin.Context) {
+	actor := middleware.Actor(c)
+	if actor == "" {
+		c.Error(middleware.ErrActorRequired)
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.Error(apperror.Invalidf("file is required"))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer file.Close()
+
+	s, err := h.statementUC.Import(c.Request.Context(), file, c.PostForm("format"), header.Filename, actor)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, s)
+}
+
+// GetStatement godoc
+// @Summary Get a bank statement (admin)
+// @Description Import counts and the outcome of every entry.
+// @Tags statements
+// @Produce json
+// @Param id path int true "Statement ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.BankStatement
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /statements/{id} [get]
+func (h *StatementHandler) GetStatement(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid statement id"))
+		return
+	}
+
+	s, err := h.statementUC.GetStatement(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+// ListQueue godoc
+// @Summary List the reconciliation queue (admin)
+// @Description Statement credits that could not be matched to a loan or whose payment failed, oldest first.
+// @Tags statements
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {array} models.StatementEntry
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /reconciliation/queue [get]
+func (h *StatementHandler) ListQueue(c *gin.Context) {
+	entries, err := h.statementUC.ListQueue(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// ResolveEntry godoc
+// @Summary Post a queued entry to a loan (admin)
+// @Description Makes the payment on the given loan as of the booking date and takes the entry out of the queue.
+// @Tags statements
+// @Accept json
+// @Produce json
+// @Param id path int true "Statement entry ID"
+// @Param request body models.ResolveEntryRequest true "Loan to pay"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param X-User-ID header string true "Requesting user"
+// @Success 200 {object} models.StatementEntry
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /reconciliation/entries/{id}/resolve [post]
+func (h *StatementHandler) ResolveEntry(c *gin.Context) {
+	actor := middleware.Actor(c)
+	if actor == "" {
+		c.Error(middleware.ErrActorRequired)
+		return
+	}
+	entryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid statement entry id"))
+		return
+	}
+
+	var req models.ResolveEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+
+	entry, err := h.statementUC.Resolve(c.Request.Context(), entryID, req.LoanID, actor)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// DismissEntry godoc
+// @Summary Dismiss a queued entry (admin)
+// @Description Takes the entry out of the queue without posting a payment, e.g. when the money was returned to the sender.
+// @Tags statements
+// @Accept json
+// @Produce json
+// @Param id path int true "Statement entry ID"
+// @Param request body models.DismissEntryRequest true "Dismissal reason"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param X-User-ID header string true "Requesting user"
+// @Success 200 {object} models.StatementEntry
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /reconciliation/entries/{id}/dismiss [post]
+func (h *StatementHandler) DismissEntry(c *gin.Context) {
+	actor := middleware.Actor(c)
+	if actor == "" {
+		c.Error(middleware.ErrActorRequired)
+		return
+	}
+	entryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid statement entry id"))
+		return
+	}
+
+	var req models.DismissEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+
+	entry, err := h.statementUC.Dismiss(c.Request.Context(), entryID, req.Reason, actor)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
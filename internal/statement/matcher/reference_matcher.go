@@ -0,0 +1,50 @@
+package matcher
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/evrintobing17/loan-billing-system/internal/statement"
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// loanReference matches a loan reference such as "LOAN-42", "loan 42" or
+// "Loan#42" anywhere in the remittance text.
+var loanReference = regexp.MustCompile(`(?i)\bLOAN[\s#:-]*(\d{1,10})\b`)
+
+type referenceMatcher struct{}
+
+// NewReferenceMatcher matches credits whose remittance information carries a
+// loan reference. A text naming more than one loan is ambiguous.
+func NewReferenceMatcher() statement.LoanMatcher {
+	return referenceMatcher{}
+}
+
+func (referenceMatcher) Match(_ context.Context, entry *models.StatementEntry) (int, bool, error) {
+	var found []int
+	for _, m := range loanReference.FindAllStringSubmatch(entry.Reference, -1) {
+		id, err := strconv.Atoi(m[1])
+		if err != nil || id == 0 {
+			continue
+		}
+		if !slices.Contains(found, id) {
+			found = append(found, id)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return 0, false, nil
+	case 1:
+		return found[0], true, nil
+	default:
+		refs := make([]string, len(found))
+		for i, id := range found {
+			refs[i] = fmt.Sprintf("LOAN-%d", id)
+		}
+		return 0, false, fmt.Errorf("reference names several loans: %s", strings.Join(refs, ", "))
+	}
+}
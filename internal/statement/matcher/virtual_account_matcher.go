@@ -0,0 +1,65 @@
+package matcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/evrintobing17/loan-billing-system/internal/loan"
+	"github.com/evrintobing17/loan-billing-system/internal/statement"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/virtualaccount"
+)
+
+// accountNumber matches a run of digits that may be grouped with spaces or
+// dashes, as virtual account numbers are often written in transfer notes.
+// Plain digit runs are tried on their own too, in case grouping joined a
+// number to its neighbours.
+var (
+	accountNumber = regexp.MustCompile(`\d[\d -]{8,}\d`)
+	digitRun      = regexp.MustCompile(`\d{10,}`)
+)
+
+type virtualAccountMatcher struct {
+	loanRepo loan.LoanRepository
+}
+
+// NewVirtualAccountMatcher matches credits paid into a loan's virtual
+// account, or whose remittance information quotes one. Numbers with a wrong
+// check digit are not looked up.
+func NewVirtualAccountMatcher(lr loan.LoanRepository) statement.LoanMatcher {
+	return &virtualAccountMatcher{loanRepo: lr}
+}
+
+func (m *virtualAccountMatcher) Match(ctx context.Context, entry *models.StatementEntry) (int, bool, error) {
+	candidates := append([]string{entry.Account}, accountNumber.FindAllString(entry.Reference, -1)...)
+	candidates = append(candidates, digitRun.FindAllString(entry.Reference, -1)...)
+
+	var found []int
+	for _, c := range candidates {
+		number := virtualaccount.Normalize(c)
+		if !virtualaccount.Valid(number) {
+			continue
+		}
+		l, err := m.loanRepo.GetByVirtualAccount(ctx, number)
+		if errors.Is(err, loan.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return 0, false, err
+		}
+		if !slices.Contains(found, l.ID) {
+			found = append(found, l.ID)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return 0, false, nil
+	case 1:
+		return found[0], true, nil
+	default:
+		return 0, false, fmt.Errorf("entry names the virtual accounts of several loans: %v", found)
+	}
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evrintobing17/loan-billing-system/internal/statement"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+	"github.com/lib/pq"
+)
+
+type statementRepository struct {
+	DB *sql.DB
+}
+
+func NewStatementRepository(DB *sql.DB) statement.StatementRepository {
+	return &statementRepository{
+		DB: DB,
+	}
+}
+
+// CreateStatement implements [statement.StatementRepository].
+func (r *statementRepository) CreateStatement(ctx context.Context, s *models.BankStatement) error {
+	query := `INSERT INTO bank_statements (format, account, file_name, imported_by)
+              VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4) RETURNING id, imported_at`
+	return postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, s.Format, s.Account, s.FileName, s.ImportedBy).
+		Scan(&s.ID, &s.ImportedAt)
+}
+
+// UpdateCounts implements [statement.StatementRepository].
+func (r *statementRepository) UpdateCounts(ctx context.Context, s *models.BankStatement) error {
+	query := `UPDATE bank_statements
+              SET total_entries = $2, posted = $3, queued = $4, duplicates = $5, ignored = $6
+              WHERE id = $1`
+	_, err := postgres.Conn(ctx, r.DB).ExecContext(ctx, query, s.ID, s.TotalEntries, s.Posted, s.Queued, s.Duplicates, s.Ignored)
+	if err != nil {
+		return fmt.Errorf("update statement counts: %w", err)
+	}
+	return nil
+}
+
+func (r *statementRepository) GetStatement(ctx context.Context, id int) (*models.BankStatement, error) {
+	var s models.BankStatement
+	var account, fileName sql.NullString
+	query := `SELECT id, format, account, file_name, total_entries, posted, queued, duplicates, ignored, imported_by, imported_at
+              FROM bank_statements WHERE id = $1`
+	err := postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, id).Scan(
+		&s.ID,
+		&s.Format,
+		&account,
+		&fileName,
+		&s.TotalEntries,
+		&s.Posted,
+		&s.Queued,
+		&s.Duplicates,
+		&s.Ignored,
+		&s.ImportedBy,
+		&s.ImportedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query statement: %w", err)
+	}
+	s.Account, s.FileName = account.String, fileName.String
+
+	s.Entries, err = r.queryEntries(ctx, `WHERE statement_id = $1 ORDER BY id`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+const entryColumns = `id, statement_id, statement_account, transaction_id, booking_date, amount, credit, currency, reference, account,
+                      status, loan_id, payment_id, error, resolved_by, resolved_at`
+
+func scanEntry(row interface{ Scan(...any) error }, e *models.StatementEntry) error {
+	var currency, reference, account, entryErr, resolvedBy sql.NullString
+	err := row.Scan(
+		&e.ID,
+		&e.StatementID,
+		&e.StatementAccount,
+		&e.TransactionID,
+		&e.BookingDate,
+		&e.Amount,
+		&e.Credit,
+		&currency,
+		&reference,
+		&account,
+		&e.Status,
+		&e.LoanID,
+		&e.PaymentID,
+		&entryErr,
+		&resolvedBy,
+		&e.ResolvedAt,
+	)
+	e.Currency, e.Reference, e.Account = currency.String, reference.String, account.String
+	e.Error, e.ResolvedBy = entryErr.String, resolvedBy.String
+	return err
+}
+
+// CreateEntry implements [statement.StatementRepository].
+func (r *statementRepository) CreateEntry(ctx context.Context, e *models.StatementEntry) error {
+	query := `INSERT INTO statement_entries (statement_id, statement_account, transaction_id, booking_date, amount, credit,
+                                               currency, reference, account, status)
+              VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), NULLIF($9, ''), $10)
+              ON CONFLICT (statement_account, transaction_id) DO NOTHING
+              RETURNING id`
+	return postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, e.StatementID, e.StatementAccount, e.TransactionID,
+		e.BookingDate, e.Amount, e.Credit, e.Currency, e.Reference, e.Account, e.Status).Scan(&e.ID)
+}
+
+func (r *statementRepository) GetEntry(ctx context.Context, id int) (*models.StatementEntry, error) {
+	var e models.StatementEntry
+	query := `SELECT ` + entryColumns + ` FROM statement_entries WHERE id = $1`
+	err := scanEntry(postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, id), &e)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query statement entry: %w", err)
+	}
+	return &e, nil
+}
+
+// UpdateEntry implements [statement.StatementRepository].
+func (r *statementRepository) UpdateEntry(ctx context.Context, e *models.StatementEntry, fromStatuses ...string) error {
+	query := `UPDATE statement_entries
+              SET status = $2, loan_id = $3, payment_id = $4, error = NULLIF($5, ''), resolved_by = NULLIF($6, ''), resolved_at = $7
+              WHERE id = $1 AND status = ANY($8)`
+	res, err := postgres.Conn(ctx, r.DB).ExecContext(ctx, query, e.ID, e.Status, e.LoanID, e.PaymentID, e.Error,
+		e.ResolvedBy, e.ResolvedAt, pq.Array(fromStatuses))
+	if err != nil {
+		return fmt.Errorf("update statement entry: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *statementRepository) ListQueue(ctx context.Context) ([]models.StatementEntry, error) {
+	return r.queryEntries(ctx, `WHERE status = ANY($1) ORDER BY booking_date, id`,
+		pq.Array([]string{models.StatementEntryPending, models.StatementEntryUnmatched, models.StatementEntryFailed}))
+}
+
+func (r *statementRepository) queryEntries(ctx context.Context, where string, args ...any) ([]models.StatementEntry, error) {
+	rows, err := postgres.Conn(ctx, r.DB).QueryContext(ctx, `SELECT `+entryColumns+` FROM statement_entries `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query statement entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.StatementEntry
+	for rows.Next() {
+		var e models.StatementEntry
+		if err := scanEntry(rows, &e); err != nil {
+			return nil, fmt.Errorf("scan statement entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return entries, nil
+}
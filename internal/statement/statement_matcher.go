@@ -0,0 +1,14 @@
+package statement
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// LoanMatcher finds the loan a bank credit pays. ok is false when the matcher
+// finds nothing, so the next matcher can try; an error means the entry is
+// ambiguous and needs a person.
+type LoanMatcher interface {
+	Match(ctx context.Context, entry *models.StatementEntry) (loanID int, ok bool, err error)
+}
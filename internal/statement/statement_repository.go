@@ -0,0 +1,24 @@
+package statement
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type StatementRepository interface {
+	CreateStatement(ctx context.Context, statement *models.BankStatement) error
+	// UpdateCounts stores the import counts of a statement.
+	UpdateCounts(ctx context.Context, statement *models.BankStatement) error
+	// GetStatement returns the statement with its entries.
+	GetStatement(ctx context.Context, id int) (*models.BankStatement, error)
+	// CreateEntry stores a new entry. It returns sql.ErrNoRows when the
+	// transaction was imported before on the same statement account.
+	CreateEntry(ctx context.Context, entry *models.StatementEntry) error
+	GetEntry(ctx context.Context, id int) (*models.StatementEntry, error)
+	// UpdateEntry stores the entry's reconciliation outcome. It returns
+	// sql.ErrNoRows unless the entry is still in one of the given statuses.
+	UpdateEntry(ctx context.Context, entry *models.StatementEntry, fromStatuses ...string) error
+	// ListQueue returns the entries awaiting reconciliation, oldest first.
+	ListQueue(ctx context.Context) ([]models.StatementEntry, error)
+}
@@ -0,0 +1,23 @@
+package statement
+
+import (
+	"context"
+	"io"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// StatementUsecase imports bank statements and posts the credits it can match
+// as loan payments. The rest waits in the reconciliation queue.
+type StatementUsecase interface {
+	// Import reads a csv, mt940 or camt053 statement; an empty format is
+	// detected from the content.
+	Import(ctx context.Context, file io.Reader, format, fileName, importedBy string) (*models.BankStatement, error)
+	GetStatement(ctx context.Context, id int) (*models.BankStatement, error)
+	ListQueue(ctx context.Context) ([]models.StatementEntry, error)
+	// Resolve posts a queued entry as a payment on the given loan.
+	Resolve(ctx context.Context, entryID, loanID int, resolvedBy string) (*models.StatementEntry, error)
+	// Dismiss takes an entry out of the queue without posting it, e.g. when
+	// the money is returned to the sender.
+	Dismiss(ctx context.Context, entryID int, reason, resolvedBy string) (*models.StatementEntry, error)
+}
@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/evrintobing17/loan-billing-system/internal/loan"
+	"github.com/evrintobing17/loan-billing-system/internal/payment"
+	"github.com/evrintobing17/loan-billing-system/internal/statement"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/bankstatement"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+)
+
+var queueStatuses = []string{models.StatementEntryPending, models.StatementEntryUnmatched, models.StatementEntryFailed}
+
+type statementUseCase struct {
+	statementRepo statement.StatementRepository
+	loanRepo      loan.LoanRepository
+	paymentUC     payment.PaymentUsecase
+	matchers      []statement.LoanMatcher
+	currency      string
+	clock         clock.Clock
+}
+
+// NewStatementUseCase builds the statement import. Matchers are tried in
+// order until one finds the loan a credit pays. Credits in a currency other
+// than the loans' currency are queued rather than posted.
+func NewStatementUseCase(sr statement.StatementRepository, lr loan.LoanRepository, puc payment.PaymentUsecase,
+	currency string, clk clock.Clock, matchers ...statement.LoanMatcher) statement.StatementUsecase {
+	return &statementUseCase{
+		statementRepo: sr,
+		loanRepo:      lr,
+		paymentUC:     puc,
+		matchers:      matchers,
+		currency:      currency,
+		clock:         clk,
+	}
+}
+
+// Import implements [statement.StatementUsecase]. Every entry is stored
+// before it is posted, so a transaction seen in an earlier statement of the
+// same account is skipped as a duplicate.
+func (uc *statementUseCase) Import(ctx context.Context, file io.Reader, format, fileName, importedBy string) (*models.BankStatement, error) {
+	parsed, err := bankstatement.Parse(format, file)
+	if err != nil {
+		if errors.Is(err, bankstatement.ErrUnknownFormat) {
+			return nil, statement.ErrInvalidStatement.Withf("format must be csv, mt940 or camt053")
+		}
+		return nil, statement.ErrInvalidStatement.Withf("%s", err.Error())
+	}
+
+	s := &models.BankStatement{
+		Format:       parsed.Format,
+		Account:      parsed.Account,
+		FileName:     fileName,
+		TotalEntries: len(parsed.Transactions),
+		ImportedBy:   importedBy,
+	}
+	if err := uc.statementRepo.CreateStatement(ctx, s); err != nil {
+		return nil, err
+	}
+
+	for _, txn := range parsed.Transactions {
+		entry := &models.StatementEntry{
+			StatementID:      s.ID,
+			StatementAccount: s.Account,
+			TransactionID:    txn.ID,
+			BookingDate:      txn.BookingDate,
+			Amount:           math.Round(txn.Amount*100) / 100,
+			Credit:           txn.Credit,
+			Currency:         txn.Currency,
+			Reference:        txn.Reference,
+			Account:          txn.Account,
+			Status:           models.StatementEntryPending,
+		}
+		if err := uc.statementRepo.CreateEntry(ctx, entry); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				s.Duplicates++
+				continue
+			}
+			return nil, err
+		}
+		if err := uc.reconcile(ctx, entry); err != nil {
+			return nil, err
+		}
+		switch entry.Status {
+		case models.StatementEntryPosted:
+			s.Posted++
+		case models.StatementEntryIgnored:
+			s.Ignored++
+		default:
+			s.Queued++
+		}
+	}
+
+	if err := uc.statementRepo.UpdateCounts(ctx, s); err != nil {
+		return nil, err
+	}
+	return uc.statementRepo.GetStatement(ctx, s.ID)
+}
+
+// reconcile matches a pending entry and posts it. Entries it cannot post are
+// left in the queue with the reason; only failing to store the outcome is an
+// error.
+func (uc *statementUseCase) reconcile(ctx context.Context, entry *models.StatementEntry) error {
+	switch {
+	case !entry.Credit:
+		entry.Status = models.StatementEntryIgnored
+	case !uc.inLoanCurrency(entry):
+		entry.Status = models.StatementEntryUnmatched
+		entry.Error = fmt.Sprintf("currency %s is not the loan currency %s", entry.Currency, uc.currency)
+	default:
+		loanID, err := uc.match(ctx, entry)
+		switch {
+		case err != nil:
+			entry.Status = models.StatementEntryUnmatched
+			entry.Error = err.Error()
+		case loanID == 0:
+			entry.Status = models.StatementEntryUnmatched
+			entry.Error = "no loan reference found"
+		default:
+			_ = uc.post(ctx, entry, loanID)
+		}
+	}
+	return uc.statementRepo.UpdateEntry(ctx, entry, models.StatementEntryPending)
+}
+
+// match runs the matchers in order and checks that the loan exists.
+func (uc *statementUseCase) match(ctx context.Context, entry *models.StatementEntry) (int, error) {
+	for _, m := range uc.matchers {
+		loanID, ok, err := m.Match(ctx, entry)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			continue
+		}
+		if _, err := uc.loanRepo.GetByID(ctx, loanID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return 0, fmt.Errorf("loan %d does not exist", loanID)
+			}
+			return 0, err
+		}
+		return loanID, nil
+	}
+	return 0, nil
+}
+
+// inLoanCurrency reports whether the entry is in the loans' currency. An
+// entry without a currency is taken to be.
+func (uc *statementUseCase) inLoanCurrency(entry *models.StatementEntry) bool {
+	return entry.Currency == "" || strings.EqualFold(entry.Currency, uc.currency)
+}
+
+// post makes the payment as of the booking date, keyed on the statement
+// account and bank transaction ID so a retried entry is never paid twice, and
+// records the outcome on the entry. The error is returned for callers that
+// report it.
+func (uc *statementUseCase) post(ctx context.Context, entry *models.StatementEntry, loanID int) error {
+	entry.LoanID = &loanID
+	key := "bank:" + entry.TransactionID
+	if entry.StatementAccount != "" {
+		key = "bank:" + entry.StatementAccount + ":" + entry.TransactionID
+	}
+	p, err := uc.paymentUC.MakePayment(clock.WithAsOfDate(ctx, entry.BookingDate), loanID, entry.Amount, key)
+	if err == nil {
+		entry.Status = models.StatementEntryPosted
+		entry.LoanID = &p.LoanID
+		entry.PaymentID = &p.ID
+		entry.Error = ""
+		return nil
+	}
+	entry.Status = models.StatementEntryFailed
+	entry.Error = err.Error()
+	return err
+}
+
+func (uc *statementUseCase) GetStatement(ctx context.Context, id int) (*models.BankStatement, error) {
+	s, err := uc.statementRepo.GetStatement(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, statement.ErrStatementNotFound.Wrap(err)
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+func (uc *statementUseCase) ListQueue(ctx context.Context) ([]models.StatementEntry, error) {
+	return uc.statementRepo.ListQueue(ctx)
+}
+
+// Resolve implements [statement.StatementUsecase]. A payment that still fails
+// leaves the entry in the queue and returns the error.
+func (uc *statementUseCase) Resolve(ctx context.Context, entryID, loanID int, resolvedBy string) (*models.StatementEntry, error) {
+	entry, err := uc.queuedEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.Credit {
+		return nil, statement.ErrEntryNotQueued.Withf("debits cannot be posted as payments")
+	}
+	if !uc.inLoanCurrency(entry) {
+		return nil, statement.ErrCurrencyMismatch.Withf("entry is in %s, loans are in %s; dismiss it instead", entry.Currency, uc.currency)
+	}
+	if _, err := uc.loanRepo.GetByID(ctx, loanID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.post(ctx, entry, loanID); err != nil {
+		if uerr := uc.statementRepo.UpdateEntry(ctx, entry, queueStatuses...); uerr != nil {
+			return nil, uerr
+		}
+		return nil, err
+	}
+	entry.Status = models.StatementEntryResolved
+	return entry, uc.close(ctx, entry, resolvedBy)
+}
+
+// Dismiss implements [statement.StatementUsecase].
+func (uc *statementUseCase) Dismiss(ctx context.Context, entryID int, reason, resolvedBy string) (*models.StatementEntry, error) {
+	entry, err := uc.queuedEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	entry.Status = models.StatementEntryDismissed
+	entry.Error = reason
+	return entry, uc.close(ctx, entry, resolvedBy)
+}
+
+func (uc *statementUseCase) queuedEntry(ctx context.Context, entryID int) (*models.StatementEntry, error) {
+	entry, err := uc.statementRepo.GetEntry(ctx, entryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, statement.ErrEntryNotFound.Wrap(err)
+		}
+		return nil, err
+	}
+	for _, status := range queueStatuses {
+		if entry.Status == status {
+			return entry, nil
+		}
+	}
+	return nil, statement.ErrEntryNotQueued
+}
+
+// close stamps who took the entry out of the queue. It fails with a conflict
+// when someone else closed it first.
+func (uc *statementUseCase) close(ctx context.Context, entry *models.StatementEntry, resolvedBy string) error {
+	now := uc.clock.Now()
+	entry.ResolvedBy = resolvedBy
+	entry.ResolvedAt = &now
+	if err := uc.statementRepo.UpdateEntry(ctx, entry, queueStatuses...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return statement.ErrEntryNotQueued
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package subscription
+
+import "github.com/evrintobing17/loan-billing-system/pkg/apperror"
+
+var (
+	ErrSubscriptionNotFound = apperror.New(apperror.NotFound, "subscription_not_found", "subscription not found")
+	ErrDeliveryNotFound     = apperror.New(apperror.NotFound, "webhook_delivery_not_found", "webhook delivery not found")
+	ErrDeliveryPending      = apperror.New(apperror.Conflict, "webhook_delivery_pending", "the delivery is still being attempted")
+	ErrUnknownEventType     = apperror.New(apperror.Invalid, "unknown_event_type", "unknown event type")
+	ErrInvalidURL           = apperror.New(apperror.Invalid, "invalid_subscription_url", "url must be https on a public host")
+)
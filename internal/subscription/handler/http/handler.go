@@ -0,0 +1,307 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/evrintobing17/loan-billing-system/internal/subscription"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/evrintobing17/loan-billing-system/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type SubscriptionHandler struct {
+	subscriptionUC subscription.SubscriptionUsecase
+}
+
+func NewSubscriptionHandler(uc subscription.SubscriptionUsecase) *SubscriptionHandler {
+	return &SubscriptionHandler{subscriptionUC: uc}
+}
+
+// Create godoc
+// @Summary Subscribe a partner endpoint to domain events (admin)
+// @Description Events of the given types, or of all types with "*", are posted to the URL as they are published. The URL must be https on a public host; redirects are not followed. The response carries the signing secret; it is not shown again.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param request body models.CreateSubscriptionRequest true "Subscription"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param X-User-ID header string true "Acting user"
+// @Success 201 {object} models.Subscription
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /subscriptions [post]
+func (h *SubscriptionHandler) Create(c *gin.Context) {
+	actor := middleware.Actor(c)
+	if actor == "" {
+		c.Error(middleware.ErrActorRequired)
+		return
+	}
+	var req models.CreateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+
+	s, err := h.subscriptionUC.Create(c.Request.Context(), req, actor)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, s)
+}
+
+// List godoc
+// @Summary List subscriptions (admin)
+// @Tags subscriptions
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {array} models.Subscription
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /subscriptions [get]
+func (h *SubscriptionHandler) List(c *gin.Context) {
+	subscriptions, err := h.subscriptionUC.List(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+// Get godoc
+// @Summary Get a subscription (admin)
+// @Tags subscriptions
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.Subscription
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /subscriptions/{id} [get]
+func (h *SubscriptionHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid subscription id"))
+		return
+	}
+
+	s, err := h.subscriptionUC.Get(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+// Update godoc
+// @Summary Update a subscription (admin)
+// @Description Changes the fields that are given. A new URL must be https on a public host. Setting active to false stops events being pushed; setting it back resumes with the events published from then on.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Param request body models.UpdateSubscriptionRequest true "Changes"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.Subscription
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /subscriptions/{id} [put]
+func (h *SubscriptionHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid subscription id"))
+		return
+	}
+	var req models.UpdateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+
+	s, err := h.subscriptionUC.Update(c.Request.Context(), id, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+// Deactivate godoc
+// @Summary Deactivate a subscription (admin)
+// @Description Stops events being pushed to the subscription. Deliveries still pending go to the dead-letter list. The subscription and its deliveries are kept.
+// @Tags subscriptions
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.Subscription
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /subscriptions/{id} [delete]
+func (h *SubscriptionHandler) Deactivate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid subscription id"))
+		return
+	}
+
+	s, err := h.subscriptionUC.Deactivate(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+// RotateSecret godoc
+// @Summary Rotate a subscription's signing secret (admin)
+// @Description Issues a new secret, returned once in the response. Until previous_secret_expires_at deliveries carry a signature under the old secret as well, so the partner can switch over without dropping any.
+// @Tags subscriptions
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.Subscription
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /subscriptions/{id}/rotate-secret [post]
+func (h *SubscriptionHandler) RotateSecret(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid subscription id"))
+		return
+	}
+
+	s, err := h.subscriptionUC.RotateSecret(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+// ListDeliveries godoc
+// @Summary List webhook deliveries (admin)
+// @Description The 200 most recent deliveries, newest first. status=dead is the dead-letter list.
+// @Tags subscriptions
+// @Produce json
+// @Param subscription_id query int false "Filter by subscription"
+// @Param event_id query int false "Filter by event"
+// @Param status query string false "Filter by status: pending, delivered or dead"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {array} models.WebhookDelivery
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /webhook-deliveries [get]
+func (h *SubscriptionHandler) ListDeliveries(c *gin.Context) {
+	filter := models.DeliveryFilter{Status: c.Query("status")}
+	if s := c.Query("subscription_id"); s != "" {
+		subscriptionID, err := strconv.Atoi(s)
+		if err != nil {
+			c.Error(apperror.Invalidf("invalid subscription_id"))
+			return
+		}
+		filter.SubscriptionID = subscriptionID
+	}
+	if s := c.Query("event_id"); s != "" {
+		eventID, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			c.Error(apperror.Invalidf("invalid event_id"))
+			return
+		}
+		filter.EventID = eventID
+	}
+
+	deliveries, err := h.subscriptionUC.ListDeliveries(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// GetDelivery godoc
+// @Summary Get a webhook delivery and its log (admin)
+// @Description The delivery with every attempt made: when, the status code and the start of the answer, or why no answer came.
+// @Tags subscriptions
+// @Produce json
+// @Param id path int true "Delivery ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.WebhookDelivery
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /webhook-deliveries/{id} [get]
+func (h *SubscriptionHandler) GetDelivery(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid delivery id"))
+		return
+	}
+
+	d, err := h.subscriptionUC.GetDelivery(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, d)
+}
+
+// Redeliver godoc
+// @Summary Redeliver a webhook (admin)
+// @Description Puts a dead or delivered delivery back in the queue with a fresh set of attempts. Its log is kept.
+// @Tags subscriptions
+// @Produce json
+// @Param id path int true "Delivery ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.WebhookDelivery
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /webhook-deliveries/{id}/redeliver [post]
+func (h *SubscriptionHandler) Redeliver(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid delivery id"))
+		return
+	}
+
+	d, err := h.subscriptionUC.Redeliver(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, d)
+}
+
+// Dispatch godoc
+// @Summary Post pending webhook deliveries (admin)
+// @Description Posts the deliveries that are due now, as the background dispatcher does. Failed posts are retried with a doubling delay until the attempts run out.
+// @Tags subscriptions
+// @Produce json
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.DeliveryDispatch
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /webhook-deliveries/dispatch [post]
+func (h *SubscriptionHandler) Dispatch(c *gin.Context) {
+	result, err := h.subscriptionUC.Dispatch(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/subscription"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+	"github.com/lib/pq"
+)
+
+type subscriptionRepository struct {
+	DB *sql.DB
+}
+
+func NewSubscriptionRepository(DB *sql.DB) subscription.SubscriptionRepository {
+	return &subscriptionRepository{
+		DB: DB,
+	}
+}
+
+const subscriptionColumns = `id, name, url, event_types, active, secret, previous_secret, previous_secret_expires_at,
+                             created_by, created_at, updated_at`
+
+func scanSubscription(row interface{ Scan(...any) error }, s *models.Subscription) error {
+	var previousSecret sql.NullString
+	err := row.Scan(
+		&s.ID,
+		&s.Name,
+		&s.URL,
+		pq.Array(&s.EventTypes),
+		&s.Active,
+		&s.Secret,
+		&previousSecret,
+		&s.PreviousSecretExpiresAt,
+		&s.CreatedBy,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+	s.PreviousSecret = previousSecret.String
+	return err
+}
+
+func (r *subscriptionRepository) Create(ctx context.Context, s *models.Subscription) error {
+	query := `INSERT INTO webhook_subscriptions (name, url, event_types, active, secret, created_by)
+              VALUES ($1, $2, $3, $4, $5, $6)
+              RETURNING id, created_at, updated_at`
+	err := postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, s.Name, s.URL, pq.Array(s.EventTypes), s.Active,
+		s.Secret, s.CreatedBy).Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("insert subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *subscriptionRepository) Get(ctx context.Context, id int) (*models.Subscription, error) {
+	var s models.Subscription
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions WHERE id = $1`
+	err := scanSubscription(postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, id), &s)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query subscription: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *subscriptionRepository) List(ctx context.Context) ([]models.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions ORDER BY id`
+	return r.querySubscriptions(ctx, query)
+}
+
+// ListFor implements [subscription.SubscriptionRepository].
+func (r *subscriptionRepository) ListFor(ctx context.Context, eventType string) ([]models.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions
+              WHERE active AND ($1 = ANY(event_types) OR $2 = ANY(event_types))
+              ORDER BY id`
+	return r.querySubscriptions(ctx, query, eventType, models.AllEvents)
+}
+
+func (r *subscriptionRepository) querySubscriptions(ctx context.Context, query string, args ...any) ([]models.Subscription, error) {
+	rows, err := postgres.Conn(ctx, r.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []models.Subscription
+	for rows.Next() {
+		var s models.Subscription
+		if err := scanSubscription(rows, &s); err != nil {
+			return nil, fmt.Errorf("scan subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	return subscriptions, rows.Err()
+}
+
+func (r *subscriptionRepository) Update(ctx context.Context, s *models.Subscription) error {
+	query := `UPDATE webhook_subscriptions
+              SET name = $2, url = $3, event_types = $4, active = $5, secret = $6,
+                  previous_secret = NULLIF($7, ''), previous_secret_expires_at = $8, updated_at = CURRENT_TIMESTAMP
+              WHERE id = $1
+              RETURNING updated_at`
+	err := postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, s.ID, s.Name, s.URL, pq.Array(s.EventTypes), s.Active,
+		s.Secret, s.PreviousSecret, s.PreviousSecretExpiresAt).Scan(&s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("update subscription: %w", err)
+	}
+	return nil
+}
+
+const deliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts, last_status_code,
+                         last_error, next_attempt_at, created_at, delivered_at`
+
+func scanDelivery(row interface{ Scan(...any) error }, d *models.WebhookDelivery) error {
+	var payload []byte
+	var statusCode sql.NullInt64
+	var lastError sql.NullString
+	err := row.Scan(
+		&d.ID,
+		&d.SubscriptionID,
+		&d.EventID,
+		&d.EventType,
+		&payload,
+		&d.Status,
+		&d.Attempts,
+		&statusCode,
+		&lastError,
+		&d.NextAttemptAt,
+		&d.CreatedAt,
+		&d.DeliveredAt,
+	)
+	d.Payload, d.LastStatusCode, d.LastError = payload, int(statusCode.Int64), lastError.String
+	return err
+}
+
+// CreateDelivery implements [subscription.SubscriptionRepository].
+func (r *subscriptionRepository) CreateDelivery(ctx context.Context, d *models.WebhookDelivery) error {
+	query := `INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, status, next_attempt_at)
+              VALUES ($1, $2, $3, $4, $5, $6)
+              ON CONFLICT (subscription_id, event_id) DO NOTHING
+              RETURNING id, created_at`
+	return postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, d.SubscriptionID, d.EventID, d.EventType,
+		[]byte(d.Payload), d.Status, d.NextAttemptAt).Scan(&d.ID, &d.CreatedAt)
+}
+
+func (r *subscriptionRepository) GetDelivery(ctx context.Context, id int) (*models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+	err := scanDelivery(postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, id), &d)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query webhook delivery: %w", err)
+	}
+	return &d, nil
+}
+
+// ListDeliveries returns the most recent deliveries first.
+func (r *subscriptionRepository) ListDeliveries(ctx context.Context, filter models.DeliveryFilter, limit int) ([]models.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries
+              WHERE ($1 = 0 OR subscription_id = $1) AND ($2 = 0 OR event_id = $2) AND ($3 = '' OR status = $3)
+              ORDER BY id DESC
+              LIMIT $4`
+	return r.queryDeliveries(ctx, query, filter.SubscriptionID, filter.EventID, filter.Status, limit)
+}
+
+// ClaimDeliveries implements [subscription.SubscriptionRepository].
+func (r *subscriptionRepository) ClaimDeliveries(ctx context.Context, now, lease time.Time, limit int) ([]models.WebhookDelivery, error) {
+	query := `UPDATE webhook_deliveries SET attempts = attempts + 1, next_attempt_at = $2
+              WHERE id IN (SELECT id FROM webhook_deliveries
+                           WHERE status = $3 AND next_attempt_at <= $1
+                           ORDER BY next_attempt_at, id
+                           LIMIT $4
+                           FOR UPDATE SKIP LOCKED)
+              RETURNING ` + deliveryColumns
+	return r.queryDeliveries(ctx, query, now, lease, models.DeliveryPending, limit)
+}
+
+func (r *subscriptionRepository) queryDeliveries(ctx context.Context, query string, args ...any) ([]models.WebhookDelivery, error) {
+	rows, err := postgres.Conn(ctx, r.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := scanDelivery(rows, &d); err != nil {
+			return nil, fmt.Errorf("scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *subscriptionRepository) UpdateDelivery(ctx context.Context, d *models.WebhookDelivery) error {
+	query := `UPDATE webhook_deliveries
+              SET status = $2, attempts = $3, last_status_code = NULLIF($4, 0), last_error = NULLIF($5, ''),
+                  next_attempt_at = $6, delivered_at = $7
+              WHERE id = $1`
+	_, err := postgres.Conn(ctx, r.DB).ExecContext(ctx, query, d.ID, d.Status, d.Attempts, d.LastStatusCode, d.LastError,
+		d.NextAttemptAt, d.DeliveredAt)
+	if err != nil {
+		return fmt.Errorf("update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *subscriptionRepository) AddAttempt(ctx context.Context, a *models.DeliveryAttempt) error {
+	query := `INSERT INTO webhook_delivery_attempts (delivery_id, attempted_at, status_code, error, duration_ms, response)
+              VALUES ($1, $2, NULLIF($3, 0), NULLIF($4, ''), $5, NULLIF($6, ''))
+              RETURNING id`
+	err := postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, a.DeliveryID, a.AttemptedAt, a.StatusCode, a.Error,
+		a.DurationMS, a.Response).Scan(&a.ID)
+	if err != nil {
+		return fmt.Errorf("insert webhook delivery attempt: %w", err)
+	}
+	return nil
+}
+
+// ListAttempts returns the delivery's attempts, oldest first.
+func (r *subscriptionRepository) ListAttempts(ctx context.Context, deliveryID int) ([]models.DeliveryAttempt, error) {
+	query := `SELECT id, delivery_id, attempted_at, status_code, error, duration_ms, response
+              FROM webhook_delivery_attempts WHERE delivery_id = $1 ORDER BY id`
+	rows, err := postgres.Conn(ctx, r.DB).QueryContext(ctx, query, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("query webhook delivery attempts: %w", err)
+	}
+	defer rows.Close()
+
+	var attempts []models.DeliveryAttempt
+	for rows.Next() {
+		var a models.DeliveryAttempt
+		var statusCode sql.NullInt64
+		var attemptErr, response sql.NullString
+		if err := rows.Scan(&a.ID, &a.DeliveryID, &a.AttemptedAt, &statusCode, &attemptErr, &a.DurationMS, &response); err != nil {
+			return nil, fmt.Errorf("scan webhook delivery attempt: %w", err)
+		}
+		a.StatusCode, a.Error, a.Response = int(statusCode.Int64), attemptErr.String, response.String
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
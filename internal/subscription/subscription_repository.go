@@ -0,0 +1,30 @@
+package subscription
+
+import (
+	"context"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type SubscriptionRepository interface {
+	Create(ctx context.Context, s *models.Subscription) error
+	Get(ctx context.Context, id int) (*models.Subscription, error)
+	List(ctx context.Context) ([]models.Subscription, error)
+	// ListFor returns the active subscriptions to the event type.
+	ListFor(ctx context.Context, eventType string) ([]models.Subscription, error)
+	Update(ctx context.Context, s *models.Subscription) error
+
+	// CreateDelivery returns sql.ErrNoRows when the event is queued for the
+	// subscription already.
+	CreateDelivery(ctx context.Context, d *models.WebhookDelivery) error
+	GetDelivery(ctx context.Context, id int) (*models.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, filter models.DeliveryFilter, limit int) ([]models.WebhookDelivery, error)
+	// ClaimDeliveries takes up to limit pending deliveries due by now,
+	// counting an attempt and holding them until lease so that concurrent
+	// dispatchers do not send them too.
+	ClaimDeliveries(ctx context.Context, now, lease time.Time, limit int) ([]models.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, d *models.WebhookDelivery) error
+	AddAttempt(ctx context.Context, a *models.DeliveryAttempt) error
+	ListAttempts(ctx context.Context, deliveryID int) ([]models.DeliveryAttempt, error)
+}
@@ -0,0 +1,35 @@
+package subscription
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// SubscriptionUsecase pushes domain events to partner webhooks. Events are
+// queued per subscription as the relay publishes them and posted by the
+// dispatcher, so a slow or failing partner holds up nobody else.
+type SubscriptionUsecase interface {
+	Create(ctx context.Context, req models.CreateSubscriptionRequest, actor string) (*models.Subscription, error)
+	Get(ctx context.Context, id int) (*models.Subscription, error)
+	List(ctx context.Context) ([]models.Subscription, error)
+	Update(ctx context.Context, id int, req models.UpdateSubscriptionRequest) (*models.Subscription, error)
+	// Deactivate stops pushing events to the subscription. Deliveries still
+	// pending go to the dead-letter list when next due.
+	Deactivate(ctx context.Context, id int) (*models.Subscription, error)
+	// RotateSecret gives the subscription a new secret. The old one keeps
+	// signing alongside it for the configured grace period.
+	RotateSecret(ctx context.Context, id int) (*models.Subscription, error)
+
+	// Publish queues the event for every active subscription to its type.
+	// It implements [eventstream.Publisher].
+	Publish(ctx context.Context, e *models.Event) error
+	// Dispatch posts the pending deliveries that are due.
+	Dispatch(ctx context.Context) (*models.DeliveryDispatch, error)
+
+	ListDeliveries(ctx context.Context, filter models.DeliveryFilter) ([]models.WebhookDelivery, error)
+	// GetDelivery returns the delivery with its log of attempts.
+	GetDelivery(ctx context.Context, id int) (*models.WebhookDelivery, error)
+	// Redeliver queues a delivered or dead delivery again.
+	Redeliver(ctx context.Context, id int) (*models.WebhookDelivery, error)
+}
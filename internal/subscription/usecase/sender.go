@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/subscription"
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// responseLimit caps how much of an answer's body is logged.
+const responseLimit = 1024
+
+// sender posts deliveries to partner endpoints.
+type sender struct {
+	client *http.Client
+}
+
+// newSender returns a sender that only connects to public addresses, so a
+// subscription cannot reach the services next to the API, whatever its host
+// name resolves to. Redirects are not followed: a 3xx answer fails the
+// attempt like any other non-2xx one.
+func newSender(timeout time.Duration) *sender {
+	dialer := &net.Dialer{Timeout: timeout, Control: dialPublic}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialled in the endpoint's place and defeat the check.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &sender{client: &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// checkURL accepts an https URL whose host is a name or a public address.
+// Names are checked again when dialled, as they may resolve anywhere.
+func checkURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+		return subscription.ErrInvalidURL
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return subscription.ErrInvalidURL.Withf("url host %s is not public", u.Hostname())
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && !publicAddr(ip) {
+		return subscription.ErrInvalidURL.Withf("url host %s is not public", u.Hostname())
+	}
+	return nil
+}
+
+// dialPublic refuses connections to addresses that are not public.
+func dialPublic(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil || !publicAddr(ip) {
+		return fmt.Errorf("refusing to connect to %s: not a public address", host)
+	}
+	return nil
+}
+
+// publicAddr reports whether ip is routable on the internet: not loopback,
+// private, link-local, multicast or unspecified.
+func publicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast()
+}
+
+// post sends the delivery's payload to url and records the outcome in
+// attempt; any 2xx answer counts as delivered. The request carries the
+// scheme inbound webhooks are checked with: X-Webhook-Signature holds
+// "sha256=" and the hex HMAC-SHA256 of "timestamp.body" under each secret,
+// comma separated, with the timestamp in X-Webhook-Timestamp.
+func (s *sender) post(ctx context.Context, url string, secrets []string, d *models.WebhookDelivery, attempt *models.DeliveryAttempt) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(d.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return
+	}
+	timestamp := strconv.FormatInt(attempt.AttemptedAt.Unix(), 10)
+	signatures := make([]string, len(secrets))
+	for i, secret := range secrets {
+		signatures[i] = "sha256=" + sign(secret, timestamp, d.Payload)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "loan-billing-webhooks/1")
+	req.Header.Set("X-Webhook-ID", strconv.FormatInt(d.EventID, 10))
+	req.Header.Set("X-Webhook-Event", d.EventType)
+	req.Header.Set("X-Webhook-Delivery", strconv.Itoa(d.ID))
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", strings.Join(signatures, ","))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	attempt.DurationMS = int(time.Since(start).Milliseconds())
+	if err != nil {
+		attempt.Error = err.Error()
+		return
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, responseLimit))
+	attempt.StatusCode, attempt.Response = resp.StatusCode, strings.ToValidUTF8(string(body), "")
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Error = fmt.Sprintf("endpoint answered %s", resp.Status)
+	}
+}
+
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
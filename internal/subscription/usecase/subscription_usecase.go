@@ -0,0 +1,348 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/subscription"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+)
+
+const (
+	listLimit     = 200
+	dispatchBatch = 100
+	// sendLease is how long a claimed delivery is held before another
+	// dispatcher may take it, should this one die mid-post.
+	sendLease = 5 * time.Minute
+	// retryBackoff doubles with every failed attempt, up to maxBackoff.
+	retryBackoff = 30 * time.Second
+	maxBackoff   = 6 * time.Hour
+	secretPrefix = "whsec_"
+)
+
+type subscriptionUseCase struct {
+	subscriptionRepo subscription.SubscriptionRepository
+	clock            clock.Clock
+	sender           *sender
+	maxAttempts      int
+	secretGrace      time.Duration
+}
+
+// NewSubscriptionUseCase builds the partner webhooks. A post gets timeout to
+// answer; a delivery that failed maxAttempts times goes to the dead-letter
+// list. A rotated secret keeps signing for secretGrace.
+func NewSubscriptionUseCase(sr subscription.SubscriptionRepository, clk clock.Clock, timeout time.Duration,
+	maxAttempts int, secretGrace time.Duration) subscription.SubscriptionUsecase {
+	return &subscriptionUseCase{
+		subscriptionRepo: sr,
+		clock:            clk,
+		sender:           newSender(timeout),
+		maxAttempts:      maxAttempts,
+		secretGrace:      secretGrace,
+	}
+}
+
+// Create implements [subscription.SubscriptionUsecase]. The URL must be https
+// on a public host.
+func (uc *subscriptionUseCase) Create(ctx context.Context, req models.CreateSubscriptionRequest, actor string) (*models.Subscription, error) {
+	if err := checkURL(req.URL); err != nil {
+		return nil, err
+	}
+	eventTypes, err := validEventTypes(req.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	s := &models.Subscription{
+		Name:       req.Name,
+		URL:        req.URL,
+		EventTypes: eventTypes,
+		Active:     true,
+		Secret:     secret,
+		CreatedBy:  actor,
+	}
+	if err := uc.subscriptionRepo.Create(ctx, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get implements [subscription.SubscriptionUsecase]. The secrets are left
+// out.
+func (uc *subscriptionUseCase) Get(ctx context.Context, id int) (*models.Subscription, error) {
+	s, err := uc.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.Secret = ""
+	return s, nil
+}
+
+func (uc *subscriptionUseCase) get(ctx context.Context, id int) (*models.Subscription, error) {
+	s, err := uc.subscriptionRepo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, subscription.ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+func (uc *subscriptionUseCase) List(ctx context.Context) ([]models.Subscription, error) {
+	subscriptions, err := uc.subscriptionRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if subscriptions == nil {
+		subscriptions = []models.Subscription{}
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+	return subscriptions, nil
+}
+
+func (uc *subscriptionUseCase) Update(ctx context.Context, id int, req models.UpdateSubscriptionRequest) (*models.Subscription, error) {
+	s, err := uc.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		if *req.Name == "" {
+			return nil, apperror.Invalidf("name must not be empty")
+		}
+		s.Name = *req.Name
+	}
+	if req.URL != nil {
+		if err := checkURL(*req.URL); err != nil {
+			return nil, err
+		}
+		s.URL = *req.URL
+	}
+	if req.EventTypes != nil {
+		if s.EventTypes, err = validEventTypes(req.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+	if req.Active != nil {
+		s.Active = *req.Active
+	}
+	if err := uc.subscriptionRepo.Update(ctx, s); err != nil {
+		return nil, err
+	}
+	s.Secret = ""
+	return s, nil
+}
+
+func (uc *subscriptionUseCase) Deactivate(ctx context.Context, id int) (*models.Subscription, error) {
+	active := false
+	return uc.Update(ctx, id, models.UpdateSubscriptionRequest{Active: &active})
+}
+
+// RotateSecret implements [subscription.SubscriptionUsecase]. Rotating
+// again within the grace period drops the oldest secret at once.
+func (uc *subscriptionUseCase) RotateSecret(ctx context.Context, id int) (*models.Subscription, error) {
+	s, err := uc.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	expires := uc.clock.Now().Add(uc.secretGrace)
+	s.PreviousSecret, s.PreviousSecretExpiresAt, s.Secret = s.Secret, &expires, secret
+	if err := uc.subscriptionRepo.Update(ctx, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// validEventTypes checks the event types against the known ones and drops
+// duplicates.
+func validEventTypes(eventTypes []string) ([]string, error) {
+	if len(eventTypes) == 0 {
+		return nil, apperror.Invalidf("event_types must not be empty")
+	}
+	valid := make([]string, 0, len(eventTypes))
+	for _, t := range eventTypes {
+		if t != models.AllEvents && !slices.Contains(models.EventTypes, t) {
+			return nil, subscription.ErrUnknownEventType.Withf("unknown event type %q", t)
+		}
+		if !slices.Contains(valid, t) {
+			valid = append(valid, t)
+		}
+	}
+	return valid, nil
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate secret: %w", err)
+	}
+	return secretPrefix + hex.EncodeToString(b), nil
+}
+
+// Publish implements [subscription.SubscriptionUsecase]. An event the relay
+// publishes again is not queued twice for a subscription.
+func (uc *subscriptionUseCase) Publish(ctx context.Context, e *models.Event) error {
+	subscriptions, err := uc.subscriptionRepo.ListFor(ctx, e.Type)
+	if err != nil || len(subscriptions) == 0 {
+		return err
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	now := uc.clock.Now()
+	for _, s := range subscriptions {
+		d := &models.WebhookDelivery{
+			SubscriptionID: s.ID,
+			EventID:        e.ID,
+			EventType:      e.Type,
+			Payload:        payload,
+			Status:         models.DeliveryPending,
+			NextAttemptAt:  now,
+		}
+		if err := uc.subscriptionRepo.CreateDelivery(ctx, d); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("queue event %d for subscription %d: %w", e.ID, s.ID, err)
+		}
+	}
+	return nil
+}
+
+// Dispatch implements [subscription.SubscriptionUsecase]. A failed post is
+// retried with a doubling delay until the attempts run out.
+func (uc *subscriptionUseCase) Dispatch(ctx context.Context) (*models.DeliveryDispatch, error) {
+	result := &models.DeliveryDispatch{}
+	subscriptions := map[int]*models.Subscription{}
+	for {
+		now := uc.clock.Now()
+		batch, err := uc.subscriptionRepo.ClaimDeliveries(ctx, now, now.Add(sendLease), dispatchBatch)
+		if err != nil {
+			return result, err
+		}
+		for i := range batch {
+			d := &batch[i]
+			s, ok := subscriptions[d.SubscriptionID]
+			if !ok {
+				if s, err = uc.get(ctx, d.SubscriptionID); err != nil {
+					return result, err
+				}
+				subscriptions[d.SubscriptionID] = s
+			}
+			if err := uc.deliver(ctx, s, d, result); err != nil {
+				return result, err
+			}
+		}
+		if len(batch) < dispatchBatch {
+			return result, nil
+		}
+	}
+}
+
+func (uc *subscriptionUseCase) deliver(ctx context.Context, s *models.Subscription, d *models.WebhookDelivery, result *models.DeliveryDispatch) error {
+	attempt := &models.DeliveryAttempt{DeliveryID: d.ID, AttemptedAt: uc.clock.Now()}
+	if s.Active {
+		uc.sender.post(ctx, s.URL, uc.secrets(s), d, attempt)
+	} else {
+		attempt.Error = "subscription is inactive"
+	}
+	if err := uc.subscriptionRepo.AddAttempt(ctx, attempt); err != nil {
+		return err
+	}
+
+	now := uc.clock.Now()
+	d.LastStatusCode, d.LastError = attempt.StatusCode, attempt.Error
+	switch {
+	case attempt.Error == "":
+		d.Status, d.DeliveredAt = models.DeliveryDelivered, &now
+		result.Delivered++
+	case !s.Active || d.Attempts >= uc.maxAttempts:
+		d.Status = models.DeliveryDead
+		result.Dead++
+	default:
+		backoff := maxBackoff
+		if d.Attempts < 20 {
+			backoff = min(retryBackoff<<(d.Attempts-1), maxBackoff)
+		}
+		d.NextAttemptAt = now.Add(backoff)
+		result.Retrying++
+	}
+	return uc.subscriptionRepo.UpdateDelivery(ctx, d)
+}
+
+// secrets returns the secrets a delivery is signed with: the current one,
+// and the previous one while its grace period lasts.
+func (uc *subscriptionUseCase) secrets(s *models.Subscription) []string {
+	secrets := []string{s.Secret}
+	if s.PreviousSecret != "" && s.PreviousSecretExpiresAt != nil && uc.clock.Now().Before(*s.PreviousSecretExpiresAt) {
+		secrets = append(secrets, s.PreviousSecret)
+	}
+	return secrets
+}
+
+func (uc *subscriptionUseCase) ListDeliveries(ctx context.Context, filter models.DeliveryFilter) ([]models.WebhookDelivery, error) {
+	switch filter.Status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead:
+	default:
+		return nil, apperror.Invalidf("status must be pending, delivered or dead")
+	}
+	deliveries, err := uc.subscriptionRepo.ListDeliveries(ctx, filter, listLimit)
+	if err != nil {
+		return nil, err
+	}
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+	return deliveries, nil
+}
+
+func (uc *subscriptionUseCase) GetDelivery(ctx context.Context, id int) (*models.WebhookDelivery, error) {
+	d, err := uc.subscriptionRepo.GetDelivery(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, subscription.ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	if d.Log, err = uc.subscriptionRepo.ListAttempts(ctx, id); err != nil {
+		return nil, err
+	}
+	if d.Log == nil {
+		d.Log = []models.DeliveryAttempt{}
+	}
+	return d, nil
+}
+
+// Redeliver implements [subscription.SubscriptionUsecase]. The delivery
+// gets a fresh set of attempts and goes out with the next dispatch; its
+// log is kept.
+func (uc *subscriptionUseCase) Redeliver(ctx context.Context, id int) (*models.WebhookDelivery, error) {
+	d, err := uc.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.Status == models.DeliveryPending {
+		return nil, subscription.ErrDeliveryPending
+	}
+	d.Status, d.Attempts, d.NextAttemptAt, d.DeliveredAt = models.DeliveryPending, 0, uc.clock.Now(), nil
+	if err := uc.subscriptionRepo.UpdateDelivery(ctx, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,8 @@
+package topup
+
+import "github.com/evrintobing17/loan-billing-system/pkg/apperror"
+
+var (
+	ErrDelinquent     = apperror.New(apperror.Unprocessable, "loan_delinquent", "delinquent loans cannot be topped up")
+	ErrFeesNotCovered = apperror.New(apperror.Unprocessable, "top_up_fees_not_covered", "top-up amount does not cover the new loan's deducted fees")
+)
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/evrintobing17/loan-billing-system/internal/topup"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+type TopUpHandler struct {
+	topUpUC topup.TopUpUsecase
+}
+
+func NewTopUpHandler(uc topup.TopUpUsecase) *TopUpHandler {
+	return &TopUpHandler{topUpUC: uc}
+}
+
+// TopUpLoan godoc
+// @Summary Top up a loan
+// @Description Refinance an active, non-delinquent loan (admin only). Its outstanding balance is settled internally and a new loan is created for that balance plus the top-up amount. Only the net new cash remains to be disbursed.
+// @Tags loans
+// @Accept json
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param request body models.TopUpRequest true "Top-up amount and new loan terms"
+// @Success 201 {object} models.TopUpResult
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /loans/{id}/top-up [post]
+func (h *TopUpHandler) TopUpLoan(c *gin.Context) {
+	loanID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid loan id"))
+		return
+	}
+
+	var req models.TopUpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+
+	result, err := h.topUpUC.TopUpLoan(c.Request.Context(), loanID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+// GetTopUps godoc
+// @Summary List top-ups of a loan
+// @Description Top-ups the loan took part in, as the new or the refinanced loan.
+// @Tags loans
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Success 200 {array} models.TopUp
+// @Failure 404 {object} models.Problem
+// @Router /loans/{id}/top-ups [get]
+func (h *TopUpHandler) GetTopUps(c *gin.Context) {
+	loanID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid loan id"))
+		return
+	}
+	topUps, err := h.topUpUC.GetTopUps(c.Request.Context(), loanID)
+	if err != nil {
+		c.Error(apperror.Default(err, apperror.NotFound))
+		return
+	}
+	c.JSON(http.StatusOK, topUps)
+}
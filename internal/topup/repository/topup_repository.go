@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/evrintobing17/loan-billing-system/internal/topup"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+type topUpRepository struct {
+	DB *sql.DB
+}
+
+func NewTopUpRepository(DB *sql.DB) topup.TopUpRepository {
+	return &topUpRepository{
+		DB: DB,
+	}
+}
+
+// Create implements [topup.TopUpRepository].
+func (r *topUpRepository) Create(ctx context.Context, t *models.TopUp) error {
+	query := `INSERT INTO loan_topups (loan_id, previous_loan_id, settlement_payment_id, settlement_amount, topup_amount, net_new_cash)
+              VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+	return postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, t.LoanID, t.PreviousLoanID, t.SettlementPaymentID,
+		t.SettlementAmount, t.TopUpAmount, t.NetNewCash).Scan(&t.ID, &t.CreatedAt)
+}
+
+func (r *topUpRepository) GetByLoanID(ctx context.Context, loanID int) ([]models.TopUp, error) {
+	query := `SELECT id, loan_id, previous_loan_id, settlement_payment_id, settlement_amount, topup_amount, net_new_cash, created_at
+              FROM loan_topups
+              WHERE loan_id = $1 OR previous_loan_id = $1
+              ORDER BY id`
+	rows, err := postgres.Conn(ctx, r.DB).QueryContext(ctx, query, loanID)
+	if err != nil {
+		return nil, fmt.Errorf("query top-ups: %w", err)
+	}
+	defer rows.Close()
+
+	var topUps []models.TopUp
+	for rows.Next() {
+		var t models.TopUp
+		err := rows.Scan(
+			&t.ID,
+			&t.LoanID,
+			&t.PreviousLoanID,
+			&t.SettlementPaymentID,
+			&t.SettlementAmount,
+			&t.TopUpAmount,
+			&t.NetNewCash,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan top-up: %w", err)
+		}
+		topUps = append(topUps, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return topUps, nil
+}
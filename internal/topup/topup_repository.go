@@ -0,0 +1,13 @@
+package topup
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type TopUpRepository interface {
+	Create(ctx context.Context, topUp *models.TopUp) error
+	// GetByLoanID returns the top-ups the loan took part in, as the new or the previous loan.
+	GetByLoanID(ctx context.Context, loanID int) ([]models.TopUp, error)
+}
@@ -0,0 +1,12 @@
+package topup
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type TopUpUsecase interface {
+	TopUpLoan(ctx context.Context, loanID int, req models.TopUpRequest) (*models.TopUpResult, error)
+	GetTopUps(ctx context.Context, loanID int) ([]models.TopUp, error)
+}
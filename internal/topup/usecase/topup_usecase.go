@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evrintobing17/loan-billing-system/internal/disbursement"
+	"github.com/evrintobing17/loan-billing-system/internal/loan"
+	"github.com/evrintobing17/loan-billing-system/internal/payment"
+	"github.com/evrintobing17/loan-billing-system/internal/topup"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+// ChannelInternalSettlement is the disbursement channel for the part of a
+// top-up loan that pays off the previous loan instead of reaching the borrower.
+const ChannelInternalSettlement = "internal_settlement"
+
+type topUpUseCase struct {
+	topUpRepo      topup.TopUpRepository
+	loanRepo       loan.LoanRepository
+	loanUC         loan.LoanUsecase
+	paymentUC      payment.PaymentUsecase
+	disbursementUC disbursement.DisbursementUsecase
+	tx             postgres.Transactor
+	clock          clock.Clock
+}
+
+func NewTopUpUseCase(tr topup.TopUpRepository, lr loan.LoanRepository, luc loan.LoanUsecase, puc payment.PaymentUsecase,
+	duc disbursement.DisbursementUsecase, tx postgres.Transactor, clk clock.Clock) topup.TopUpUsecase {
+	return &topUpUseCase{
+		topUpRepo:      tr,
+		loanRepo:       lr,
+		loanUC:         luc,
+		paymentUC:      puc,
+		disbursementUC: duc,
+		tx:             tx,
+		clock:          clk,
+	}
+}
+
+// TopUpLoan refinances a loan in good standing. In one transaction it settles
+// the loan's outstanding balance with an internal settlement payment, creates
+// a new loan for the settlement plus the top-up amount, disburses the
+// settlement part internally and links the two loans. The rest of the new
+// loan's net disbursement is the new cash, paid out through the normal
+// disbursement endpoint. The previous loan is locked first, so a concurrent
+// top-up, write-off or payment waits and then finds it refinanced.
+func (uc *topUpUseCase) TopUpLoan(ctx context.Context, loanID int, req models.TopUpRequest) (*models.TopUpResult, error) {
+	today := clock.Today(ctx, uc.clock)
+	var result models.TopUpResult
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		previous, err := uc.loanRepo.GetForUpdate(ctx, loanID)
+		if err != nil {
+			return err
+		}
+		if previous.Status != models.LoanStatusActive {
+			return loan.ErrNotActive.Withf("only active loans can be topped up")
+		}
+		delinquent, err := uc.loanUC.IsDelinquent(ctx, loanID)
+		if err != nil {
+			return err
+		}
+		if delinquent {
+			return topup.ErrDelinquent
+		}
+
+		settlement, err := uc.paymentUC.SettleLoan(ctx, loanID)
+		if err != nil {
+			return err
+		}
+
+		newLoan, err := uc.loanUC.CreateLoan(ctx, models.LoanTerms{
+			BorrowerID:   previous.BorrowerID,
+			Principal:    settlement.Amount + req.Amount,
+			InterestRate: req.InterestRate,
+			TermWeeks:    req.TermWeeks,
+			StartDate:    today,
+			ProductCode:  req.ProductCode,
+		})
+		if err != nil {
+			return err
+		}
+		netNewCash := newLoan.NetDisbursement - settlement.Amount
+		if netNewCash < -0.005 {
+			return topup.ErrFeesNotCovered
+		}
+
+		_, err = uc.disbursementUC.Disburse(ctx, newLoan.ID, models.DisbursementRequest{
+			Amount:           settlement.Amount,
+			DisbursementDate: today.Format("2006-01-02"),
+			Channel:          ChannelInternalSettlement,
+			Reference:        fmt.Sprintf("loan:%d", loanID),
+		})
+		if err != nil {
+			return err
+		}
+		if err := uc.loanRepo.LinkRefinance(ctx, loanID, newLoan.ID); err != nil {
+			return err
+		}
+
+		result.TopUp = models.TopUp{
+			LoanID:              newLoan.ID,
+			PreviousLoanID:      loanID,
+			SettlementPaymentID: settlement.ID,
+			SettlementAmount:    settlement.Amount,
+			TopUpAmount:         req.Amount,
+			NetNewCash:          max(netNewCash, 0),
+		}
+		if err := uc.topUpRepo.Create(ctx, &result.TopUp); err != nil {
+			return err
+		}
+
+		// Reload to pick up the disbursement and the link.
+		loaded, err := uc.loanRepo.GetByID(ctx, newLoan.ID)
+		if err != nil {
+			return err
+		}
+		result.Loan = *loaded
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (uc *topUpUseCase) GetTopUps(ctx context.Context, loanID int) ([]models.TopUp, error) {
+	if _, err := uc.loanRepo.GetByID(ctx, loanID); err != nil {
+		return nil, err
+	}
+	return uc.topUpRepo.GetByLoanID(ctx, loanID)
+}
@@ -0,0 +1,13 @@
+package webhook
+
+import "github.com/evrintobing17/loan-billing-system/pkg/apperror"
+
+var (
+	ErrUnknownProvider  = apperror.New(apperror.NotFound, "unknown_provider", "payment provider is not configured")
+	ErrInvalidSignature = apperror.New(apperror.Unauthorized, "invalid_signature", "webhook signature is missing or invalid")
+	ErrInvalidPayload   = apperror.New(apperror.Invalid, "invalid_webhook_payload", "webhook payload cannot be read")
+	ErrEventNotFound    = apperror.New(apperror.NotFound, "webhook_event_not_found", "webhook event not found")
+	ErrEventProcessed   = apperror.New(apperror.Conflict, "webhook_event_processed", "webhook event was already processed")
+	ErrPaidTooLongAgo   = apperror.New(apperror.Unprocessable, "paid_too_long_ago", "payment was taken too long ago to be backdated")
+	ErrCurrencyMismatch = apperror.New(apperror.Unprocessable, "currency_mismatch", "money received is not in the loan currency")
+)
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/evrintobing17/loan-billing-system/internal/webhook"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+// maxPayloadBytes bounds a webhook body; provider events are a few KB.
+const maxPayloadBytes = 1 << 20
+
+type WebhookHandler struct {
+	webhookUC webhook.WebhookUsecase
+}
+
+func NewWebhookHandler(uc webhook.WebhookUsecase) *WebhookHandler {
+	return &WebhookHandler{webhookUC: uc}
+}
+
+// ReceivePayment godoc
+// @Summary Receive a payment notification
+// @Description Endpoint for payment processors. The request must carry the provider's HMAC signature over the raw body and a recent timestamp. Payment events are posted as loan payments keyed on the provider's event ID; redeliveries are acknowledged without paying twice. A payment the loan rejects is recorded as failed and still acknowledged with 200.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider, e.g. generic or stripe"
+// @Success 200 {object} models.WebhookEvent
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /webhooks/payments/{provider} [post]
+func (h *WebhookHandler) ReceivePayment(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPayloadBytes))
+	if err != nil {
+		c.Error(apperror.Invalidf("cannot read request body: %v", err))
+		return
+	}
+
+	event, err := h.webhookUC.Receive(c.Request.Context(), c.Param("provider"), c.Request.Header, body)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
+
+// ListEvents godoc
+// @Summary List webhook events (admin)
+// @Description Most recent payment notifications first, at most 200.
+// @Tags webhooks
+// @Produce json
+// @Param provider query string false "Filter by provider"
+// @Param status query string false "Filter by status: received, processed, ignored or failed"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {array} models.WebhookEvent
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /webhooks/events [get]
+func (h *WebhookHandler) ListEvents(c *gin.Context) {
+	events, err := h.webhookUC.ListEvents(c.Request.Context(), c.Query("provider"), c.Query("status"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+// GetEvent godoc
+// @Summary Get a webhook event (admin)
+// @Description The notification with its raw payload and outcome.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook event ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.WebhookEvent
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /webhooks/events/{id} [get]
+func (h *WebhookHandler) GetEvent(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid webhook event id"))
+		return
+	}
+
+	event, err := h.webhookUC.GetEvent(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
+
+// ReplayEvent godoc
+// @Summary Replay a webhook event (admin)
+// @Description Processes a stored notification again from its raw payload. Processed events cannot be replayed.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook event ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} models.WebhookEvent
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /webhooks/events/{id}/replay [post]
+func (h *WebhookHandler) ReplayEvent(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid webhook event id"))
+		return
+	}
+
+	event, err := h.webhookUC.Replay(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/webhook"
+)
+
+const (
+	genericTimestampHeader = "X-Webhook-Timestamp"
+	genericSignatureHeader = "X-Webhook-Signature"
+	genericPaymentEvent    = "payment.succeeded"
+)
+
+type genericProvider struct{}
+
+// NewGeneric accepts the house webhook format, for processors that can be
+// configured to send it or a relay in front of them:
+//
+//	X-Webhook-Timestamp: 1772446500
+//	X-Webhook-Signature: hex(HMAC-SHA256(secret, timestamp + "." + body))
+//
+//	{"id": "evt_1", "type": "payment.succeeded",
+//	 "data": {"loan_id": 12, "amount": 110000, "paid_at": "2026-03-02T10:15:00Z"}}
+func NewGeneric() webhook.Provider {
+	return genericProvider{}
+}
+
+func (genericProvider) Verify(header http.Header, body []byte, secret string) (time.Time, error) {
+	timestamp, signature := header.Get(genericTimestampHeader), header.Get(genericSignatureHeader)
+	if timestamp == "" || signature == "" {
+		return time.Time{}, errors.New("missing " + genericTimestampHeader + " or " + genericSignatureHeader + " header")
+	}
+	signedAt, err := parseUnix(timestamp)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !validMAC(secret, timestamp, body, strings.TrimPrefix(signature, "sha256=")) {
+		return time.Time{}, errors.New("signature does not match")
+	}
+	return signedAt, nil
+}
+
+type genericEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		LoanID int       `json:"loan_id"`
+		Amount float64   `json:"amount"`
+		PaidAt time.Time `json:"paid_at"`
+	} `json:"data"`
+}
+
+func (genericProvider) Parse(body []byte) (*webhook.Notification, error) {
+	var e genericEvent
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, err
+	}
+	if e.ID == "" {
+		return nil, errors.New("event id is missing")
+	}
+	n := &webhook.Notification{EventID: e.ID, Type: e.Type, Payment: e.Type == genericPaymentEvent}
+	if n.Payment {
+		if e.Data.LoanID <= 0 || e.Data.Amount <= 0 {
+			return nil, errors.New("payment event needs a loan_id and a positive amount")
+		}
+		n.LoanID, n.Amount, n.PaidAt = e.Data.LoanID, e.Data.Amount, e.Data.PaidAt
+	}
+	return n, nil
+}
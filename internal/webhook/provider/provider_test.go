@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testSecret    = "whsec_test"
+	testTimestamp = "1772446500"
+)
+
+func testMAC(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestGenericVerify(t *testing.T) {
+	body := `{"id":"evt_1","type":"payment.succeeded"}`
+	good := testMAC(testSecret, testTimestamp, body)
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		body      string
+		wantErr   string
+	}{
+		{name: "valid", timestamp: testTimestamp, signature: good, body: body},
+		{name: "valid with sha256 prefix", timestamp: testTimestamp, signature: "sha256=" + good, body: body},
+		{name: "missing signature", timestamp: testTimestamp, body: body, wantErr: "missing"},
+		{name: "missing timestamp", signature: good, body: body, wantErr: "missing"},
+		{name: "timestamp not a number", timestamp: "yesterday", signature: good, body: body, wantErr: "invalid timestamp"},
+		{name: "body changed", timestamp: testTimestamp, signature: good, body: body + " ", wantErr: "does not match"},
+		{name: "timestamp changed", timestamp: "1772446501", signature: good, body: body, wantErr: "does not match"},
+		{name: "other secret", timestamp: testTimestamp, signature: testMAC("whsec_other", testTimestamp, body), body: body, wantErr: "does not match"},
+		{name: "signature not hex", timestamp: testTimestamp, signature: "zz", body: body, wantErr: "does not match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.timestamp != "" {
+				header.Set(genericTimestampHeader, tt.timestamp)
+			}
+			if tt.signature != "" {
+				header.Set(genericSignatureHeader, tt.signature)
+			}
+			signedAt, err := NewGeneric().Verify(header, []byte(tt.body), testSecret)
+			checkVerify(t, signedAt, err, tt.wantErr)
+		})
+	}
+}
+
+func TestStripeVerify(t *testing.T) {
+	body := `{"id":"evt_1","type":"payment_intent.succeeded"}`
+	good := testMAC(testSecret, testTimestamp, body)
+	stale := testMAC("whsec_old", testTimestamp, body)
+	tests := []struct {
+		name    string
+		header  string
+		wantErr string
+	}{
+		{name: "valid", header: "t=" + testTimestamp + ",v1=" + good},
+		{name: "any v1 may match", header: "t=" + testTimestamp + ",v1=" + stale + ",v1=" + good},
+		{name: "other schemes are ignored", header: "t=" + testTimestamp + ",v0=" + stale + ", v1=" + good},
+		{name: "missing header", wantErr: "missing"},
+		{name: "no timestamp", header: "v1=" + good, wantErr: "malformed"},
+		{name: "no v1 signature", header: "t=" + testTimestamp + ",v0=" + good, wantErr: "malformed"},
+		{name: "timestamp not a number", header: "t=now,v1=" + good, wantErr: "invalid timestamp"},
+		{name: "no signature matches", header: "t=" + testTimestamp + ",v1=" + stale, wantErr: "does not match"},
+		{name: "timestamp changed", header: "t=1772446501,v1=" + good, wantErr: "does not match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.header != "" {
+				header.Set(stripeSignatureHeader, tt.header)
+			}
+			signedAt, err := NewStripe().Verify(header, []byte(body), testSecret)
+			checkVerify(t, signedAt, err, tt.wantErr)
+		})
+	}
+}
+
+// checkVerify expects either an error containing wantErr or the signing
+// time of testTimestamp, which the caller checks against its tolerance.
+func checkVerify(t *testing.T, signedAt time.Time, err error, wantErr string) {
+	t.Helper()
+	if wantErr != "" {
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("Verify() error = %v, want %q", err, wantErr)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if want := time.Unix(1772446500, 0); !signedAt.Equal(want) {
+		t.Errorf("Verify() signed at %v, want %v", signedAt, want)
+	}
+}
+
+func TestStripeParse(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		wantAmount   float64
+		wantCurrency string
+		wantLoanID   int
+		wantErr      string
+	}{
+		{
+			name:         "amount in cents",
+			body:         `{"id":"evt_1","type":"payment_intent.succeeded","created":1772446500,"data":{"object":{"amount_received":11000050,"currency":"usd","metadata":{"loan_id":"12"}}}}`,
+			wantAmount:   110000.50,
+			wantCurrency: "USD",
+			wantLoanID:   12,
+		},
+		{
+			name:         "zero-decimal currency",
+			body:         `{"id":"evt_2","type":"payment_intent.succeeded","data":{"object":{"amount_received":110000,"currency":"JPY","metadata":{"loan_id":"12"}}}}`,
+			wantAmount:   110000,
+			wantCurrency: "JPY",
+			wantLoanID:   12,
+		},
+		{name: "no loan", body: `{"id":"evt_3","type":"payment_intent.succeeded","data":{"object":{"amount_received":100,"currency":"usd"}}}`, wantErr: "loan_id"},
+		{name: "no amount", body: `{"id":"evt_4","type":"payment_intent.succeeded","data":{"object":{"currency":"usd","metadata":{"loan_id":"12"}}}}`, wantErr: "no amount"},
+		{name: "no event id", body: `{"type":"payment_intent.succeeded"}`, wantErr: "event id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := NewStripe().Parse([]byte(tt.body))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !n.Payment || n.LoanID != tt.wantLoanID || n.Amount != tt.wantAmount || n.Currency != tt.wantCurrency {
+				t.Errorf("Parse() = %+v, want a payment of %.2f %s to loan %d", n, tt.wantAmount, tt.wantCurrency, tt.wantLoanID)
+			}
+		})
+	}
+}
+
+func TestGenericParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantPayment bool
+		wantErr     string
+	}{
+		{name: "payment", body: `{"id":"evt_1","type":"payment.succeeded","data":{"loan_id":12,"amount":110000,"paid_at":"2026-03-02T10:15:00Z"}}`, wantPayment: true},
+		{name: "other event", body: `{"id":"evt_2","type":"payment.failed"}`},
+		{name: "payment without a loan", body: `{"id":"evt_3","type":"payment.succeeded","data":{"amount":1}}`, wantErr: "loan_id"},
+		{name: "no event id", body: `{"type":"payment.succeeded"}`, wantErr: "event id"},
+		{name: "not JSON", body: `id=evt_1`, wantErr: "invalid character"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := NewGeneric().Parse([]byte(tt.body))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if n.Payment != tt.wantPayment {
+				t.Errorf("Parse() payment = %v, want %v", n.Payment, tt.wantPayment)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/webhook"
+)
+
+const (
+	stripeSignatureHeader = "Stripe-Signature"
+	stripePaymentEvent    = "payment_intent.succeeded"
+)
+
+// stripeZeroDecimal are the currencies Stripe amounts are not in cents for.
+var stripeZeroDecimal = map[string]bool{
+	"bif": true, "clp": true, "djf": true, "gnf": true, "jpy": true, "kmf": true, "krw": true, "mga": true,
+	"pyg": true, "rwf": true, "ugx": true, "vnd": true, "vuv": true, "xaf": true, "xof": true, "xpf": true,
+}
+
+type stripeProvider struct{}
+
+// NewStripe reads Stripe payment_intent events. The loan is taken from the
+// payment intent's "loan_id" metadata.
+func NewStripe() webhook.Provider {
+	return stripeProvider{}
+}
+
+// Verify checks the Stripe-Signature header, "t=<unix>,v1=<hex>[,v1=...]";
+// any v1 signature may match, which covers secret rotation.
+func (stripeProvider) Verify(header http.Header, body []byte, secret string) (time.Time, error) {
+	value := header.Get(stripeSignatureHeader)
+	if value == "" {
+		return time.Time{}, errors.New("missing " + stripeSignatureHeader + " header")
+	}
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(value, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			timestamp = v
+		case "v1":
+			signatures = append(signatures, v)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return time.Time{}, errors.New("malformed " + stripeSignatureHeader + " header")
+	}
+	signedAt, err := parseUnix(timestamp)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, sig := range signatures {
+		if validMAC(secret, timestamp, body, sig) {
+			return signedAt, nil
+		}
+	}
+	return time.Time{}, errors.New("signature does not match")
+}
+
+type stripeEvent struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object struct {
+			AmountReceived int64             `json:"amount_received"`
+			Currency       string            `json:"currency"`
+			Metadata       map[string]string `json:"metadata"`
+		} `json:"object"`
+	} `json:"data"`
+}
+
+func (stripeProvider) Parse(body []byte) (*webhook.Notification, error) {
+	var e stripeEvent
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, err
+	}
+	if e.ID == "" {
+		return nil, errors.New("event id is missing")
+	}
+	n := &webhook.Notification{EventID: e.ID, Type: e.Type, Payment: e.Type == stripePaymentEvent}
+	if !n.Payment {
+		return n, nil
+	}
+
+	intent := e.Data.Object
+	loanID, err := strconv.Atoi(intent.Metadata["loan_id"])
+	if err != nil || loanID <= 0 {
+		return nil, errors.New("payment intent has no valid loan_id metadata")
+	}
+	if intent.AmountReceived <= 0 {
+		return nil, errors.New("payment intent received no amount")
+	}
+	n.LoanID = loanID
+	n.Currency = strings.ToUpper(intent.Currency)
+	n.Amount = float64(intent.AmountReceived)
+	if !stripeZeroDecimal[strings.ToLower(intent.Currency)] {
+		n.Amount = math.Round(n.Amount) / 100
+	}
+	if e.Created > 0 {
+		n.PaidAt = time.Unix(e.Created, 0)
+	}
+	return n, nil
+}
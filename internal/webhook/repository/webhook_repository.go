@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evrintobing17/loan-billing-system/internal/webhook"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+type webhookRepository struct {
+	DB *sql.DB
+}
+
+func NewWebhookRepository(DB *sql.DB) webhook.WebhookRepository {
+	return &webhookRepository{
+		DB: DB,
+	}
+}
+
+const eventColumns = `id, provider, event_id, event_type, payload, status, loan_id, amount, payment_id, error,
+                      attempts, received_at, processed_at`
+
+func scanEvent(row interface{ Scan(...any) error }, e *models.WebhookEvent) error {
+	var eventType, eventErr sql.NullString
+	err := row.Scan(
+		&e.ID,
+		&e.Provider,
+		&e.EventID,
+		&eventType,
+		&e.Payload,
+		&e.Status,
+		&e.LoanID,
+		&e.Amount,
+		&e.PaymentID,
+		&eventErr,
+		&e.Attempts,
+		&e.ReceivedAt,
+		&e.ProcessedAt,
+	)
+	e.EventType, e.Error = eventType.String, eventErr.String
+	return err
+}
+
+// CreateEvent implements [webhook.WebhookRepository].
+func (r *webhookRepository) CreateEvent(ctx context.Context, e *models.WebhookEvent) error {
+	query := `INSERT INTO webhook_events (provider, event_id, event_type, payload, status)
+              VALUES ($1, $2, NULLIF($3, ''), $4, $5)
+              ON CONFLICT (provider, event_id) DO NOTHING
+              RETURNING id, received_at`
+	return postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, e.Provider, e.EventID, e.EventType, e.Payload, e.Status).
+		Scan(&e.ID, &e.ReceivedAt)
+}
+
+func (r *webhookRepository) UpdateEvent(ctx context.Context, e *models.WebhookEvent) error {
+	query := `UPDATE webhook_events
+              SET event_type = NULLIF($2, ''), status = $3, loan_id = $4, amount = $5, payment_id = $6,
+                  error = NULLIF($7, ''), attempts = $8, processed_at = $9
+              WHERE id = $1`
+	_, err := postgres.Conn(ctx, r.DB).ExecContext(ctx, query, e.ID, e.EventType, e.Status, e.LoanID, e.Amount,
+		e.PaymentID, e.Error, e.Attempts, e.ProcessedAt)
+	if err != nil {
+		return fmt.Errorf("update webhook event: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) GetEvent(ctx context.Context, id int) (*models.WebhookEvent, error) {
+	return r.getEvent(ctx, `WHERE id = $1`, id)
+}
+
+func (r *webhookRepository) GetEventByEventID(ctx context.Context, provider, eventID string) (*models.WebhookEvent, error) {
+	return r.getEvent(ctx, `WHERE provider = $1 AND event_id = $2`, provider, eventID)
+}
+
+func (r *webhookRepository) getEvent(ctx context.Context, where string, args ...any) (*models.WebhookEvent, error) {
+	var e models.WebhookEvent
+	query := `SELECT ` + eventColumns + ` FROM webhook_events ` + where
+	if err := scanEvent(postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, args...), &e); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query webhook event: %w", err)
+	}
+	return &e, nil
+}
+
+// ListEvents implements [webhook.WebhookRepository].
+func (r *webhookRepository) ListEvents(ctx context.Context, provider, status string, limit int) ([]models.WebhookEvent, error) {
+	query := `SELECT ` + eventColumns + ` FROM webhook_events
+              WHERE ($1 = '' OR provider = $1) AND ($2 = '' OR status = $2)
+              ORDER BY received_at DESC, id DESC
+              LIMIT $3`
+	rows, err := postgres.Conn(ctx, r.DB).QueryContext(ctx, query, provider, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query webhook events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.WebhookEvent
+	for rows.Next() {
+		var e models.WebhookEvent
+		if err := scanEvent(rows, &e); err != nil {
+			return nil, fmt.Errorf("scan webhook event: %w", err)
+		}
+		events = append(events, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return events, nil
+}
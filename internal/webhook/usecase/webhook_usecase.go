@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/loan"
+	"github.com/evrintobing17/loan-billing-system/internal/payment"
+	"github.com/evrintobing17/loan-billing-system/internal/webhook"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+)
+
+// listLimit caps ListEvents; older events are still replayable by ID.
+const listLimit = 200
+
+type webhookUseCase struct {
+	webhookRepo webhook.WebhookRepository
+	paymentUC   payment.PaymentUsecase
+	clock       clock.Clock
+	providers   map[string]webhook.Provider
+	secrets     map[string]string
+	tolerance   time.Duration
+	maxBackdate int
+	currency    string
+}
+
+// NewWebhookUseCase builds the webhook ingestion. A provider is enabled when
+// it has both an adapter and a secret. Requests signed more than tolerance
+// away from now are rejected as possible replays. A payment taken more than
+// maxBackdate days before today, or in a currency other than the loans'
+// currency, is not made; it fails for an admin to look into.
+func NewWebhookUseCase(wr webhook.WebhookRepository, puc payment.PaymentUsecase, clk clock.Clock,
+	providers map[string]webhook.Provider, secrets map[string]string, tolerance time.Duration,
+	maxBackdate int, currency string) webhook.WebhookUsecase {
+	return &webhookUseCase{
+		webhookRepo: wr,
+		paymentUC:   puc,
+		clock:       clk,
+		providers:   providers,
+		secrets:     secrets,
+		tolerance:   tolerance,
+		maxBackdate: maxBackdate,
+		currency:    currency,
+	}
+}
+
+// Receive implements [webhook.WebhookUsecase]. Business failures such as an
+// amount that does not match what is due are recorded on the event and not
+// returned, so the provider does not keep retrying them; they are replayed
+// by hand. Other errors are returned so the provider retries.
+func (uc *webhookUseCase) Receive(ctx context.Context, provider string, header http.Header, body []byte) (*models.WebhookEvent, error) {
+	adapter, secret := uc.providers[provider], uc.secrets[provider]
+	if adapter == nil || secret == "" {
+		return nil, webhook.ErrUnknownProvider.Withf("payment provider %q is not configured", provider)
+	}
+
+	signedAt, err := adapter.Verify(header, body, secret)
+	if err != nil {
+		return nil, webhook.ErrInvalidSignature.Withf("%s", err.Error())
+	}
+	if skew := uc.clock.Now().Sub(signedAt); skew > uc.tolerance || skew < -uc.tolerance {
+		return nil, webhook.ErrInvalidSignature.Withf("webhook timestamp is outside the %s tolerance", uc.tolerance)
+	}
+
+	n, err := adapter.Parse(body)
+	if err != nil {
+		return nil, webhook.ErrInvalidPayload.Withf("%s", err.Error())
+	}
+
+	event := &models.WebhookEvent{
+		Provider:  provider,
+		EventID:   n.EventID,
+		EventType: n.Type,
+		Payload:   string(body),
+		Status:    models.WebhookEventReceived,
+	}
+	if err := uc.webhookRepo.CreateEvent(ctx, event); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		// A redelivery; only an event that has not gone through is retried.
+		if event, err = uc.webhookRepo.GetEventByEventID(ctx, provider, n.EventID); err != nil {
+			return nil, err
+		}
+		if event.Status != models.WebhookEventReceived && event.Status != models.WebhookEventFailed {
+			return event, nil
+		}
+	}
+	return event, uc.process(ctx, event, n)
+}
+
+func (uc *webhookUseCase) ListEvents(ctx context.Context, provider, status string) ([]models.WebhookEvent, error) {
+	return uc.webhookRepo.ListEvents(ctx, provider, status, listLimit)
+}
+
+func (uc *webhookUseCase) GetEvent(ctx context.Context, id int) (*models.WebhookEvent, error) {
+	event, err := uc.webhookRepo.GetEvent(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, webhook.ErrEventNotFound.Wrap(err)
+		}
+		return nil, err
+	}
+	return event, nil
+}
+
+// Replay implements [webhook.WebhookUsecase]. The payload is not verified
+// again: it was when it arrived, and the timestamp has expired since.
+func (uc *webhookUseCase) Replay(ctx context.Context, id int) (*models.WebhookEvent, error) {
+	event, err := uc.GetEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status == models.WebhookEventProcessed {
+		return nil, webhook.ErrEventProcessed
+	}
+	adapter := uc.providers[event.Provider]
+	if adapter == nil {
+		return nil, webhook.ErrUnknownProvider.Withf("payment provider %q is not configured", event.Provider)
+	}
+	n, err := adapter.Parse([]byte(event.Payload))
+	if err != nil {
+		return nil, webhook.ErrInvalidPayload.Withf("%s", err.Error())
+	}
+	if err := uc.process(ctx, event, n); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// process posts the payment a notification reports, keyed on the provider's
+// event ID, and records the outcome on the event.
+func (uc *webhookUseCase) process(ctx context.Context, event *models.WebhookEvent, n *webhook.Notification) error {
+	event.EventType = n.Type
+	event.Attempts++
+	event.Error = ""
+	now := uc.clock.Now()
+	event.ProcessedAt = &now
+
+	if !n.Payment {
+		event.Status = models.WebhookEventIgnored
+		return uc.webhookRepo.UpdateEvent(ctx, event)
+	}
+	event.Amount = &n.Amount
+
+	p, err := uc.pay(ctx, event.Provider+":"+n.EventID, n)
+	if err != nil {
+		event.Status = models.WebhookEventFailed
+		event.Error = err.Error()
+		// The loan is referenced only once it is known to exist; the ID a
+		// provider sends for an unknown loan stays in the payload and error.
+		if !errors.Is(err, loan.ErrNotFound) && !errors.Is(err, webhook.ErrPaidTooLongAgo) {
+			event.LoanID = &n.LoanID
+		}
+		if uerr := uc.webhookRepo.UpdateEvent(ctx, event); uerr != nil {
+			return uerr
+		}
+		if e, ok := apperror.Lookup(err); ok && e.Kind != apperror.Internal {
+			return nil
+		}
+		return err
+	}
+	event.Status = models.WebhookEventProcessed
+	event.LoanID, event.PaymentID = &p.LoanID, &p.ID
+	return uc.webhookRepo.UpdateEvent(ctx, event)
+}
+
+// pay makes the payment, or finds the one an earlier attempt made but failed
+// to record. A payment the provider took before today is made as of the day
+// it was taken, up to maxBackdate days back.
+func (uc *webhookUseCase) pay(ctx context.Context, key string, n *webhook.Notification) (*models.Payment, error) {
+	if n.Currency != "" && !strings.EqualFold(n.Currency, uc.currency) {
+		return nil, webhook.ErrCurrencyMismatch.Withf("payment in %s, loans are in %s", n.Currency, uc.currency)
+	}
+	if !n.PaidAt.IsZero() {
+		today := clock.Today(ctx, uc.clock)
+		if paidOn := clock.Date(n.PaidAt.In(uc.clock.Location())); paidOn.Before(today) {
+			if paidOn.Before(today.AddDate(0, 0, -uc.maxBackdate)) {
+				return nil, webhook.ErrPaidTooLongAgo.Withf("payment taken on %s is more than %d days ago",
+					paidOn.Format("2006-01-02"), uc.maxBackdate)
+			}
+			ctx = clock.WithAsOfDate(ctx, paidOn)
+		}
+	}
+	return uc.paymentUC.MakePayment(ctx, n.LoanID, n.Amount, key)
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/internal/webhook"
+	"github.com/evrintobing17/loan-billing-system/internal/webhook/provider"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+)
+
+// TestReceiveRejects covers the checks made before anything is stored, so
+// no repository is needed.
+func TestReceiveRejects(t *testing.T) {
+	const secret = "whsec_test"
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	body := []byte(`{"id":"evt_1","type":"payment.succeeded","data":{"loan_id":12,"amount":1}}`)
+	signed := func(at time.Time, key string) http.Header {
+		timestamp := strconv.FormatInt(at.Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(timestamp + "."))
+		mac.Write(body)
+		header := http.Header{}
+		header.Set("X-Webhook-Timestamp", timestamp)
+		header.Set("X-Webhook-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		return header
+	}
+
+	uc := NewWebhookUseCase(nil, nil, clock.NewFixed(now),
+		map[string]webhook.Provider{"generic": provider.NewGeneric(), "stripe": provider.NewStripe()},
+		map[string]string{"generic": secret}, 5*time.Minute, 7, "IDR")
+	tests := []struct {
+		name     string
+		provider string
+		header   http.Header
+		wantErr  error
+	}{
+		{name: "unknown provider", provider: "paypal", header: signed(now, secret), wantErr: webhook.ErrUnknownProvider},
+		{name: "provider without a secret", provider: "stripe", header: signed(now, secret), wantErr: webhook.ErrUnknownProvider},
+		{name: "wrong secret", provider: "generic", header: signed(now, "whsec_other"), wantErr: webhook.ErrInvalidSignature},
+		{name: "signed too long ago", provider: "generic", header: signed(now.Add(-5*time.Minute-time.Second), secret), wantErr: webhook.ErrInvalidSignature},
+		{name: "signed too far ahead", provider: "generic", header: signed(now.Add(5*time.Minute+time.Second), secret), wantErr: webhook.ErrInvalidSignature},
+		{name: "unsigned", provider: "generic", header: http.Header{}, wantErr: webhook.ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.Receive(context.Background(), tt.provider, tt.header, body)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Receive() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
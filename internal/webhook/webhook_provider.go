@@ -0,0 +1,30 @@
+package webhook
+
+import (
+	"net/http"
+	"time"
+)
+
+// Provider adapts one payment processor's webhooks to payments.
+type Provider interface {
+	// Verify checks the request signature against the provider's secret and
+	// returns the time the provider signed the request.
+	Verify(header http.Header, body []byte, secret string) (time.Time, error)
+	// Parse maps a payload to a notification. Events that are not successful
+	// payments come back with Payment false.
+	Parse(body []byte) (*Notification, error)
+}
+
+// Notification is what the system needs from a provider event.
+type Notification struct {
+	EventID string
+	Type    string
+	Payment bool
+	LoanID  int
+	Amount  float64
+	// Currency is the upper-case ISO 4217 code of Amount; empty if the
+	// provider does not say, in which case the loan currency is assumed.
+	Currency string
+	// PaidAt is when the borrower paid; zero if the provider does not say.
+	PaidAt time.Time
+}
@@ -0,0 +1,18 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type WebhookRepository interface {
+	// CreateEvent stores a new event. It returns sql.ErrNoRows when the
+	// provider sent the event before.
+	CreateEvent(ctx context.Context, event *models.WebhookEvent) error
+	UpdateEvent(ctx context.Context, event *models.WebhookEvent) error
+	GetEvent(ctx context.Context, id int) (*models.WebhookEvent, error)
+	GetEventByEventID(ctx context.Context, provider, eventID string) (*models.WebhookEvent, error)
+	// ListEvents returns the newest events first; empty filters match all.
+	ListEvents(ctx context.Context, provider, status string, limit int) ([]models.WebhookEvent, error)
+}
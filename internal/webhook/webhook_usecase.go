@@ -0,0 +1,21 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+// WebhookUsecase ingests payment processor notifications and posts the
+// payments they report.
+type WebhookUsecase interface {
+	// Receive verifies, stores and processes a notification. An event the
+	// provider delivered before is not processed again unless it failed.
+	Receive(ctx context.Context, provider string, header http.Header, body []byte) (*models.WebhookEvent, error)
+	ListEvents(ctx context.Context, provider, status string) ([]models.WebhookEvent, error)
+	GetEvent(ctx context.Context, id int) (*models.WebhookEvent, error)
+	// Replay processes a stored event again from its payload, e.g. after the
+	// loan was fixed or the provider adapter updated.
+	Replay(ctx context.Context, id int) (*models.WebhookEvent, error)
+}
@@ -0,0 +1,11 @@
+package writeoff
+
+import "github.com/evrintobing17/loan-billing-system/pkg/apperror"
+
+var (
+	ErrRequestNotFound   = apperror.New(apperror.NotFound, "write_off_request_not_found", "no pending write-off request for this loan")
+	ErrRequestPending    = apperror.New(apperror.Conflict, "write_off_request_pending", "a write-off request is already pending for this loan")
+	ErrNotPending        = apperror.New(apperror.Conflict, "write_off_not_pending", "write-off request is no longer pending")
+	ErrNothingToWriteOff = apperror.New(apperror.Unprocessable, "nothing_to_write_off", "loan has no outstanding balance to write off")
+	ErrSameUser          = apperror.New(apperror.Forbidden, "write_off_same_user", "a write-off must be decided by a different user than the requester")
+)
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/evrintobing17/loan-billing-system/internal/writeoff"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/evrintobing17/loan-billing-system/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type WriteOffHandler struct {
+	writeOffUC writeoff.WriteOffUsecase
+}
+
+func NewWriteOffHandler(uc writeoff.WriteOffUsecase) *WriteOffHandler {
+	return &WriteOffHandler{writeOffUC: uc}
+}
+
+// RequestWriteOff godoc
+// @Summary Request a loan write-off
+// @Description Open a write-off request for an active loan (admin only). It takes effect once approved by a different user.
+// @Tags write-offs
+// @Accept json
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param X-User-ID header string true "Requesting user"
+// @Param request body models.WriteOffRequest true "Write-off reason"
+// @Success 201 {object} models.WriteOff
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /loans/{id}/write-off [post]
+func (h *WriteOffHandler) RequestWriteOff(c *gin.Context) {
+	loanID, actor, ok := loanAndActor(c)
+	if !ok {
+		return
+	}
+
+	var req models.WriteOffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Default(err, apperror.Invalid))
+		return
+	}
+
+	w, err := h.writeOffUC.RequestWriteOff(c.Request.Context(), loanID, req.Reason, actor)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, w)
+}
+
+// ApproveWriteOff godoc
+// @Summary Approve a pending loan write-off
+// @Description Write the loan off (admin only). The approver must differ from the requester. The loan's ledger receivables, principal and accrued interest, are charged to loss and become the written-off balance; later payments are booked as recoveries up to it.
+// @Tags write-offs
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param X-User-Key header string true "Approving user's key; refused with 403 unless USER_KEYS is configured"
+// @Success 200 {object} models.WriteOff
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /loans/{id}/write-off/approve [post]
+func (h *WriteOffHandler) ApproveWriteOff(c *gin.Context) {
+	loanID, actor, ok := loanAndActor(c)
+	if !ok {
+		return
+	}
+
+	w, err := h.writeOffUC.ApproveWriteOff(c.Request.Context(), loanID, actor)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, w)
+}
+
+// RejectWriteOff godoc
+// @Summary Reject a pending loan write-off
+// @Tags write-offs
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Param X-User-Key header string true "Rejecting user's key; refused with 403 unless USER_KEYS is configured"
+// @Success 200 {object} models.WriteOff
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /loans/{id}/write-off/reject [post]
+func (h *WriteOffHandler) RejectWriteOff(c *gin.Context) {
+	loanID, actor, ok := loanAndActor(c)
+	if !ok {
+		return
+	}
+
+	w, err := h.writeOffUC.RejectWriteOff(c.Request.Context(), loanID, actor)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, w)
+}
+
+// ListWriteOffs godoc
+// @Summary List write-off requests of a loan
+// @Tags write-offs
+// @Produce json
+// @Param id path int true "Loan ID"
+// @Success 200 {array} models.WriteOff
+// @Failure 404 {object} models.Problem
+// @Router /loans/{id}/write-offs [get]
+func (h *WriteOffHandler) ListWriteOffs(c *gin.Context) {
+	loanID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid loan id"))
+		return
+	}
+	writeOffs, err := h.writeOffUC.ListWriteOffs(c.Request.Context(), loanID)
+	if err != nil {
+		c.Error(apperror.Default(err, apperror.NotFound))
+		return
+	}
+	c.JSON(http.StatusOK, writeOffs)
+}
+
+func loanAndActor(c *gin.Context) (int, string, bool) {
+	loanID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(apperror.Invalidf("invalid loan id"))
+		return 0, "", false
+	}
+	actor := middleware.Actor(c)
+	if actor == "" {
+		c.Error(middleware.ErrActorRequired)
+		return 0, "", false
+	}
+	return loanID, actor, true
+}
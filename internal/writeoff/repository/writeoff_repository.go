@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evrintobing17/loan-billing-system/internal/writeoff"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+type writeOffRepository struct {
+	DB *sql.DB
+}
+
+func NewWriteOffRepository(DB *sql.DB) writeoff.WriteOffRepository {
+	return &writeOffRepository{
+		DB: DB,
+	}
+}
+
+const writeOffColumns = `id, loan_id, amount, reason, status, requested_by, requested_at, decided_by, decided_at`
+
+func scanWriteOff(row interface{ Scan(...any) error }, w *models.WriteOff) error {
+	var decidedBy sql.NullString
+	var decidedAt sql.NullTime
+	err := row.Scan(
+		&w.ID,
+		&w.LoanID,
+		&w.Amount,
+		&w.Reason,
+		&w.Status,
+		&w.RequestedBy,
+		&w.RequestedAt,
+		&decidedBy,
+		&decidedAt,
+	)
+	if err != nil {
+		return err
+	}
+	w.DecidedBy = decidedBy.String
+	if decidedAt.Valid {
+		w.DecidedAt = &decidedAt.Time
+	}
+	return nil
+}
+
+// Create implements [writeoff.WriteOffRepository].
+func (r *writeOffRepository) Create(ctx context.Context, w *models.WriteOff) error {
+	query := `INSERT INTO write_offs (loan_id, amount, reason, status, requested_by)
+              VALUES ($1, $2, $3, $4, $5) RETURNING id, requested_at`
+	return postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, w.LoanID, w.Amount, w.Reason, w.Status, w.RequestedBy).
+		Scan(&w.ID, &w.RequestedAt)
+}
+
+func (r *writeOffRepository) GetPendingByLoanID(ctx context.Context, loanID int) (*models.WriteOff, error) {
+	var w models.WriteOff
+	query := `SELECT ` + writeOffColumns + ` FROM write_offs WHERE loan_id = $1 AND status = $2`
+	err := scanWriteOff(postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, loanID, models.WriteOffStatusPending), &w)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query pending write-off: %w", err)
+	}
+	return &w, nil
+}
+
+func (r *writeOffRepository) GetByLoanID(ctx context.Context, loanID int) ([]models.WriteOff, error) {
+	query := `SELECT ` + writeOffColumns + ` FROM write_offs WHERE loan_id = $1 ORDER BY id`
+	rows, err := postgres.Conn(ctx, r.DB).QueryContext(ctx, query, loanID)
+	if err != nil {
+		return nil, fmt.Errorf("query write-offs: %w", err)
+	}
+	defer rows.Close()
+
+	var writeOffs []models.WriteOff
+	for rows.Next() {
+		var w models.WriteOff
+		if err := scanWriteOff(rows, &w); err != nil {
+			return nil, fmt.Errorf("scan write-off: %w", err)
+		}
+		writeOffs = append(writeOffs, w)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+	return writeOffs, nil
+}
+
+// Decide implements [writeoff.WriteOffRepository].
+func (r *writeOffRepository) Decide(ctx context.Context, w *models.WriteOff) error {
+	query := `UPDATE write_offs
+              SET status = $2, amount = $3, decided_by = $4, decided_at = CURRENT_TIMESTAMP
+              WHERE id = $1 AND status = 'pending'
+              RETURNING decided_at`
+	var decidedAt sql.NullTime
+	err := postgres.Conn(ctx, r.DB).QueryRowContext(ctx, query, w.ID, w.Status, w.Amount, w.DecidedBy).Scan(&decidedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return writeoff.ErrNotPending
+		}
+		return fmt.Errorf("decide write-off: %w", err)
+	}
+	w.DecidedAt = &decidedAt.Time
+	return nil
+}
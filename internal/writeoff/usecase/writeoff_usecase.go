@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/evrintobing17/loan-billing-system/internal/accounting"
+	"github.com/evrintobing17/loan-billing-system/internal/loan"
+	"github.com/evrintobing17/loan-billing-system/internal/writeoff"
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+type writeOffUseCase struct {
+	writeOffRepo writeoff.WriteOffRepository
+	loanRepo     loan.LoanRepository
+	loanUC       loan.LoanUsecase
+	accountingUC accounting.AccountingUsecase
+	tx           postgres.Transactor
+	clock        clock.Clock
+}
+
+func NewWriteOffUseCase(wr writeoff.WriteOffRepository, lr loan.LoanRepository, luc loan.LoanUsecase,
+	auc accounting.AccountingUsecase, tx postgres.Transactor, clk clock.Clock) writeoff.WriteOffUsecase {
+	return &writeOffUseCase{
+		writeOffRepo: wr,
+		loanRepo:     lr,
+		loanUC:       luc,
+		accountingUC: auc,
+		tx:           tx,
+		clock:        clk,
+	}
+}
+
+// RequestWriteOff opens a write-off request for an active loan. The amount
+// shown is the outstanding balance at request time; the final amount is
+// taken on approval.
+func (uc *writeOffUseCase) RequestWriteOff(ctx context.Context, loanID int, reason, requestedBy string) (*models.WriteOff, error) {
+	l, err := uc.loanRepo.GetByID(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	if l.Status != models.LoanStatusActive {
+		return nil, loan.ErrNotActive.Withf("only active loans can be written off")
+	}
+	if _, err := uc.writeOffRepo.GetPendingByLoanID(ctx, loanID); err == nil {
+		return nil, writeoff.ErrRequestPending
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	outstanding, err := uc.loanUC.GetOutstanding(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	if outstanding <= 0 {
+		return nil, writeoff.ErrNothingToWriteOff
+	}
+	w := &models.WriteOff{
+		LoanID:      loanID,
+		Amount:      outstanding,
+		Reason:      reason,
+		Status:      models.WriteOffStatusPending,
+		RequestedBy: requestedBy,
+	}
+	if err := uc.writeOffRepo.Create(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// ApproveWriteOff executes a pending write-off. The approver must not be the
+// requester. The loan's receivables in the ledger are charged to loan loss
+// expense and become its written-off balance, so unearned interest and fees
+// still to fall due are not written off. The loan is locked
+// and checked again, since it may have been paid off, refinanced or
+// restructured since the request.
+func (uc *writeOffUseCase) ApproveWriteOff(ctx context.Context, loanID int, approvedBy string) (*models.WriteOff, error) {
+	w, err := uc.pendingRequest(ctx, loanID, approvedBy)
+	if err != nil {
+		return nil, err
+	}
+	today := clock.Today(ctx, uc.clock)
+
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		l, err := uc.loanRepo.GetForUpdate(ctx, loanID)
+		if err != nil {
+			return err
+		}
+		if l.Status != models.LoanStatusActive {
+			return loan.ErrNotActive.Withf("only active loans can be written off")
+		}
+		amount, err := uc.accountingUC.RecordWriteOff(ctx, loanID, today)
+		if err != nil {
+			return err
+		}
+		if amount <= 0 {
+			return writeoff.ErrNothingToWriteOff
+		}
+
+		w.Status = models.WriteOffStatusApproved
+		w.Amount = amount
+		w.DecidedBy = approvedBy
+		if err := uc.writeOffRepo.Decide(ctx, w); err != nil {
+			return err
+		}
+		return uc.loanRepo.WriteOff(ctx, loanID, amount, today)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (uc *writeOffUseCase) RejectWriteOff(ctx context.Context, loanID int, rejectedBy string) (*models.WriteOff, error) {
+	w, err := uc.pendingRequest(ctx, loanID, rejectedBy)
+	if err != nil {
+		return nil, err
+	}
+	w.Status = models.WriteOffStatusRejected
+	w.DecidedBy = rejectedBy
+	if err := uc.writeOffRepo.Decide(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (uc *writeOffUseCase) ListWriteOffs(ctx context.Context, loanID int) ([]models.WriteOff, error) {
+	if _, err := uc.loanRepo.GetByID(ctx, loanID); err != nil {
+		return nil, err
+	}
+	return uc.writeOffRepo.GetByLoanID(ctx, loanID)
+}
+
+// pendingRequest returns the loan's pending request, enforcing that the
+// deciding user is not the requester.
+func (uc *writeOffUseCase) pendingRequest(ctx context.Context, loanID int, decidedBy string) (*models.WriteOff, error) {
+	w, err := uc.writeOffRepo.GetPendingByLoanID(ctx, loanID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, writeoff.ErrRequestNotFound
+		}
+		return nil, err
+	}
+	if w.RequestedBy == decidedBy {
+		return nil, writeoff.ErrSameUser
+	}
+	return w, nil
+}
@@ -0,0 +1,15 @@
+package writeoff
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type WriteOffRepository interface {
+	Create(ctx context.Context, writeOff *models.WriteOff) error
+	GetPendingByLoanID(ctx context.Context, loanID int) (*models.WriteOff, error)
+	GetByLoanID(ctx context.Context, loanID int) ([]models.WriteOff, error)
+	// Decide stores the approval or rejection of a pending request.
+	Decide(ctx context.Context, writeOff *models.WriteOff) error
+}
@@ -0,0 +1,14 @@
+package writeoff
+
+import (
+	"context"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+)
+
+type WriteOffUsecase interface {
+	RequestWriteOff(ctx context.Context, loanID int, reason, requestedBy string) (*models.WriteOff, error)
+	ApproveWriteOff(ctx context.Context, loanID int, approvedBy string) (*models.WriteOff, error)
+	RejectWriteOff(ctx context.Context, loanID int, rejectedBy string) (*models.WriteOff, error)
+	ListWriteOffs(ctx context.Context, loanID int) ([]models.WriteOff, error)
+}
@@ -0,0 +1,4 @@
+DROP TABLE payment_installments;
+DROP TABLE payments;
+DROP TABLE installments;
+DROP TABLE loans;
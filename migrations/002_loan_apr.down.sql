@@ -0,0 +1,3 @@
+ALTER TABLE loans
+    DROP COLUMN apr,
+    DROP COLUMN effective_annual_rate;
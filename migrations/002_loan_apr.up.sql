@@ -0,0 +1,3 @@
+ALTER TABLE loans
+    ADD COLUMN apr                   NUMERIC(9,4) NOT NULL DEFAULT 0,
+    ADD COLUMN effective_annual_rate NUMERIC(9,4) NOT NULL DEFAULT 0;
@@ -0,0 +1,15 @@
+DROP TABLE payment_charges;
+DROP TABLE loan_charges;
+
+ALTER TABLE installments
+    DROP COLUMN principal_amount,
+    DROP COLUMN interest_amount,
+    DROP COLUMN fee_amount;
+
+ALTER TABLE loans
+    DROP COLUMN product_id,
+    DROP COLUMN origination_fee,
+    DROP COLUMN net_disbursement;
+
+DROP TABLE product_fees;
+DROP TABLE loan_products;
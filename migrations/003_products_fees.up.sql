@@ -0,0 +1,51 @@
+CREATE TABLE loan_products (
+    id              SERIAL PRIMARY KEY,
+    code            VARCHAR(50) NOT NULL UNIQUE,
+    name            VARCHAR(255) NOT NULL,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE product_fees (
+    id              SERIAL PRIMARY KEY,
+    product_id      INT NOT NULL REFERENCES loan_products(id) ON DELETE CASCADE,
+    fee_type        VARCHAR(20) NOT NULL CHECK (fee_type IN ('origination', 'servicing')),
+    calculation     VARCHAR(20) NOT NULL CHECK (calculation IN ('flat', 'percent')),
+    amount          NUMERIC(15,4) NOT NULL,
+    treatment       VARCHAR(20) NOT NULL CHECK (treatment IN ('deducted', 'financed', 'installment', 'separate')),
+    frequency_weeks INT NOT NULL DEFAULT 0
+);
+
+ALTER TABLE loans
+    ADD COLUMN product_id       INT REFERENCES loan_products(id),
+    ADD COLUMN origination_fee  NUMERIC(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN net_disbursement NUMERIC(15,2) NOT NULL DEFAULT 0;
+
+UPDATE loans SET net_disbursement = principal;
+
+ALTER TABLE installments
+    ADD COLUMN principal_amount NUMERIC(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN interest_amount  NUMERIC(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN fee_amount       NUMERIC(15,2) NOT NULL DEFAULT 0;
+
+UPDATE installments i
+SET principal_amount = l.principal / l.term_weeks,
+    interest_amount  = i.amount - l.principal / l.term_weeks
+FROM loans l
+WHERE l.id = i.loan_id;
+
+CREATE TABLE loan_charges (
+    id              SERIAL PRIMARY KEY,
+    loan_id         INT NOT NULL REFERENCES loans(id) ON DELETE CASCADE,
+    fee_type        VARCHAR(20) NOT NULL,
+    due_date        DATE NOT NULL,
+    amount          NUMERIC(15,2) NOT NULL,
+    paid            BOOLEAN DEFAULT FALSE
+);
+
+CREATE TABLE payment_charges (
+    payment_id      INT NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+    charge_id       INT NOT NULL REFERENCES loan_charges(id) ON DELETE CASCADE,
+    PRIMARY KEY (payment_id, charge_id)
+);
+
+CREATE INDEX idx_loan_charges_loan_due ON loan_charges(loan_id, due_date);
@@ -0,0 +1,6 @@
+DROP TABLE disbursements;
+
+ALTER TABLE loans
+    DROP COLUMN status,
+    DROP COLUMN disbursed_amount,
+    DROP COLUMN first_disbursement_date;
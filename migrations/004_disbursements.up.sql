@@ -0,0 +1,20 @@
+ALTER TABLE loans
+    ADD COLUMN status                  VARCHAR(30) NOT NULL DEFAULT 'active',
+    ADD COLUMN disbursed_amount        NUMERIC(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN first_disbursement_date DATE;
+
+-- Loans booked before disbursement tracking existed were treated as paid out.
+UPDATE loans SET disbursed_amount = net_disbursement, first_disbursement_date = start_date;
+
+CREATE TABLE disbursements (
+    id                SERIAL PRIMARY KEY,
+    loan_id           INT NOT NULL REFERENCES loans(id) ON DELETE CASCADE,
+    amount            NUMERIC(15,2) NOT NULL,
+    disbursement_date DATE NOT NULL,
+    channel           VARCHAR(50) NOT NULL,
+    reference         VARCHAR(255) NOT NULL,
+    created_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(channel, reference)
+);
+
+CREATE INDEX idx_disbursements_loan ON disbursements(loan_id);
@@ -0,0 +1,37 @@
+CREATE TABLE gl_accounts (
+    code            VARCHAR(50) PRIMARY KEY,
+    name            VARCHAR(255) NOT NULL,
+    account_type    VARCHAR(20) NOT NULL CHECK (account_type IN ('asset', 'liability', 'equity', 'income', 'expense'))
+);
+
+INSERT INTO gl_accounts (code, name, account_type) VALUES
+    ('CASH',                'Cash',                'asset'),
+    ('LOAN_RECEIVABLE',     'Loan receivable',     'asset'),
+    ('INTEREST_RECEIVABLE', 'Interest receivable', 'asset'),
+    ('INTEREST_INCOME',     'Interest income',     'income'),
+    ('FEE_INCOME',          'Fee income',          'income'),
+    ('SUSPENSE',            'Suspense',            'liability');
+
+CREATE TABLE journal_entries (
+    id              SERIAL PRIMARY KEY,
+    entry_date      DATE NOT NULL,
+    event_type      VARCHAR(50) NOT NULL,
+    loan_id         INT REFERENCES loans(id),
+    reference       VARCHAR(255) NOT NULL DEFAULT '',
+    description     TEXT NOT NULL DEFAULT '',
+    reversal_of     INT UNIQUE REFERENCES journal_entries(id),
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE journal_lines (
+    id              SERIAL PRIMARY KEY,
+    entry_id        INT NOT NULL REFERENCES journal_entries(id) ON DELETE CASCADE,
+    account_code    VARCHAR(50) NOT NULL REFERENCES gl_accounts(code),
+    debit           NUMERIC(15,2) NOT NULL DEFAULT 0,
+    credit          NUMERIC(15,2) NOT NULL DEFAULT 0,
+    CHECK (debit >= 0 AND credit >= 0 AND (debit = 0 OR credit = 0))
+);
+
+CREATE INDEX idx_journal_entries_loan ON journal_entries(loan_id);
+CREATE INDEX idx_journal_lines_entry ON journal_lines(entry_id);
+CREATE INDEX idx_journal_lines_account ON journal_lines(account_code);
@@ -0,0 +1 @@
+DROP TABLE interest_accruals;
@@ -0,0 +1,10 @@
+CREATE TABLE interest_accruals (
+    id              SERIAL PRIMARY KEY,
+    loan_id         INT NOT NULL REFERENCES loans(id) ON DELETE CASCADE,
+    accrual_date    DATE NOT NULL,
+    amount          NUMERIC(15,2) NOT NULL,
+    days_past_due   INT NOT NULL DEFAULT 0,
+    non_accrual     BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(loan_id, accrual_date)
+);
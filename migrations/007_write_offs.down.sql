@@ -0,0 +1,10 @@
+DROP TABLE write_offs;
+
+DELETE FROM gl_accounts WHERE code IN ('LOAN_LOSS_EXPENSE', 'RECOVERY_INCOME');
+
+ALTER TABLE payments DROP COLUMN payment_type;
+
+ALTER TABLE loans
+    DROP COLUMN written_off_amount,
+    DROP COLUMN written_off_date,
+    DROP COLUMN recovered_amount;
@@ -0,0 +1,26 @@
+ALTER TABLE loans
+    ADD COLUMN written_off_amount NUMERIC(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN written_off_date   DATE,
+    ADD COLUMN recovered_amount   NUMERIC(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE payments
+    ADD COLUMN payment_type VARCHAR(20) NOT NULL DEFAULT 'installment';
+
+INSERT INTO gl_accounts (code, name, account_type) VALUES
+    ('LOAN_LOSS_EXPENSE', 'Loan loss expense', 'expense'),
+    ('RECOVERY_INCOME',   'Recovery income',   'income');
+
+CREATE TABLE write_offs (
+    id              SERIAL PRIMARY KEY,
+    loan_id         INT NOT NULL REFERENCES loans(id) ON DELETE CASCADE,
+    amount          NUMERIC(15,2) NOT NULL DEFAULT 0,
+    reason          TEXT NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'pending',
+    requested_by    VARCHAR(255) NOT NULL,
+    requested_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    decided_by      VARCHAR(255),
+    decided_at      TIMESTAMP
+);
+
+-- At most one open request per loan.
+CREATE UNIQUE INDEX idx_write_offs_pending ON write_offs(loan_id) WHERE status = 'pending';
@@ -0,0 +1,19 @@
+DROP TABLE loan_restructures;
+
+-- Lossy: only the current schedule of each loan survives the rollback.
+DELETE FROM loan_charges WHERE closed OR schedule_version <> (SELECT schedule_version FROM loans WHERE loans.id = loan_charges.loan_id);
+DELETE FROM installments WHERE closed OR schedule_version <> (SELECT schedule_version FROM loans WHERE loans.id = installments.loan_id);
+
+ALTER TABLE loan_charges
+    DROP COLUMN schedule_version,
+    DROP COLUMN closed;
+
+ALTER TABLE installments DROP CONSTRAINT installments_loan_id_schedule_version_week_number_key;
+ALTER TABLE installments
+    DROP COLUMN schedule_version,
+    DROP COLUMN closed;
+ALTER TABLE installments ADD UNIQUE (loan_id, week_number);
+
+ALTER TABLE loans
+    DROP COLUMN schedule_version,
+    DROP COLUMN restructured;
@@ -0,0 +1,35 @@
+ALTER TABLE loans
+    ADD COLUMN schedule_version INT NOT NULL DEFAULT 1,
+    ADD COLUMN restructured     BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Superseded schedules are kept for audit; their open items are closed.
+ALTER TABLE installments
+    ADD COLUMN schedule_version INT NOT NULL DEFAULT 1,
+    ADD COLUMN closed           BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE installments DROP CONSTRAINT installments_loan_id_week_number_key;
+ALTER TABLE installments ADD UNIQUE (loan_id, schedule_version, week_number);
+
+ALTER TABLE loan_charges
+    ADD COLUMN schedule_version INT NOT NULL DEFAULT 1,
+    ADD COLUMN closed           BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE loan_restructures (
+    id                  SERIAL PRIMARY KEY,
+    loan_id             INT NOT NULL REFERENCES loans(id) ON DELETE CASCADE,
+    from_version        INT NOT NULL,
+    to_version          INT NOT NULL,
+    restructure_date    DATE NOT NULL,
+    term_weeks          INT NOT NULL,
+    interest_rate       NUMERIC(5,2) NOT NULL,
+    holiday_weeks       INT NOT NULL DEFAULT 0,
+    remaining_principal NUMERIC(15,2) NOT NULL,
+    capitalised_arrears NUMERIC(15,2) NOT NULL DEFAULT 0,
+    new_principal       NUMERIC(15,2) NOT NULL,
+    weekly_amount       NUMERIC(15,2) NOT NULL,
+    recognised_interest NUMERIC(15,2) NOT NULL DEFAULT 0,
+    reason              TEXT NOT NULL,
+    requested_by        VARCHAR(255) NOT NULL,
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(loan_id, to_version)
+);
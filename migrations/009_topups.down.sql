@@ -0,0 +1,5 @@
+DROP TABLE loan_topups;
+
+ALTER TABLE loans
+    DROP COLUMN refinances_loan_id,
+    DROP COLUMN refinanced_by_loan_id;
@@ -0,0 +1,16 @@
+ALTER TABLE loans
+    ADD COLUMN refinances_loan_id    INT REFERENCES loans(id),
+    ADD COLUMN refinanced_by_loan_id INT REFERENCES loans(id);
+
+CREATE TABLE loan_topups (
+    id                    SERIAL PRIMARY KEY,
+    loan_id               INT NOT NULL REFERENCES loans(id) ON DELETE CASCADE,
+    previous_loan_id      INT NOT NULL UNIQUE REFERENCES loans(id),
+    settlement_payment_id INT NOT NULL REFERENCES payments(id),
+    settlement_amount     NUMERIC(15,2) NOT NULL,
+    topup_amount          NUMERIC(15,2) NOT NULL,
+    net_new_cash          NUMERIC(15,2) NOT NULL,
+    created_at            TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_loan_topups_loan ON loan_topups(loan_id);
@@ -0,0 +1,2 @@
+DROP TABLE guarantors;
+DROP TABLE collateral;
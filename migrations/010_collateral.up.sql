@@ -0,0 +1,26 @@
+CREATE TABLE collateral (
+    id              SERIAL PRIMARY KEY,
+    loan_id         INT NOT NULL REFERENCES loans(id) ON DELETE CASCADE,
+    collateral_type VARCHAR(30) NOT NULL,
+    description     TEXT NOT NULL DEFAULT '',
+    valuation       NUMERIC(15,2) NOT NULL,
+    valuation_date  DATE NOT NULL,
+    lien_status     VARCHAR(20) NOT NULL CHECK (lien_status IN ('pending', 'registered', 'released')),
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE guarantors (
+    id              SERIAL PRIMARY KEY,
+    loan_id         INT NOT NULL REFERENCES loans(id) ON DELETE CASCADE,
+    name            VARCHAR(255) NOT NULL,
+    role            VARCHAR(20) NOT NULL CHECK (role IN ('guarantor', 'co_borrower')),
+    id_number       VARCHAR(100) NOT NULL DEFAULT '',
+    phone           VARCHAR(50) NOT NULL DEFAULT '',
+    liability_share NUMERIC(5,2) NOT NULL CHECK (liability_share > 0 AND liability_share <= 100),
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_collateral_loan ON collateral(loan_id);
+CREATE INDEX idx_guarantors_loan ON guarantors(loan_id);
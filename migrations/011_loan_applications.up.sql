@@ -0,0 +1,20 @@
+CREATE TABLE loan_applications (
+    id              SERIAL PRIMARY KEY,
+    principal       NUMERIC(15,2) NOT NULL,
+    interest_rate   NUMERIC(5,2) NOT NULL,
+    term_weeks      INT NOT NULL,
+    start_date      DATE NOT NULL,
+    product_code    VARCHAR(50) NOT NULL DEFAULT '',
+    status          VARCHAR(20) NOT NULL CHECK (status IN ('submitted', 'under_review', 'approved', 'rejected')),
+    submitted_by    VARCHAR(255) NOT NULL,
+    reviewed_by     VARCHAR(255),
+    decided_by      VARCHAR(255),
+    decided_role    VARCHAR(50),
+    decision_note   TEXT NOT NULL DEFAULT '',
+    loan_id         INT REFERENCES loans(id),
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    decided_at      TIMESTAMP
+);
+
+CREATE INDEX idx_loan_applications_status ON loan_applications(status);
@@ -0,0 +1,8 @@
+DROP TABLE credit_decisions;
+
+ALTER TABLE loan_applications
+    DROP COLUMN borrower_id,
+    DROP COLUMN credit_outcome;
+
+DROP INDEX idx_loans_borrower;
+ALTER TABLE loans DROP COLUMN borrower_id;
@@ -0,0 +1,18 @@
+ALTER TABLE loans ADD COLUMN borrower_id VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE loan_applications
+    ADD COLUMN borrower_id    VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN credit_outcome VARCHAR(20) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_loans_borrower ON loans(borrower_id);
+
+CREATE TABLE credit_decisions (
+    id              SERIAL PRIMARY KEY,
+    application_id  INT NOT NULL REFERENCES loan_applications(id) ON DELETE CASCADE,
+    engine          VARCHAR(50) NOT NULL,
+    outcome         VARCHAR(20) NOT NULL CHECK (outcome IN ('approve', 'refer', 'decline')),
+    inputs          JSONB NOT NULL,
+    reasons         JSONB NOT NULL DEFAULT '[]',
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_credit_decisions_application ON credit_decisions(application_id);
@@ -0,0 +1,6 @@
+DROP TABLE borrower_limits;
+
+ALTER TABLE loan_products
+    DROP COLUMN max_borrower_outstanding,
+    DROP COLUMN max_borrower_active_loans,
+    DROP COLUMN block_delinquent;
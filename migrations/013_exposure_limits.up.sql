@@ -0,0 +1,15 @@
+-- Per-product exposure limits on each borrower; zero means no limit.
+ALTER TABLE loan_products
+    ADD COLUMN max_borrower_outstanding  NUMERIC(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN max_borrower_active_loans INT NOT NULL DEFAULT 0,
+    ADD COLUMN block_delinquent          BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Borrower-specific limits replacing the configured defaults.
+CREATE TABLE borrower_limits (
+    borrower_id      VARCHAR(100) PRIMARY KEY,
+    max_outstanding  NUMERIC(15,2) NOT NULL DEFAULT 0,
+    max_active_loans INT NOT NULL DEFAULT 0,
+    block_delinquent BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_by       VARCHAR(255) NOT NULL,
+    updated_at       TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,5 @@
+DROP INDEX idx_payments_loan;
+
+ALTER TABLE payments
+    DROP COLUMN reversed_at,
+    DROP COLUMN reversal_reason;
@@ -0,0 +1,7 @@
+-- A reversed payment keeps its row and links for audit; its installments and
+-- charges are reopened.
+ALTER TABLE payments
+    ADD COLUMN reversed_at     TIMESTAMP,
+    ADD COLUMN reversal_reason TEXT;
+
+CREATE INDEX idx_payments_loan ON payments(loan_id);
@@ -0,0 +1,2 @@
+DROP TABLE loan_import_rows;
+DROP TABLE loan_import_jobs;
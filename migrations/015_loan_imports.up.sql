@@ -0,0 +1,26 @@
+CREATE TABLE loan_import_jobs (
+    id          SERIAL PRIMARY KEY,
+    dry_run     BOOLEAN NOT NULL DEFAULT FALSE,
+    status      VARCHAR(20) NOT NULL DEFAULT 'running',
+    total_rows  INT NOT NULL DEFAULT 0,
+    succeeded   INT NOT NULL DEFAULT 0,
+    failed      INT NOT NULL DEFAULT 0,
+    error       TEXT,
+    created_by  VARCHAR(255) NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE TABLE loan_import_rows (
+    job_id            INT NOT NULL REFERENCES loan_import_jobs(id) ON DELETE CASCADE,
+    line              INT NOT NULL,
+    ref               VARCHAR(100) NOT NULL,
+    status            VARCHAR(20) NOT NULL,
+    loan_id           INT REFERENCES loans(id),
+    payments_replayed INT NOT NULL DEFAULT 0,
+    error             TEXT,
+    PRIMARY KEY (job_id, line)
+);
+
+-- A legacy loan is imported at most once.
+CREATE UNIQUE INDEX idx_loan_import_rows_ref ON loan_import_rows(ref) WHERE status = 'imported';
@@ -0,0 +1,2 @@
+DROP TABLE statement_entries;
+DROP TABLE bank_statements;
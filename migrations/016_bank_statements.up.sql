@@ -0,0 +1,35 @@
+CREATE TABLE bank_statements (
+    id            SERIAL PRIMARY KEY,
+    format        VARCHAR(20) NOT NULL,
+    account       VARCHAR(100),
+    file_name     VARCHAR(255),
+    total_entries INT NOT NULL DEFAULT 0,
+    posted        INT NOT NULL DEFAULT 0,
+    queued        INT NOT NULL DEFAULT 0,
+    duplicates    INT NOT NULL DEFAULT 0,
+    ignored       INT NOT NULL DEFAULT 0,
+    imported_by   VARCHAR(255) NOT NULL,
+    imported_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A bank transaction is reconciled once, whichever statement it arrives in.
+CREATE TABLE statement_entries (
+    id             SERIAL PRIMARY KEY,
+    statement_id   INT NOT NULL REFERENCES bank_statements(id) ON DELETE CASCADE,
+    transaction_id VARCHAR(100) NOT NULL UNIQUE,
+    booking_date   DATE NOT NULL,
+    amount         NUMERIC(15,2) NOT NULL,
+    credit         BOOLEAN NOT NULL,
+    currency       VARCHAR(3),
+    reference      TEXT,
+    account        VARCHAR(100),
+    status         VARCHAR(20) NOT NULL DEFAULT 'pending',
+    loan_id        INT REFERENCES loans(id),
+    payment_id     INT REFERENCES payments(id),
+    error          TEXT,
+    resolved_by    VARCHAR(255),
+    resolved_at    TIMESTAMP
+);
+
+CREATE INDEX idx_statement_entries_queue ON statement_entries(booking_date)
+    WHERE status IN ('pending', 'unmatched', 'failed');
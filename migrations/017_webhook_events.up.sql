@@ -0,0 +1,19 @@
+-- Payment notifications exactly as received, so they can be replayed.
+CREATE TABLE webhook_events (
+    id           SERIAL PRIMARY KEY,
+    provider     VARCHAR(50) NOT NULL,
+    event_id     VARCHAR(255) NOT NULL,
+    event_type   VARCHAR(100),
+    payload      TEXT NOT NULL,
+    status       VARCHAR(20) NOT NULL DEFAULT 'received',
+    loan_id      INT REFERENCES loans(id),
+    amount       NUMERIC(15,2),
+    payment_id   INT REFERENCES payments(id),
+    error        TEXT,
+    attempts     INT NOT NULL DEFAULT 0,
+    received_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP,
+    UNIQUE (provider, event_id)
+);
+
+CREATE INDEX idx_webhook_events_status ON webhook_events(status, received_at);
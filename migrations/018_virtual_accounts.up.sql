@@ -0,0 +1,3 @@
+-- Filled at loan creation; loans booked before this migration get theirs
+-- from `loanctl assign-virtual-accounts`.
+ALTER TABLE loans ADD COLUMN virtual_account VARCHAR(20) UNIQUE;
@@ -0,0 +1,3 @@
+DROP TABLE collection_instructions;
+DROP TABLE collection_runs;
+DROP TABLE mandates;
@@ -0,0 +1,50 @@
+CREATE TABLE mandates (
+    id             SERIAL PRIMARY KEY,
+    loan_id        INT NOT NULL REFERENCES loans(id),
+    reference      VARCHAR(35) NOT NULL UNIQUE,
+    debtor_name    VARCHAR(140) NOT NULL,
+    debtor_account VARCHAR(34) NOT NULL,
+    debtor_agent   VARCHAR(35),
+    signed_date    DATE NOT NULL,
+    status         VARCHAR(20) NOT NULL DEFAULT 'active',
+    dunning_level  INT NOT NULL DEFAULT 0,
+    status_reason  TEXT,
+    created_by     VARCHAR(255) NOT NULL,
+    created_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A loan has at most one mandate in force.
+CREATE UNIQUE INDEX idx_mandates_loan ON mandates(loan_id) WHERE status IN ('active', 'suspended');
+
+CREATE TABLE collection_runs (
+    id              SERIAL PRIMARY KEY,
+    collection_date DATE NOT NULL UNIQUE,
+    status          VARCHAR(20) NOT NULL DEFAULT 'open',
+    instructions    INT NOT NULL DEFAULT 0,
+    total_amount    NUMERIC(15,2) NOT NULL DEFAULT 0,
+    collected       INT NOT NULL DEFAULT 0,
+    failed          INT NOT NULL DEFAULT 0,
+    created_by      VARCHAR(255) NOT NULL,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    exported_at     TIMESTAMP
+);
+
+CREATE TABLE collection_instructions (
+    id            SERIAL PRIMARY KEY,
+    run_id        INT NOT NULL REFERENCES collection_runs(id) ON DELETE CASCADE,
+    mandate_id    INT NOT NULL REFERENCES mandates(id),
+    loan_id       INT NOT NULL REFERENCES loans(id),
+    end_to_end_id VARCHAR(35) NOT NULL UNIQUE,
+    amount        NUMERIC(15,2) NOT NULL,
+    attempt       INT NOT NULL DEFAULT 1,
+    status        VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reason_code   VARCHAR(10),
+    reason        TEXT,
+    -- Set on a failure that will be collected again from this date.
+    retry_on      DATE,
+    payment_id    INT REFERENCES payments(id),
+    updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_collection_instructions_mandate ON collection_instructions(mandate_id, id);
@@ -0,0 +1,31 @@
+CREATE TABLE borrower_contacts (
+    borrower_id VARCHAR(100) PRIMARY KEY,
+    email       VARCHAR(255),
+    phone       VARCHAR(20),
+    opt_out     BOOLEAN NOT NULL DEFAULT false,
+    updated_by  VARCHAR(255) NOT NULL,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE notifications (
+    id              SERIAL PRIMARY KEY,
+    kind            VARCHAR(30) NOT NULL,
+    borrower_id     VARCHAR(100) NOT NULL,
+    loan_id         INT NOT NULL REFERENCES loans(id),
+    channel         VARCHAR(20) NOT NULL,
+    recipient       VARCHAR(255),
+    -- The event and channel a notification is for; it is sent at most once.
+    dedup_key       VARCHAR(255) NOT NULL UNIQUE,
+    subject         TEXT,
+    body            TEXT NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts        INT NOT NULL DEFAULT 0,
+    error           TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    sent_at         TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_due ON notifications(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_notifications_borrower ON notifications(borrower_id);
+CREATE INDEX idx_notifications_loan ON notifications(loan_id);
@@ -0,0 +1,21 @@
+-- Domain events recorded in the transaction of the change they describe and
+-- published by the relay afterwards.
+CREATE TABLE outbox_events (
+    id              BIGSERIAL PRIMARY KEY,
+    event_type      VARCHAR(50) NOT NULL,
+    version         INT NOT NULL,
+    aggregate_type  VARCHAR(30) NOT NULL,
+    aggregate_id    VARCHAR(100) NOT NULL,
+    occurred_at     TIMESTAMP NOT NULL,
+    data            JSONB NOT NULL,
+    -- Set for events that must be recorded once, e.g. one per delinquency.
+    dedup_key       VARCHAR(255) UNIQUE,
+    attempts        INT NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    published_at    TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_events_unpublished ON outbox_events(id) WHERE published_at IS NULL;
+CREATE INDEX idx_outbox_events_aggregate ON outbox_events(aggregate_type, aggregate_id);
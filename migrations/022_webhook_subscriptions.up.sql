@@ -0,0 +1,48 @@
+-- Partner endpoints that domain events are pushed to.
+CREATE TABLE webhook_subscriptions (
+    id                         SERIAL PRIMARY KEY,
+    name                       VARCHAR(100) NOT NULL,
+    url                        TEXT NOT NULL,
+    event_types                TEXT[] NOT NULL,
+    active                     BOOLEAN NOT NULL DEFAULT TRUE,
+    secret                     VARCHAR(100) NOT NULL,
+    -- Kept signing for a grace period after the secret is rotated.
+    previous_secret            VARCHAR(100),
+    previous_secret_expires_at TIMESTAMP,
+    created_by                 VARCHAR(100) NOT NULL,
+    created_at                 TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at                 TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One event pushed to one subscription.
+CREATE TABLE webhook_deliveries (
+    id               SERIAL PRIMARY KEY,
+    subscription_id  INT NOT NULL REFERENCES webhook_subscriptions(id),
+    event_id         BIGINT NOT NULL REFERENCES outbox_events(id),
+    event_type       VARCHAR(50) NOT NULL,
+    payload          JSONB NOT NULL,
+    status           VARCHAR(20) NOT NULL,
+    attempts         INT NOT NULL DEFAULT 0,
+    last_status_code INT,
+    last_error       TEXT,
+    next_attempt_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at       TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    delivered_at     TIMESTAMP,
+    UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_status ON webhook_deliveries(status, subscription_id);
+
+-- The log of every POST of a delivery.
+CREATE TABLE webhook_delivery_attempts (
+    id           SERIAL PRIMARY KEY,
+    delivery_id  INT NOT NULL REFERENCES webhook_deliveries(id),
+    attempted_at TIMESTAMP NOT NULL,
+    status_code  INT,
+    error        TEXT,
+    duration_ms  INT NOT NULL,
+    response     TEXT
+);
+
+CREATE INDEX idx_webhook_delivery_attempts_delivery ON webhook_delivery_attempts(delivery_id);
@@ -0,0 +1,3 @@
+ALTER TABLE loans
+    ALTER COLUMN apr                   TYPE NUMERIC(9,4),
+    ALTER COLUMN effective_annual_rate TYPE NUMERIC(9,4);
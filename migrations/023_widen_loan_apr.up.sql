@@ -0,0 +1,5 @@
+-- A high rate over a short term compounds to an effective annual rate far
+-- beyond NUMERIC(9,4).
+ALTER TABLE loans
+    ALTER COLUMN apr                   TYPE NUMERIC(20,4),
+    ALTER COLUMN effective_annual_rate TYPE NUMERIC(20,4);
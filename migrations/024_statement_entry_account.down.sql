@@ -0,0 +1,3 @@
+ALTER TABLE statement_entries DROP CONSTRAINT statement_entries_account_transaction_key;
+ALTER TABLE statement_entries ADD CONSTRAINT statement_entries_transaction_id_key UNIQUE (transaction_id);
+ALTER TABLE statement_entries DROP COLUMN statement_account;
@@ -0,0 +1,12 @@
+-- Banks number transactions per account, so the same transaction ID may
+-- arrive on two accounts. A transaction is reconciled once per account.
+ALTER TABLE statement_entries ADD COLUMN statement_account VARCHAR(100) NOT NULL DEFAULT '';
+
+UPDATE statement_entries e
+SET statement_account = COALESCE(s.account, '')
+FROM bank_statements s
+WHERE s.id = e.statement_id;
+
+ALTER TABLE statement_entries DROP CONSTRAINT statement_entries_transaction_id_key;
+ALTER TABLE statement_entries
+    ADD CONSTRAINT statement_entries_account_transaction_key UNIQUE (statement_account, transaction_id);
@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL schema migrations into the binary.
+//
+// Each version NNN_name has an NNN_name.up.sql and an NNN_name.down.sql file.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
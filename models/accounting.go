@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// Chart of accounts.
+const (
+	AccountCash               = "CASH"
+	AccountLoanReceivable     = "LOAN_RECEIVABLE"
+	AccountInterestReceivable = "INTEREST_RECEIVABLE"
+	AccountInterestIncome     = "INTEREST_INCOME"
+	AccountFeeIncome          = "FEE_INCOME"
+	// AccountSuspense holds loan proceeds booked but not yet disbursed.
+	AccountSuspense        = "SUSPENSE"
+	AccountLoanLossExpense = "LOAN_LOSS_EXPENSE"
+	AccountRecoveryIncome  = "RECOVERY_INCOME"
+)
+
+// Journal entry event types.
+const (
+	JournalEventLoanBooked   = "loan_booked"
+	JournalEventDisbursement = "disbursement"
+	JournalEventPayment      = "payment"
+	JournalEventReversal     = "reversal"
+	JournalEventAccrual      = "interest_accrual"
+	JournalEventWriteOff     = "write_off"
+	JournalEventRecovery     = "recovery"
+	JournalEventRestructure  = "restructure"
+)
+
+type JournalEntry struct {
+	ID          int           `json:"id"`
+	EntryDate   time.Time     `json:"entry_date"`
+	EventType   string        `json:"event_type"`
+	LoanID      *int          `json:"loan_id,omitempty"`
+	Reference   string        `json:"reference"`
+	Description string        `json:"description"`
+	ReversalOf  *int          `json:"reversal_of,omitempty"`
+	Lines       []JournalLine `json:"lines"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+type JournalLine struct {
+	AccountCode string  `json:"account_code"`
+	Debit       float64 `json:"debit"`
+	Credit      float64 `json:"credit"`
+}
+
+// PaymentSplit is how a payment is allocated across the loan's balances.
+type PaymentSplit struct {
+	Principal float64 `json:"principal"`
+	Interest  float64 `json:"interest"`
+	Fees      float64 `json:"fees"`
+	// Final marks the payment that leaves no installment unpaid. It clears
+	// whatever principal is left on the receivable.
+	Final bool `json:"-"`
+}
+
+type TrialBalanceRow struct {
+	AccountCode string  `json:"account_code"`
+	AccountName string  `json:"account_name"`
+	AccountType string  `json:"account_type"`
+	Debit       float64 `json:"debit"`
+	Credit      float64 `json:"credit"`
+	Balance     float64 `json:"balance"`
+}
+
+type TrialBalance struct {
+	Accounts    []TrialBalanceRow `json:"accounts"`
+	TotalDebit  float64           `json:"total_debit"`
+	TotalCredit float64           `json:"total_credit"`
+	Balanced    bool              `json:"balanced"`
+}
+
+type ReverseEntryRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
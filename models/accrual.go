@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// InterestAccrual is the result of one accrual run for one loan and business date.
+type InterestAccrual struct {
+	ID          int       `json:"id"`
+	LoanID      int       `json:"loan_id"`
+	AccrualDate time.Time `json:"accrual_date"`
+	Amount      float64   `json:"amount"`
+	DaysPastDue int       `json:"days_past_due"`
+	// NonAccrual is set when the loan was past the non-accrual DPD threshold
+	// and nothing was accrued.
+	NonAccrual bool      `json:"non_accrual"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AccrualRun summarises an accrual run over all active loans.
+type AccrualRun struct {
+	BusinessDate   time.Time `json:"business_date"`
+	LoansProcessed int       `json:"loans_processed"`
+	Accrued        int       `json:"accrued"`
+	NonAccrual     int       `json:"non_accrual"`
+	AlreadyRun     int       `json:"already_run"`
+	TotalAmount    float64   `json:"total_amount"`
+	Errors         []string  `json:"errors,omitempty"`
+}
+
+type RunAccrualRequest struct {
+	BusinessDate string `json:"business_date" binding:"omitempty,datetime=2006-01-02"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Loan application statuses: submitted -> under_review -> approved/rejected.
+const (
+	ApplicationStatusSubmitted   = "submitted"
+	ApplicationStatusUnderReview = "under_review"
+	ApplicationStatusApproved    = "approved"
+	ApplicationStatusRejected    = "rejected"
+)
+
+// LoanApplication is a request for a loan awaiting review. CreditOutcome is
+// the automated credit decision made on submission. Approving the
+// application books the loan; LoanID then points at it.
+type LoanApplication struct {
+	ID            int        `json:"id"`
+	BorrowerID    string     `json:"borrower_id"`
+	Principal     float64    `json:"principal"`
+	InterestRate  float64    `json:"interest_rate"`
+	TermWeeks     int        `json:"term_weeks"`
+	StartDate     time.Time  `json:"start_date"`
+	ProductCode   string     `json:"product_code,omitempty"`
+	Status        string     `json:"status"`
+	CreditOutcome string     `json:"credit_outcome,omitempty"`
+	SubmittedBy   string     `json:"submitted_by"`
+	ReviewedBy    string     `json:"reviewed_by,omitempty"`
+	DecidedBy     string     `json:"decided_by,omitempty"`
+	DecidedRole   string     `json:"decided_role,omitempty"`
+	DecisionNote  string     `json:"decision_note,omitempty"`
+	LoanID        *int       `json:"loan_id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DecidedAt     *time.Time `json:"decided_at,omitempty"`
+}
+
+// Terms returns the loan terms the application asks for.
+func (a *LoanApplication) Terms() LoanTerms {
+	return LoanTerms{
+		BorrowerID:   a.BorrowerID,
+		Principal:    a.Principal,
+		InterestRate: a.InterestRate,
+		TermWeeks:    a.TermWeeks,
+		StartDate:    a.StartDate,
+		ProductCode:  a.ProductCode,
+	}
+}
+
+type ApplicationDecisionRequest struct {
+	Note string `json:"note"`
+}
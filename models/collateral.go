@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// Lien statuses. Released collateral no longer secures the loan.
+const (
+	LienStatusPending    = "pending"
+	LienStatusRegistered = "registered"
+	LienStatusReleased   = "released"
+)
+
+// Guarantor roles.
+const (
+	GuarantorRoleGuarantor  = "guarantor"
+	GuarantorRoleCoBorrower = "co_borrower"
+)
+
+type Collateral struct {
+	ID             int       `json:"id"`
+	LoanID         int       `json:"loan_id"`
+	CollateralType string    `json:"collateral_type"`
+	Description    string    `json:"description"`
+	Valuation      float64   `json:"valuation"`
+	ValuationDate  time.Time `json:"valuation_date"`
+	LienStatus     string    `json:"lien_status"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type CollateralRequest struct {
+	CollateralType string  `json:"collateral_type" binding:"required,oneof=property vehicle deposit equipment inventory other"`
+	Description    string  `json:"description"`
+	Valuation      float64 `json:"valuation" binding:"required,gt=0"`
+	ValuationDate  string  `json:"valuation_date" binding:"required,datetime=2006-01-02"`
+	LienStatus     string  `json:"lien_status" binding:"required,oneof=pending registered released"`
+}
+
+// Guarantor is a guarantor or co-borrower liable for LiabilityShare percent
+// of the loan.
+type Guarantor struct {
+	ID             int       `json:"id"`
+	LoanID         int       `json:"loan_id"`
+	Name           string    `json:"name"`
+	Role           string    `json:"role"`
+	IDNumber       string    `json:"id_number"`
+	Phone          string    `json:"phone"`
+	LiabilityShare float64   `json:"liability_share"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type GuarantorRequest struct {
+	Name           string  `json:"name" binding:"required"`
+	Role           string  `json:"role" binding:"required,oneof=guarantor co_borrower"`
+	IDNumber       string  `json:"id_number"`
+	Phone          string  `json:"phone"`
+	LiabilityShare float64 `json:"liability_share" binding:"required,gt=0,lte=100"`
+}
+
+// LoanToValue compares a loan with the collateral securing it. Ratios are in
+// percent and zero when there is no collateral.
+type LoanToValue struct {
+	LoanID          int     `json:"loan_id"`
+	Principal       float64 `json:"principal"`
+	Outstanding     float64 `json:"outstanding"`
+	CollateralValue float64 `json:"collateral_value"`
+	// OriginalLTV uses Principal; CurrentLTV uses the outstanding balance.
+	OriginalLTV float64 `json:"original_ltv"`
+	CurrentLTV  float64 `json:"current_ltv"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Credit decision outcomes. A refer leaves the application to manual review.
+const (
+	CreditOutcomeApprove = "approve"
+	CreditOutcomeRefer   = "refer"
+	CreditOutcomeDecline = "decline"
+)
+
+// CreditInputs is what a credit decision is made on: the requested terms and
+// the borrower's existing loans.
+type CreditInputs struct {
+	BorrowerID   string  `json:"borrower_id"`
+	Principal    float64 `json:"principal"`
+	InterestRate float64 `json:"interest_rate"`
+	TermWeeks    int     `json:"term_weeks"`
+	ProductCode  string  `json:"product_code,omitempty"`
+	// ExistingExposure is the outstanding balance across the borrower's loans.
+	ExistingExposure float64 `json:"existing_exposure"`
+	ActiveLoans      int     `json:"active_loans"`
+	// Delinquency history across the borrower's loans. MaxDaysPaidLate is
+	// the worst arrears the borrower has since caught up on.
+	DelinquentLoans   int `json:"delinquent_loans"`
+	MaxDaysPastDue    int `json:"max_days_past_due"`
+	MaxDaysPaidLate   int `json:"max_days_paid_late"`
+	RestructuredLoans int `json:"restructured_loans"`
+	WrittenOffLoans   int `json:"written_off_loans"`
+}
+
+// CreditDecision is a stored decision on a loan application, kept with its
+// inputs and reasons for audit.
+type CreditDecision struct {
+	ID            int          `json:"id"`
+	ApplicationID int          `json:"application_id"`
+	Engine        string       `json:"engine"`
+	Outcome       string       `json:"outcome"`
+	Inputs        CreditInputs `json:"inputs"`
+	Reasons       []string     `json:"reasons"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
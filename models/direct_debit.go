@@ -0,0 +1,120 @@
+package models
+
+import "time"
+
+// Mandate statuses. A suspended mandate is not collected until it is
+// reactivated.
+const (
+	MandateActive    = "active"
+	MandateSuspended = "suspended"
+	MandateCancelled = "cancelled"
+)
+
+// Collection run statuses.
+const (
+	CollectionRunOpen      = "open"
+	CollectionRunExported  = "exported"
+	CollectionRunCompleted = "completed"
+)
+
+// Collection instruction statuses. Pending and exported instructions await
+// the bank's result; unapplied ones were collected but could not be posted
+// as a payment and need manual handling.
+const (
+	CollectionPending   = "pending"
+	CollectionExported  = "exported"
+	CollectionCollected = "collected"
+	CollectionFailed    = "failed"
+	CollectionUnapplied = "unapplied"
+)
+
+// Mandate authorises the lender to debit the borrower's bank account for a
+// loan. DunningLevel counts collections that failed for good since the last
+// successful one.
+type Mandate struct {
+	ID            int       `json:"id"`
+	LoanID        int       `json:"loan_id"`
+	Reference     string    `json:"reference"`
+	DebtorName    string    `json:"debtor_name"`
+	DebtorAccount string    `json:"debtor_account"`
+	DebtorAgent   string    `json:"debtor_agent,omitempty"`
+	SignedDate    time.Time `json:"signed_date"`
+	Status        string    `json:"status"`
+	DunningLevel  int       `json:"dunning_level"`
+	StatusReason  string    `json:"status_reason,omitempty"`
+	CreatedBy     string    `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type CreateMandateRequest struct {
+	// Reference is the unique mandate reference agreed with the bank.
+	Reference     string `json:"reference" binding:"required,max=35"`
+	DebtorName    string `json:"debtor_name" binding:"required,max=140"`
+	DebtorAccount string `json:"debtor_account" binding:"required,max=34"`
+	DebtorAgent   string `json:"debtor_agent,omitempty" binding:"max=35"`
+	SignedDate    string `json:"signed_date" binding:"required,datetime=2006-01-02"`
+}
+
+type CancelMandateRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// CollectionPolicy decides what happens when a direct debit fails.
+type CollectionPolicy struct {
+	// MaxAttempts is how often a failed collection is tried in total.
+	MaxAttempts int
+	// RetryDays is the wait before a failed collection is tried again.
+	RetryDays int
+	// SuspendAfter suspends a mandate at this dunning level; 0 never does.
+	SuspendAfter int
+}
+
+// CollectionRun is the direct debit batch of one collection date.
+type CollectionRun struct {
+	ID             int                     `json:"id"`
+	CollectionDate time.Time               `json:"collection_date"`
+	Status         string                  `json:"status"`
+	Instructions   int                     `json:"instructions"`
+	TotalAmount    float64                 `json:"total_amount"`
+	Collected      int                     `json:"collected"`
+	Failed         int                     `json:"failed"`
+	CreatedBy      string                  `json:"created_by"`
+	CreatedAt      time.Time               `json:"created_at"`
+	ExportedAt     *time.Time              `json:"exported_at,omitempty"`
+	Items          []CollectionInstruction `json:"items,omitempty"`
+}
+
+type CreateCollectionRunRequest struct {
+	CollectionDate string `json:"collection_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// CollectionInstruction asks the bank to collect the amount due on a loan.
+// RetryOn is set on a failure that will be collected again.
+type CollectionInstruction struct {
+	ID         int        `json:"id"`
+	RunID      int        `json:"run_id"`
+	MandateID  int        `json:"mandate_id"`
+	LoanID     int        `json:"loan_id"`
+	EndToEndID string     `json:"end_to_end_id"`
+	Amount     float64    `json:"amount"`
+	Attempt    int        `json:"attempt"`
+	Status     string     `json:"status"`
+	ReasonCode string     `json:"reason_code,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+	RetryOn    *time.Time `json:"retry_on,omitempty"`
+	PaymentID  *int       `json:"payment_id,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// CollectionResults summarises one imported result file. Unknown lists
+// end-to-end IDs that are not open instructions of the run.
+type CollectionResults struct {
+	RunID          int      `json:"run_id"`
+	Collected      int      `json:"collected"`
+	Failed         int      `json:"failed"`
+	RetryScheduled int      `json:"retry_scheduled"`
+	Unapplied      int      `json:"unapplied"`
+	Pending        int      `json:"pending"`
+	Unknown        []string `json:"unknown,omitempty"`
+}
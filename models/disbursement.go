@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Disbursement is one tranche of money sent to the borrower.
+type Disbursement struct {
+	ID               int       `json:"id"`
+	LoanID           int       `json:"loan_id"`
+	Amount           float64   `json:"amount"`
+	DisbursementDate time.Time `json:"disbursement_date"`
+	Channel          string    `json:"channel"`
+	Reference        string    `json:"reference"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type DisbursementRequest struct {
+	Amount           float64 `json:"amount" binding:"required,gt=0"`
+	DisbursementDate string  `json:"disbursement_date" binding:"omitempty,datetime=2006-01-02"`
+	Channel          string  `json:"channel" binding:"required"`
+	Reference        string  `json:"reference" binding:"required"`
+}
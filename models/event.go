@@ -0,0 +1,107 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Domain event types. Each payload below is one version of a type's data;
+// a breaking change to a payload gets a new version, so consumers can tell
+// what they are reading. The JSON Schemas are in docs/events.
+const (
+	EventLoanCreated        = "loan.created"
+	EventPaymentReceived    = "payment.received"
+	EventInstallmentSettled = "installment.settled"
+	EventLoanDelinquent     = "loan.delinquent"
+)
+
+// EventTypes lists every domain event type.
+var EventTypes = []string{EventLoanCreated, EventPaymentReceived, EventInstallmentSettled, EventLoanDelinquent}
+
+// Event statuses for listing the outbox.
+const (
+	EventPending   = "pending"
+	EventPublished = "published"
+)
+
+// Event is a domain event as published. ID is unique and stable across
+// redeliveries, so consumers can drop the duplicates that at-least-once
+// delivery brings.
+type Event struct {
+	ID            int64           `json:"id"`
+	Type          string          `json:"type"`
+	Version       int             `json:"version"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Data          json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+// OutboxEvent is an event waiting in the outbox, or published from it.
+// DedupKey, when set, keeps an event from being recorded twice.
+type OutboxEvent struct {
+	Event
+	DedupKey      string     `json:"-"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	PublishedAt   *time.Time `json:"published_at,omitempty"`
+}
+
+// EventFilter narrows an outbox listing; empty fields match all.
+type EventFilter struct {
+	Type   string
+	LoanID int
+	Status string
+}
+
+// EventRelay counts the outcome of one pass of the relay.
+type EventRelay struct {
+	Published int `json:"published"`
+	Failed    int `json:"failed"`
+}
+
+// LoanCreatedV1 is the data of loan.created version 1.
+type LoanCreatedV1 struct {
+	LoanID          int       `json:"loan_id"`
+	BorrowerID      string    `json:"borrower_id,omitempty"`
+	ProductID       *int      `json:"product_id,omitempty"`
+	Principal       float64   `json:"principal"`
+	InterestRate    float64   `json:"interest_rate"`
+	TermWeeks       int       `json:"term_weeks"`
+	WeeklyAmount    float64   `json:"weekly_amount"`
+	StartDate       time.Time `json:"start_date"`
+	NetDisbursement float64   `json:"net_disbursement"`
+	Status          string    `json:"status"`
+}
+
+// PaymentReceivedV1 is the data of payment.received version 1.
+type PaymentReceivedV1 struct {
+	PaymentID      int       `json:"payment_id"`
+	LoanID         int       `json:"loan_id"`
+	Amount         float64   `json:"amount"`
+	PaymentType    string    `json:"payment_type"`
+	PaymentDate    time.Time `json:"payment_date"`
+	InstallmentIDs []int     `json:"installment_ids"`
+	ChargeIDs      []int     `json:"charge_ids"`
+}
+
+// InstallmentSettledV1 is the data of installment.settled version 1.
+type InstallmentSettledV1 struct {
+	InstallmentID int       `json:"installment_id"`
+	LoanID        int       `json:"loan_id"`
+	PaymentID     int       `json:"payment_id"`
+	WeekNumber    int       `json:"week_number"`
+	DueDate       time.Time `json:"due_date"`
+	Amount        float64   `json:"amount"`
+}
+
+// LoanDelinquentV1 is the data of loan.delinquent version 1. It is recorded
+// once per delinquency, by the first sweep that finds the loan delinquent.
+type LoanDelinquentV1 struct {
+	LoanID                    int       `json:"loan_id"`
+	BorrowerID                string    `json:"borrower_id,omitempty"`
+	BusinessDate              time.Time `json:"business_date"`
+	DaysPastDue               int       `json:"days_past_due"`
+	OldestUnpaidInstallmentID int       `json:"oldest_unpaid_installment_id"`
+}
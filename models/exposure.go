@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+// Exposure limits, as named in a LimitBreach.
+const (
+	LimitMaxOutstanding  = "max_outstanding"
+	LimitMaxActiveLoans  = "max_active_loans"
+	LimitBlockDelinquent = "block_delinquent"
+)
+
+// Scopes an exposure limit is set at.
+const (
+	LimitScopeBorrower = "borrower"
+	LimitScopeProduct  = "product"
+)
+
+// ExposureLimits caps what a borrower may owe. Zero amounts mean no limit.
+type ExposureLimits struct {
+	// MaxOutstanding caps the borrower's outstanding balance including the new loan.
+	MaxOutstanding float64 `json:"max_outstanding" binding:"gte=0"`
+	// MaxActiveLoans caps the borrower's concurrent loans including the new one.
+	MaxActiveLoans int `json:"max_active_loans" binding:"gte=0"`
+	// BlockDelinquent refuses new loans while any loan of the borrower is delinquent.
+	BlockDelinquent bool `json:"block_delinquent"`
+}
+
+// IsZero reports whether no limit is set.
+func (l ExposureLimits) IsZero() bool {
+	return l == ExposureLimits{}
+}
+
+// BorrowerLimit replaces the configured default limits for one borrower.
+type BorrowerLimit struct {
+	BorrowerID string `json:"borrower_id"`
+	ExposureLimits
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ProductExposure is a borrower's exposure on the loans of one product.
+type ProductExposure struct {
+	ProductID   int     `json:"product_id"`
+	Outstanding float64 `json:"outstanding"`
+	ActiveLoans int     `json:"active_loans"`
+}
+
+// BorrowerExposure totals a borrower's existing loans. Limits are the
+// borrower-level limits in force: the borrower's own or the defaults.
+type BorrowerExposure struct {
+	BorrowerID        string            `json:"borrower_id"`
+	Outstanding       float64           `json:"outstanding"`
+	ActiveLoans       int               `json:"active_loans"`
+	DelinquentLoans   int               `json:"delinquent_loans"`
+	MaxDaysPastDue    int               `json:"max_days_past_due"`
+	MaxDaysPaidLate   int               `json:"max_days_paid_late"`
+	RestructuredLoans int               `json:"restructured_loans"`
+	WrittenOffLoans   int               `json:"written_off_loans"`
+	Products          []ProductExposure `json:"products"`
+	Limits            ExposureLimits    `json:"limits"`
+}
+
+// Product returns the borrower's exposure on the given product.
+func (e *BorrowerExposure) Product(productID int) ProductExposure {
+	for _, p := range e.Products {
+		if p.ProductID == productID {
+			return p
+		}
+	}
+	return ProductExposure{ProductID: productID}
+}
+
+// LimitBreach is one exposure limit a new loan would exceed. Actual is what
+// the borrower would reach with the new loan; for block_delinquent it is the
+// number of delinquent loans.
+type LimitBreach struct {
+	Limit       string  `json:"limit"`
+	Scope       string  `json:"scope"`
+	ProductCode string  `json:"product_code,omitempty"`
+	Max         float64 `json:"max"`
+	Actual      float64 `json:"actual"`
+}
@@ -2,8 +2,17 @@ package models
 
 import "time"
 
+// Loan statuses.
+const (
+	LoanStatusPendingDisbursement = "pending_disbursement"
+	LoanStatusActive              = "active"
+	LoanStatusWrittenOff          = "written_off"
+	LoanStatusRefinanced          = "refinanced"
+)
+
 type Loan struct {
 	ID           int       `json:"id"`
+	BorrowerID   string    `json:"borrower_id,omitempty"`
 	Principal    float64   `json:"principal"`
 	InterestRate float64   `json:"interest_rate"`
 	TermWeeks    int       `json:"term_weeks"`
@@ -11,6 +20,36 @@ type Loan struct {
 	StartDate    time.Time `json:"start_date"`
 	IsActive     bool      `json:"is_active"`
 	CreatedAt    time.Time `json:"created_at"`
+	// APR and EffectiveAnnualRate are the regulatory disclosure rates in percent,
+	// solved from the installment cash flows when the loan is created.
+	APR                 float64 `json:"apr"`
+	EffectiveAnnualRate float64 `json:"effective_annual_rate"`
+	ProductID           *int    `json:"product_id,omitempty"`
+	// OriginationFee is the total origination fee, whether deducted from the
+	// disbursement or financed into Principal. NetDisbursement is what the
+	// borrower actually receives.
+	OriginationFee  float64 `json:"origination_fee"`
+	NetDisbursement float64 `json:"net_disbursement"`
+	// Status is pending_disbursement until DisbursedAmount reaches
+	// NetDisbursement. The schedule runs from FirstDisbursementDate.
+	Status                string     `json:"status"`
+	DisbursedAmount       float64    `json:"disbursed_amount"`
+	FirstDisbursementDate *time.Time `json:"first_disbursement_date,omitempty"`
+	// WrittenOffAmount is the outstanding balance moved off the active book
+	// by a write-off; RecoveredAmount is what has been collected on it since.
+	WrittenOffAmount float64    `json:"written_off_amount"`
+	WrittenOffDate   *time.Time `json:"written_off_date,omitempty"`
+	RecoveredAmount  float64    `json:"recovered_amount"`
+	// ScheduleVersion is the current installment schedule; it goes up each
+	// time the loan is restructured.
+	ScheduleVersion int  `json:"schedule_version"`
+	Restructured    bool `json:"restructured"`
+	// A top-up settles the previous loan and links it to the new one.
+	RefinancesLoanID   *int `json:"refinances_loan_id,omitempty"`
+	RefinancedByLoanID *int `json:"refinanced_by_loan_id,omitempty"`
+	// VirtualAccount is the account number the borrower transfers
+	// repayments to; bank statements and webhooks are matched on it.
+	VirtualAccount string `json:"virtual_account,omitempty"`
 }
 
 type Installment struct {
@@ -20,11 +59,51 @@ type Installment struct {
 	DueDate    time.Time `json:"due_date"`
 	Amount     float64   `json:"amount"`
 	Paid       bool      `json:"paid"`
+	// Amount split into its components; FeeAmount holds servicing fees added
+	// into the installment.
+	PrincipalAmount float64 `json:"principal_amount"`
+	InterestAmount  float64 `json:"interest_amount"`
+	FeeAmount       float64 `json:"fee_amount"`
+	// Closed marks an unpaid installment superseded by a restructure.
+	ScheduleVersion int  `json:"schedule_version"`
+	Closed          bool `json:"closed"`
 }
 
 type CreateLoanRequest struct {
+	BorrowerID   string  `json:"borrower_id,omitempty"`
 	Principal    float64 `json:"principal" binding:"required,gt=0"`
 	InterestRate float64 `json:"interest_rate" binding:"required,gt=0"`
 	TermWeeks    int     `json:"term_weeks" binding:"required,gt=0"`
 	StartDate    string  `json:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	ProductCode  string  `json:"product_code,omitempty"`
+}
+
+// LoanTerms are the parsed inputs for creating or quoting a loan.
+type LoanTerms struct {
+	BorrowerID   string
+	Principal    float64
+	InterestRate float64
+	TermWeeks    int
+	StartDate    time.Time
+	ProductCode  string
+}
+
+// LoanQuote is a simulated loan: the schedule and totals a loan with the given
+// terms would have, without anything being persisted.
+type LoanQuote struct {
+	Principal           float64       `json:"principal"`
+	InterestRate        float64       `json:"interest_rate"`
+	TermWeeks           int           `json:"term_weeks"`
+	WeeklyAmount        float64       `json:"weekly_amount"`
+	StartDate           time.Time     `json:"start_date"`
+	TotalInterest       float64       `json:"total_interest"`
+	TotalRepayable      float64       `json:"total_repayable"`
+	APR                 float64       `json:"apr"`
+	EffectiveAnnualRate float64       `json:"effective_annual_rate"`
+	ProductCode         string        `json:"product_code,omitempty"`
+	OriginationFee      float64       `json:"origination_fee"`
+	NetDisbursement     float64       `json:"net_disbursement"`
+	TotalFees           float64       `json:"total_fees"`
+	Installments        []Installment `json:"installments"`
+	Charges             []LoanCharge  `json:"charges,omitempty"`
 }
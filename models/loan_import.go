@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Loan import job statuses.
+const (
+	ImportJobRunning   = "running"
+	ImportJobCompleted = "completed"
+	ImportJobFailed    = "failed"
+)
+
+// Loan import row outcomes. A dry run reports rows that would import as valid.
+const (
+	ImportRowImported = "imported"
+	ImportRowValid    = "valid"
+	ImportRowFailed   = "failed"
+)
+
+// LoanImportJob is one run of the CSV loan import. Counts are updated as rows
+// are processed, so a running job shows its progress.
+type LoanImportJob struct {
+	ID         int             `json:"id"`
+	DryRun     bool            `json:"dry_run"`
+	Status     string          `json:"status"`
+	TotalRows  int             `json:"total_rows"`
+	Succeeded  int             `json:"succeeded"`
+	Failed     int             `json:"failed"`
+	Error      string          `json:"error,omitempty"`
+	CreatedBy  string          `json:"created_by"`
+	CreatedAt  time.Time       `json:"created_at"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+	Rows       []LoanImportRow `json:"rows,omitempty"`
+}
+
+// LoanImportRow is the outcome of one CSV row. Line is the row's line in the
+// file, the header being line 1.
+type LoanImportRow struct {
+	Line             int    `json:"line"`
+	Ref              string `json:"ref"`
+	Status           string `json:"status"`
+	LoanID           *int   `json:"loan_id,omitempty"`
+	PaymentsReplayed int    `json:"payments_replayed"`
+	Error            string `json:"error,omitempty"`
+}
@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// Notification kinds.
+const (
+	NotificationDueReminder      = "due_reminder"
+	NotificationPaymentReceipt   = "payment_receipt"
+	NotificationDelinquencyAlert = "delinquency_alert"
+)
+
+// Notification statuses. Pending notifications are sent by the dispatcher;
+// failed ones ran out of attempts and skipped ones had nobody to send to.
+const (
+	NotificationPending = "pending"
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed"
+	NotificationSkipped = "skipped"
+)
+
+// Notification is one message to a borrower on one channel. DedupKey names
+// the event it is about, so the same event is never sent twice.
+type Notification struct {
+	ID            int        `json:"id"`
+	Kind          string     `json:"kind"`
+	BorrowerID    string     `json:"borrower_id"`
+	LoanID        int        `json:"loan_id"`
+	Channel       string     `json:"channel"`
+	Recipient     string     `json:"recipient,omitempty"`
+	DedupKey      string     `json:"dedup_key"`
+	Subject       string     `json:"subject,omitempty"`
+	Body          string     `json:"body"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	Error         string     `json:"error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+// BorrowerContact is where a borrower is notified. OptOut stops all
+// notifications to the borrower.
+type BorrowerContact struct {
+	BorrowerID string    `json:"borrower_id"`
+	Email      string    `json:"email,omitempty"`
+	Phone      string    `json:"phone,omitempty"`
+	OptOut     bool      `json:"opt_out"`
+	UpdatedBy  string    `json:"updated_by"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type SetContactRequest struct {
+	Email  string `json:"email" binding:"omitempty,email"`
+	Phone  string `json:"phone" binding:"omitempty,e164"`
+	OptOut bool   `json:"opt_out"`
+}
+
+// NotificationFilter narrows a notification listing; empty fields match all.
+type NotificationFilter struct {
+	BorrowerID string
+	LoanID     int
+	Status     string
+}
+
+type RunNotificationsRequest struct {
+	BusinessDate string `json:"business_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// NotificationRun counts the notifications queued for a business date:
+// reminders of installments falling due soon and alerts for loans that
+// became delinquent.
+type NotificationRun struct {
+	BusinessDate time.Time `json:"business_date"`
+	Reminders    int       `json:"reminders"`
+	Alerts       int       `json:"alerts"`
+	Errors       []string  `json:"errors,omitempty"`
+}
+
+// NotificationDispatch counts the outcome of one pass of the dispatcher.
+type NotificationDispatch struct {
+	Sent     int `json:"sent"`
+	Retrying int `json:"retrying"`
+	Failed   int `json:"failed"`
+}
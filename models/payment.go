@@ -2,12 +2,26 @@ package models
 
 import "time"
 
+// Payment types.
+const (
+	PaymentTypeInstallment = "installment"
+	// PaymentTypeRecovery is money collected on a written-off loan.
+	PaymentTypeRecovery = "recovery"
+	// PaymentTypeSettlement pays off a loan internally when it is refinanced.
+	PaymentTypeSettlement = "settlement"
+)
+
 type Payment struct {
 	ID             int       `json:"id"`
 	LoanID         int       `json:"loan_id"`
 	Amount         float64   `json:"amount"`
 	PaymentDate    time.Time `json:"payment_date"`
 	IdempotencyKey string    `json:"idempotency_key"`
+	PaymentType    string    `json:"payment_type"`
+	// ReversedAt is set once the payment has been reversed; its installments
+	// and charges are then open again.
+	ReversedAt     *time.Time `json:"reversed_at,omitempty"`
+	ReversalReason *string    `json:"reversal_reason,omitempty"`
 }
 
 type PaymentInstallment struct {
@@ -18,3 +32,7 @@ type PaymentInstallment struct {
 type PaymentRequest struct {
 	Amount float64 `json:"amount" binding:"required,gt=0"`
 }
+
+type ReversePaymentRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
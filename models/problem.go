@@ -0,0 +1,13 @@
+package models
+
+// Problem is an RFC 7807 problem details response. Code is a stable,
+// machine-readable error code; Type is derived from it. Some errors add
+// further members, such as the breaches of an exposure limit.
+type Problem struct {
+	Type     string `json:"type" example:"/problems/loan_not_found"`
+	Title    string `json:"title" example:"Not Found"`
+	Status   int    `json:"status" example:"404"`
+	Detail   string `json:"detail,omitempty" example:"loan not found"`
+	Instance string `json:"instance,omitempty" example:"/api/v1/loans/42"`
+	Code     string `json:"code" example:"loan_not_found"`
+}
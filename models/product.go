@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// Fee types.
+const (
+	FeeTypeOrigination = "origination"
+	FeeTypeServicing   = "servicing"
+)
+
+// Fee calculation methods.
+const (
+	FeeCalcFlat    = "flat"    // Amount is a currency amount
+	FeeCalcPercent = "percent" // Amount is a percentage of the principal
+)
+
+// Fee treatments. Origination fees are either deducted from the disbursement
+// or financed into the principal; servicing fees are either added into the
+// installments or charged on their own.
+const (
+	FeeTreatmentDeducted    = "deducted"
+	FeeTreatmentFinanced    = "financed"
+	FeeTreatmentInstallment = "installment"
+	FeeTreatmentSeparate    = "separate"
+)
+
+type LoanProduct struct {
+	ID   int          `json:"id"`
+	Code string       `json:"code"`
+	Name string       `json:"name"`
+	Fees []ProductFee `json:"fees"`
+	// Limits cap each borrower's exposure on loans of this product.
+	Limits    ExposureLimits `json:"limits"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type ProductFee struct {
+	ID          int     `json:"id"`
+	ProductID   int     `json:"product_id"`
+	FeeType     string  `json:"fee_type"`
+	Calculation string  `json:"calculation"`
+	Amount      float64 `json:"amount"`
+	Treatment   string  `json:"treatment"`
+	// FrequencyWeeks is how often a servicing fee recurs, e.g. 4 for every fourth installment.
+	FrequencyWeeks int `json:"frequency_weeks"`
+}
+
+// LoanCharge is a fee charged against a loan outside of its installments.
+type LoanCharge struct {
+	ID      int       `json:"id"`
+	LoanID  int       `json:"loan_id"`
+	FeeType string    `json:"fee_type"`
+	DueDate time.Time `json:"due_date"`
+	Amount  float64   `json:"amount"`
+	Paid    bool      `json:"paid"`
+	// Closed marks an unpaid charge superseded by a restructure.
+	ScheduleVersion int  `json:"schedule_version"`
+	Closed          bool `json:"closed"`
+}
+
+type CreateProductRequest struct {
+	Code   string                    `json:"code" binding:"required"`
+	Name   string                    `json:"name" binding:"required"`
+	Fees   []CreateProductFeeRequest `json:"fees" binding:"dive"`
+	Limits ExposureLimits            `json:"limits"`
+}
+
+type CreateProductFeeRequest struct {
+	FeeType        string  `json:"fee_type" binding:"required,oneof=origination servicing"`
+	Calculation    string  `json:"calculation" binding:"required,oneof=flat percent"`
+	Amount         float64 `json:"amount" binding:"required,gt=0"`
+	Treatment      string  `json:"treatment" binding:"required,oneof=deducted financed installment separate"`
+	FrequencyWeeks int     `json:"frequency_weeks" binding:"omitempty,gt=0"`
+}
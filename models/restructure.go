@@ -0,0 +1,95 @@
+package models
+
+import "time"
+
+// Restructure records one change of a loan's schedule. The open items of
+// schedule FromVersion were closed and replaced by schedule ToVersion.
+type Restructure struct {
+	ID              int       `json:"id"`
+	LoanID          int       `json:"loan_id"`
+	FromVersion     int       `json:"from_version"`
+	ToVersion       int       `json:"to_version"`
+	RestructureDate time.Time `json:"restructure_date"`
+	TermWeeks       int       `json:"term_weeks"`
+	InterestRate    float64   `json:"interest_rate"`
+	HolidayWeeks    int       `json:"holiday_weeks"`
+	// NewPrincipal is RemainingPrincipal plus CapitalisedArrears: the overdue
+	// interest and fees folded into the new schedule.
+	RemainingPrincipal float64 `json:"remaining_principal"`
+	CapitalisedArrears float64 `json:"capitalised_arrears"`
+	NewPrincipal       float64 `json:"new_principal"`
+	WeeklyAmount       float64 `json:"weekly_amount"`
+	// RecognisedInterest is the interest income recognised on the loan when
+	// the new schedule started; accrual continues from there.
+	RecognisedInterest float64   `json:"recognised_interest"`
+	Reason             string    `json:"reason"`
+	RequestedBy        string    `json:"requested_by"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+type RestructureRequest struct {
+	TermWeeks int `json:"term_weeks" binding:"required,gt=0"`
+	// InterestRate defaults to the loan's current rate when left out; 0
+	// makes the new schedule interest-free.
+	InterestRate *float64 `json:"interest_rate" binding:"omitempty,gte=0"`
+	// HolidayWeeks defers the first new installment by that many weeks.
+	HolidayWeeks      int    `json:"holiday_weeks" binding:"gte=0"`
+	CapitaliseArrears bool   `json:"capitalise_arrears"`
+	Reason            string `json:"reason" binding:"required"`
+}
+
+// RestructureTerms are the parsed inputs for restructuring a loan.
+type RestructureTerms struct {
+	TermWeeks int
+	// InterestRate is nil to keep the loan's current rate.
+	InterestRate      *float64
+	HolidayWeeks      int
+	CapitaliseArrears bool
+	Reason            string
+	RequestedBy       string
+}
+
+// Delinquency classifications.
+const (
+	DelinquencyCurrent    = "current"
+	DelinquencyPastDue    = "past_due"
+	DelinquencyDelinquent = "delinquent"
+)
+
+// DelinquencyStatus classifies a loan as of the business date. Restructured
+// loans are flagged so they can be reported apart from ones that never
+// needed forbearance.
+type DelinquencyStatus struct {
+	Delinquent      bool   `json:"delinquent"`
+	Classification  string `json:"classification"`
+	DaysPastDue     int    `json:"days_past_due"`
+	Restructured    bool   `json:"restructured"`
+	ScheduleVersion int    `json:"schedule_version"`
+}
+
+// LoanDelinquency is one loan's line in a delinquency sweep.
+type LoanDelinquency struct {
+	LoanID     int    `json:"loan_id"`
+	BorrowerID string `json:"borrower_id,omitempty"`
+	DelinquencyStatus
+}
+
+// DelinquencySweep classifies every active loan as of a business date.
+type DelinquencySweep struct {
+	BusinessDate   time.Time         `json:"business_date"`
+	LoansProcessed int               `json:"loans_processed"`
+	Current        int               `json:"current"`
+	PastDue        int               `json:"past_due"`
+	Delinquent     int               `json:"delinquent"`
+	Loans          []LoanDelinquency `json:"loans"`
+	Errors         []string          `json:"errors,omitempty"`
+}
+
+// LoanSchedule is one version of a loan's installment schedule.
+type LoanSchedule struct {
+	LoanID       int           `json:"loan_id"`
+	Version      int           `json:"version"`
+	Current      bool          `json:"current"`
+	Installments []Installment `json:"installments"`
+	Charges      []LoanCharge  `json:"charges,omitempty"`
+}
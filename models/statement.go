@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Statement entry statuses. Pending, unmatched and failed entries form the
+// reconciliation queue; resolved and dismissed ones left it by hand.
+const (
+	StatementEntryPending   = "pending"
+	StatementEntryPosted    = "posted"
+	StatementEntryUnmatched = "unmatched"
+	StatementEntryFailed    = "failed"
+	StatementEntryIgnored   = "ignored"
+	StatementEntryResolved  = "resolved"
+	StatementEntryDismissed = "dismissed"
+)
+
+// BankStatement is one imported statement file. The counts are those of the
+// import: Duplicates were already imported from an earlier statement and
+// Ignored are debits.
+type BankStatement struct {
+	ID           int              `json:"id"`
+	Format       string           `json:"format"`
+	Account      string           `json:"account,omitempty"`
+	FileName     string           `json:"file_name,omitempty"`
+	TotalEntries int              `json:"total_entries"`
+	Posted       int              `json:"posted"`
+	Queued       int              `json:"queued"`
+	Duplicates   int              `json:"duplicates"`
+	Ignored      int              `json:"ignored"`
+	ImportedBy   string           `json:"imported_by"`
+	ImportedAt   time.Time        `json:"imported_at"`
+	Entries      []StatementEntry `json:"entries,omitempty"`
+}
+
+// StatementEntry is one bank transaction and how it was reconciled. Error
+// explains why an entry is in the queue; for a dismissed entry it holds the
+// reason given.
+type StatementEntry struct {
+	ID          int `json:"id"`
+	StatementID int `json:"statement_id"`
+	// StatementAccount is the account of the entry's statement; transaction
+	// IDs are unique per account.
+	StatementAccount string     `json:"statement_account,omitempty"`
+	TransactionID    string     `json:"transaction_id"`
+	BookingDate      time.Time  `json:"booking_date"`
+	Amount           float64    `json:"amount"`
+	Credit           bool       `json:"credit"`
+	Currency         string     `json:"currency,omitempty"`
+	Reference        string     `json:"reference,omitempty"`
+	Account          string     `json:"account,omitempty"`
+	Status           string     `json:"status"`
+	LoanID           *int       `json:"loan_id,omitempty"`
+	PaymentID        *int       `json:"payment_id,omitempty"`
+	Error            string     `json:"error,omitempty"`
+	ResolvedBy       string     `json:"resolved_by,omitempty"`
+	ResolvedAt       *time.Time `json:"resolved_at,omitempty"`
+}
+
+type ResolveEntryRequest struct {
+	LoanID int `json:"loan_id" binding:"required,gt=0"`
+}
+
+type DismissEntryRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AllEvents subscribes to every event type, including ones added later.
+const AllEvents = "*"
+
+// Webhook delivery statuses. Pending deliveries are sent by the dispatcher;
+// dead ones ran out of attempts and wait in the dead-letter list for a
+// manual redelivery.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// Subscription is a partner endpoint that domain events are pushed to.
+// Secret signs the deliveries; it is only shown when the subscription is
+// created or its secret rotated. After a rotation the previous secret keeps
+// signing alongside it until PreviousSecretExpiresAt, so that the partner
+// can switch over without dropping deliveries.
+type Subscription struct {
+	ID                      int        `json:"id"`
+	Name                    string     `json:"name"`
+	URL                     string     `json:"url"`
+	EventTypes              []string   `json:"event_types"`
+	Active                  bool       `json:"active"`
+	Secret                  string     `json:"secret,omitempty"`
+	PreviousSecret          string     `json:"-"`
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at,omitempty"`
+	CreatedBy               string     `json:"created_by"`
+	CreatedAt               time.Time  `json:"created_at"`
+	UpdatedAt               time.Time  `json:"updated_at"`
+}
+
+type CreateSubscriptionRequest struct {
+	Name       string   `json:"name" binding:"required"`
+	URL        string   `json:"url" binding:"required,url"`
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+}
+
+// UpdateSubscriptionRequest changes the fields that are set.
+type UpdateSubscriptionRequest struct {
+	Name       *string  `json:"name"`
+	URL        *string  `json:"url" binding:"omitempty,url"`
+	EventTypes []string `json:"event_types"`
+	Active     *bool    `json:"active"`
+}
+
+// WebhookDelivery is one event pushed to one subscription. Payload is the
+// event as posted; Log lists the attempts made, on a single delivery only.
+type WebhookDelivery struct {
+	ID             int               `json:"id"`
+	SubscriptionID int               `json:"subscription_id"`
+	EventID        int64             `json:"event_id"`
+	EventType      string            `json:"event_type"`
+	Payload        json.RawMessage   `json:"payload" swaggertype:"object"`
+	Status         string            `json:"status"`
+	Attempts       int               `json:"attempts"`
+	LastStatusCode int               `json:"last_status_code,omitempty"`
+	LastError      string            `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time         `json:"next_attempt_at"`
+	CreatedAt      time.Time         `json:"created_at"`
+	DeliveredAt    *time.Time        `json:"delivered_at,omitempty"`
+	Log            []DeliveryAttempt `json:"log,omitempty"`
+}
+
+// DeliveryAttempt is one POST of a delivery. StatusCode is zero when no
+// answer came back; Response holds the start of the answer's body.
+type DeliveryAttempt struct {
+	ID          int       `json:"id"`
+	DeliveryID  int       `json:"delivery_id"`
+	AttemptedAt time.Time `json:"attempted_at"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	DurationMS  int       `json:"duration_ms"`
+	Response    string    `json:"response,omitempty"`
+}
+
+// DeliveryFilter narrows a delivery listing; empty fields match all.
+type DeliveryFilter struct {
+	SubscriptionID int
+	EventID        int64
+	Status         string
+}
+
+// DeliveryDispatch counts the outcome of one pass of the dispatcher.
+type DeliveryDispatch struct {
+	Delivered int `json:"delivered"`
+	Retrying  int `json:"retrying"`
+	Dead      int `json:"dead"`
+}
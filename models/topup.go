@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// TopUp links a new loan to the loan it refinanced. The previous loan's
+// outstanding balance was settled internally out of the new loan, so only
+// NetNewCash is paid out to the borrower.
+type TopUp struct {
+	ID                  int       `json:"id"`
+	LoanID              int       `json:"loan_id"`
+	PreviousLoanID      int       `json:"previous_loan_id"`
+	SettlementPaymentID int       `json:"settlement_payment_id"`
+	SettlementAmount    float64   `json:"settlement_amount"`
+	TopUpAmount         float64   `json:"topup_amount"`
+	NetNewCash          float64   `json:"net_new_cash"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// TopUpRequest takes the terms of the new loan. Its principal is the previous
+// loan's outstanding balance plus Amount.
+type TopUpRequest struct {
+	Amount       float64 `json:"amount" binding:"required,gt=0"`
+	InterestRate float64 `json:"interest_rate" binding:"required,gt=0"`
+	TermWeeks    int     `json:"term_weeks" binding:"required,gt=0"`
+	ProductCode  string  `json:"product_code,omitempty"`
+}
+
+// TopUpResult is the outcome of a top-up: the link record and the new loan.
+type TopUpResult struct {
+	TopUp TopUp `json:"topup"`
+	Loan  Loan  `json:"loan"`
+}
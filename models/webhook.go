@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Webhook event statuses. Received and failed events can be replayed.
+const (
+	WebhookEventReceived  = "received"
+	WebhookEventProcessed = "processed"
+	WebhookEventIgnored   = "ignored"
+	WebhookEventFailed    = "failed"
+)
+
+// WebhookEvent is a payment processor notification. Payload is the body
+// exactly as signed by the provider.
+type WebhookEvent struct {
+	ID          int        `json:"id"`
+	Provider    string     `json:"provider"`
+	EventID     string     `json:"event_id"`
+	EventType   string     `json:"event_type,omitempty"`
+	Payload     string     `json:"payload"`
+	Status      string     `json:"status"`
+	LoanID      *int       `json:"loan_id,omitempty"`
+	Amount      *float64   `json:"amount,omitempty"`
+	PaymentID   *int       `json:"payment_id,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Attempts    int        `json:"attempts"`
+	ReceivedAt  time.Time  `json:"received_at"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Write-off request statuses.
+const (
+	WriteOffStatusPending  = "pending"
+	WriteOffStatusApproved = "approved"
+	WriteOffStatusRejected = "rejected"
+)
+
+// WriteOff is a request to move a loan's outstanding balance off the active
+// book. It takes effect only once approved by a different user.
+type WriteOff struct {
+	ID          int        `json:"id"`
+	LoanID      int        `json:"loan_id"`
+	Amount      float64    `json:"amount"`
+	Reason      string     `json:"reason"`
+	Status      string     `json:"status"`
+	RequestedBy string     `json:"requested_by"`
+	RequestedAt time.Time  `json:"requested_at"`
+	DecidedBy   string     `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+}
+
+type WriteOffRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
@@ -0,0 +1,131 @@
+// Package apperror defines domain errors with stable, machine-readable codes.
+// The HTTP layer maps them to responses by kind, so use cases never deal in
+// status codes.
+package apperror
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Kind classifies an error for mapping to a transport status.
+type Kind int
+
+const (
+	Internal      Kind = iota
+	Invalid            // malformed request
+	NotFound           // the resource does not exist
+	Conflict           // the resource is in the wrong state
+	Forbidden          // the caller may not do this
+	Unprocessable      // well-formed but breaks a business rule
+	Unauthorized       // the caller's credentials are missing or wrong
+)
+
+// Error is a domain error. Errors with the same code match under errors.Is,
+// so a sentinel still matches after Withf, Wrap or WithField.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	// Fields are extra members for the error response.
+	Fields map[string]any
+	cause  error
+}
+
+// New returns a sentinel error.
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Message + ": " + e.cause.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Withf returns a copy of e with a more specific message.
+func (e *Error) Withf(format string, args ...any) *Error {
+	c := *e
+	c.Message = fmt.Sprintf(format, args...)
+	return &c
+}
+
+// Wrap returns a copy of e caused by err.
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.cause = err
+	return &c
+}
+
+// WithField returns a copy of e with an extra response member.
+func (e *Error) WithField(key string, value any) *Error {
+	c := *e
+	c.Fields = make(map[string]any, len(e.Fields)+1)
+	for k, v := range e.Fields {
+		c.Fields[k] = v
+	}
+	c.Fields[key] = value
+	return &c
+}
+
+// Generic errors for failures without a more specific code.
+var (
+	ErrInternal      = New(Internal, "internal_error", "internal server error")
+	ErrInvalid       = New(Invalid, "invalid_request", "invalid request")
+	ErrNotFound      = New(NotFound, "not_found", "resource not found")
+	ErrConflict      = New(Conflict, "conflict", "request conflicts with the current state")
+	ErrForbidden     = New(Forbidden, "forbidden", "access denied")
+	ErrUnprocessable = New(Unprocessable, "unprocessable", "request cannot be processed")
+	ErrUnauthorized  = New(Unauthorized, "unauthorized", "authentication failed")
+)
+
+// Lookup returns the first Error in err's chain.
+func Lookup(err error) (*Error, bool) {
+	var e *Error
+	ok := errors.As(err, &e)
+	return e, ok
+}
+
+// Default classifies an error that carries no code of its own as the generic
+// error of the given kind. Coded errors are returned unchanged and
+// sql.ErrNoRows becomes not_found.
+func Default(err error, kind Kind) error {
+	if _, ok := Lookup(err); ok {
+		return err
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound.Wrap(err)
+	}
+	return generic[kind].Withf("%s", err.Error())
+}
+
+// Invalidf returns a generic invalid_request error.
+func Invalidf(format string, args ...any) *Error {
+	return ErrInvalid.Withf(format, args...)
+}
+
+// NotFoundf returns a generic not_found error.
+func NotFoundf(format string, args ...any) *Error {
+	return ErrNotFound.Withf(format, args...)
+}
+
+var generic = map[Kind]*Error{
+	Internal:      ErrInternal,
+	Invalid:       ErrInvalid,
+	NotFound:      ErrNotFound,
+	Conflict:      ErrConflict,
+	Forbidden:     ErrForbidden,
+	Unprocessable: ErrUnprocessable,
+	Unauthorized:  ErrUnauthorized,
+}
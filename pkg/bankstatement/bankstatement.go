@@ -0,0 +1,99 @@
+// Package bankstatement reads bank account statements in CSV, SWIFT MT940 and
+// ISO 20022 camt.053 format into a common list of transactions.
+package bankstatement
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Statement formats.
+const (
+	FormatCSV     = "csv"
+	FormatMT940   = "mt940"
+	FormatCamt053 = "camt053"
+)
+
+// ErrUnknownFormat is returned for a format other than the ones above.
+var ErrUnknownFormat = errors.New("unknown statement format")
+
+// Statement is the parsed content of one statement file.
+type Statement struct {
+	Format string
+	// Account is the statement's account number, when the format carries one.
+	Account      string
+	Transactions []Transaction
+}
+
+// Transaction is one booked line of a statement.
+type Transaction struct {
+	// ID is the bank's unique reference for the transaction or, where the bank
+	// assigned none, one derived from the transaction's place in the statement.
+	ID          string
+	BookingDate time.Time
+	// Amount is always positive; Credit tells the direction.
+	Amount   float64
+	Credit   bool
+	Currency string
+	// Reference is the remittance information or narrative.
+	Reference string
+	// Account is the account credited within the statement account, such as
+	// a virtual account number, when the bank reports one.
+	Account string
+}
+
+// Parse reads a statement in the given format. An empty format is detected
+// from the content.
+func Parse(format string, r io.Reader) (*Statement, error) {
+	br := bufio.NewReader(r)
+	if format == "" {
+		format = detect(br)
+	}
+
+	var (
+		s   *Statement
+		err error
+	)
+	switch format {
+	case FormatCSV:
+		s, err = parseCSV(br)
+	case FormatMT940:
+		s, err = parseMT940(br)
+	case FormatCamt053:
+		s, err = parseCamt053(br)
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s statement: %w", format, err)
+	}
+	s.Format = format
+	return s, nil
+}
+
+// derivedID identifies a transaction the bank gave no reference of its own by
+// the account, the statement, its position in the statement and its amount.
+// References set by the payer, such as an end-to-end ID, are not unique and so
+// are never used.
+func derivedID(account, statementRef, position string, amount float64) string {
+	return fmt.Sprintf("%s:%s/%s:%.2f", account, statementRef, position, amount)
+}
+
+// detect guesses the format from the start of the file: XML is camt.053, a
+// SWIFT block or tag is MT940 and anything else is CSV.
+func detect(br *bufio.Reader) string {
+	head, _ := br.Peek(512)
+	head = bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")), " \t\r\n")
+	switch {
+	case bytes.HasPrefix(head, []byte("<")):
+		return FormatCamt053
+	case bytes.HasPrefix(head, []byte("{1:")), bytes.HasPrefix(head, []byte(":20:")):
+		return FormatMT940
+	default:
+		return FormatCSV
+	}
+}
@@ -0,0 +1,37 @@
+package bankstatement
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseDetectsFormat(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "csv", input: "transaction_id,date,amount\nT1,2026-03-02,1\n", want: FormatCSV},
+		{name: "mt940 in a SWIFT block", input: "{1:F01BANKIDJAXXXX0000000000}{4:\n:20:S1\n-}", want: FormatMT940},
+		{name: "bare mt940", input: ":20:S1\n:25:ACC1\n", want: FormatMT940},
+		{name: "camt.053 after a byte order mark", input: "\ufeff\n  " + camtStatementXML(""), want: FormatCamt053},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse("", strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if s.Format != tt.want {
+				t.Errorf("format = %q, want %q", s.Format, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnknownFormat(t *testing.T) {
+	if _, err := Parse("bai2", strings.NewReader("")); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Parse() error = %v, want %v", err, ErrUnknownFormat)
+	}
+}
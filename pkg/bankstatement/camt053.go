@@ -0,0 +1,192 @@
+package bankstatement
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// camt.053 elements, matched by local name so any schema version works.
+type camtDocument struct {
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	ID      string      `xml:"Id"`
+	Account camtAccount `xml:"Acct"`
+	Entries []camtEntry `xml:"Ntry"`
+}
+
+type camtAccount struct {
+	IBAN  string `xml:"Id>IBAN"`
+	Other string `xml:"Id>Othr>Id"`
+}
+
+func (a camtAccount) number() string {
+	if a.IBAN != "" {
+		return a.IBAN
+	}
+	return a.Other
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+// camtStatus is a plain code before camt.053.001.08 and a Cd element from it on.
+type camtStatus struct {
+	Text string `xml:",chardata"`
+	Code string `xml:"Cd"`
+}
+
+func (s camtStatus) code() string {
+	return firstNonEmpty(s.Code, s.Text)
+}
+
+type camtEntry struct {
+	Ref         string        `xml:"NtryRef"`
+	Amount      camtAmount    `xml:"Amt"`
+	Indicator   string        `xml:"CdtDbtInd"`
+	Reversal    bool          `xml:"RvslInd"`
+	Status      camtStatus    `xml:"Sts"`
+	BookingDate camtDate      `xml:"BookgDt"`
+	ValueDate   camtDate      `xml:"ValDt"`
+	ServicerRef string        `xml:"AcctSvcrRef"`
+	Details     []camtDetails `xml:"NtryDtls>TxDtls"`
+	Info        string        `xml:"AddtlNtryInf"`
+}
+
+type camtDetails struct {
+	ServicerRef   string      `xml:"Refs>AcctSvcrRef"`
+	Amount        *camtAmount `xml:"Amt"`
+	TxAmount      *camtAmount `xml:"AmtDtls>TxAmt>Amt"`
+	Indicator     string      `xml:"CdtDbtInd"`
+	Unstructured  []string    `xml:"RmtInf>Ustrd"`
+	CreditorRef   string      `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+	CreditorIBAN  string      `xml:"RltdPties>CdtrAcct>Id>IBAN"`
+	CreditorOther string      `xml:"RltdPties>CdtrAcct>Id>Othr>Id"`
+	Info          string      `xml:"AddtlTxInf"`
+}
+
+// parseCamt053 reads booked entries. An entry that batches several
+// transactions yields one Transaction per transaction detail.
+func parseCamt053(r io.Reader) (*Statement, error) {
+	var doc camtDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Statements) == 0 {
+		return nil, errors.New("no Stmt element found")
+	}
+
+	s := &Statement{Account: doc.Statements[0].Account.number()}
+	for _, stmt := range doc.Statements {
+		for i, entry := range stmt.Entries {
+			if status := entry.Status.code(); status != "" && status != "BOOK" {
+				continue // pending or informational
+			}
+			txns, err := camtTransactions(stmt, i+1, entry)
+			if err != nil {
+				return nil, fmt.Errorf("entry %d: %w", i+1, err)
+			}
+			s.Transactions = append(s.Transactions, txns...)
+		}
+	}
+	return s, nil
+}
+
+// camtTransactions identifies each transaction by the account servicer's
+// reference: the detail's own, or the entry's when the entry holds a single
+// transaction. Otherwise the ID is derived from the statement, the entry's
+// position and the detail's.
+func camtTransactions(stmt camtStatement, seq int, entry camtEntry) ([]Transaction, error) {
+	date, err := entry.BookingDate.parse()
+	if err != nil {
+		if date, err = entry.ValueDate.parse(); err != nil {
+			return nil, errors.New("missing booking date")
+		}
+	}
+	credit := (entry.Indicator == "CRDT") != entry.Reversal
+
+	details := entry.Details
+	if len(details) == 0 {
+		details = []camtDetails{{}}
+	}
+	var txns []Transaction
+	for j, d := range details {
+		amount := entry.Amount
+		if len(entry.Details) > 1 {
+			switch {
+			case d.Amount != nil:
+				amount = *d.Amount
+			case d.TxAmount != nil:
+				amount = *d.TxAmount
+			default:
+				return nil, errors.New("batched transaction without an amount")
+			}
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(amount.Value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount %q", amount.Value)
+		}
+
+		txn := Transaction{
+			ID:          strings.TrimSpace(d.ServicerRef),
+			BookingDate: date,
+			Amount:      value,
+			Credit:      credit,
+			Currency:    amount.Currency,
+			Reference:   firstNonEmpty(d.CreditorRef, strings.Join(d.Unstructured, " "), d.Info, entry.Info),
+			Account:     firstNonEmpty(d.CreditorOther, d.CreditorIBAN),
+		}
+		if d.Indicator != "" {
+			txn.Credit = (d.Indicator == "CRDT") != entry.Reversal
+		}
+		if txn.ID == "" && len(entry.Details) <= 1 {
+			txn.ID = firstNonEmpty(entry.ServicerRef, entry.Ref)
+		}
+		if txn.ID == "" {
+			txn.ID = derivedID(stmt.Account.number(), stmt.ID, fmt.Sprintf("%d.%d", seq, j+1), txn.Amount)
+		}
+		txns = append(txns, txn)
+	}
+	return txns, nil
+}
+
+func (d camtDate) parse() (time.Time, error) {
+	if d.Date != "" {
+		return time.Parse("2006-01-02", strings.TrimSpace(d.Date))
+	}
+	if d.DateTime != "" {
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(d.DateTime))
+		if err != nil {
+			// ISO 8601 without a zone is also allowed.
+			t, err = time.Parse("2006-01-02T15:04:05", strings.TrimSpace(d.DateTime))
+		}
+		if err != nil {
+			return time.Time{}, err
+		}
+		y, m, day := t.Date()
+		return time.Date(y, m, day, 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Time{}, errors.New("no date")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,128 @@
+package bankstatement
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func camtStatementXML(entries string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Id>STMT-0302</Id>
+      <Acct><Id><IBAN>ID12BANK0001234567</IBAN></Id></Acct>
+      ` + entries + `
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+}
+
+func TestParseCamt053(t *testing.T) {
+	const account = "ID12BANK0001234567"
+	tests := []struct {
+		name    string
+		input   string
+		want    []Transaction
+		wantErr string
+	}{
+		{
+			name: "single transaction identified by the entry",
+			input: camtStatementXML(`<Ntry>
+        <NtryRef>E1</NtryRef>
+        <Amt Ccy="IDR">110000.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2026-03-02</Dt></BookgDt>
+        <AcctSvcrRef>SVC-1</AcctSvcrRef>
+        <NtryDtls><TxDtls>
+          <Refs><EndToEndId>PAYER-1</EndToEndId></Refs>
+          <RltdPties><CdtrAcct><Id><Othr><Id>8808000000123</Id></Othr></Id></CdtrAcct></RltdPties>
+          <RmtInf><Ustrd>Loan 12</Ustrd></RmtInf>
+        </TxDtls></NtryDtls>
+      </Ntry>`),
+			want: []Transaction{
+				{ID: "SVC-1", BookingDate: date(2026, 3, 2), Amount: 110000, Credit: true, Currency: "IDR", Reference: "Loan 12", Account: "8808000000123"},
+			},
+		},
+		{
+			name: "batch without servicer references gets derived IDs",
+			input: camtStatementXML(`<Ntry>
+        <NtryRef>BATCH-1</NtryRef>
+        <Amt Ccy="IDR">300.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts><Cd>BOOK</Cd></Sts>
+        <BookgDt><DtTm>2026-03-02T09:30:00+07:00</DtTm></BookgDt>
+        <AcctSvcrRef>SVC-BATCH</AcctSvcrRef>
+        <NtryDtls>
+          <TxDtls><Amt Ccy="IDR">100.00</Amt><RmtInf><Ustrd>Loan 1</Ustrd></RmtInf></TxDtls>
+          <TxDtls><AmtDtls><TxAmt><Amt Ccy="IDR">200.00</Amt></TxAmt></AmtDtls><RmtInf><Ustrd>Loan 2</Ustrd></RmtInf></TxDtls>
+        </NtryDtls>
+      </Ntry>`),
+			want: []Transaction{
+				{ID: account + ":STMT-0302/1.1:100.00", BookingDate: date(2026, 3, 2), Amount: 100, Credit: true, Currency: "IDR", Reference: "Loan 1"},
+				{ID: account + ":STMT-0302/1.2:200.00", BookingDate: date(2026, 3, 2), Amount: 200, Credit: true, Currency: "IDR", Reference: "Loan 2"},
+			},
+		},
+		{
+			name: "pending entries are skipped and reversals flip the direction",
+			input: camtStatementXML(`<Ntry>
+        <Amt Ccy="IDR">50.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>PDNG</Sts>
+        <BookgDt><Dt>2026-03-02</Dt></BookgDt>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="IDR">75.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <RvslInd>true</RvslInd>
+        <Sts>BOOK</Sts>
+        <ValDt><Dt>2026-03-03</Dt></ValDt>
+        <AddtlNtryInf>returned debit</AddtlNtryInf>
+      </Ntry>`),
+			want: []Transaction{
+				{ID: account + ":STMT-0302/2.1:75.00", BookingDate: date(2026, 3, 3), Amount: 75, Credit: true, Currency: "IDR", Reference: "returned debit"},
+			},
+		},
+		{
+			name: "batched transaction without an amount",
+			input: camtStatementXML(`<Ntry>
+        <Amt Ccy="IDR">300.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <BookgDt><Dt>2026-03-02</Dt></BookgDt>
+        <NtryDtls><TxDtls/><TxDtls/></NtryDtls>
+      </Ntry>`),
+			wantErr: "entry 1: batched transaction without an amount",
+		},
+		{
+			name: "missing booking date",
+			input: camtStatementXML(`<Ntry>
+        <Amt Ccy="IDR">1.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+      </Ntry>`),
+			wantErr: "entry 1: missing booking date",
+		},
+		{name: "no statement", input: `<Document><BkToCstmrStmt/></Document>`, wantErr: "no Stmt element found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCamt053(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseCamt053() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCamt053() error = %v", err)
+			}
+			if s.Account != account {
+				t.Errorf("account = %q, want %q", s.Account, account)
+			}
+			if !reflect.DeepEqual(s.Transactions, tt.want) {
+				t.Errorf("parseCamt053() = %+v, want %+v", s.Transactions, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package bankstatement
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSV statements have a header row. transaction_id, date (YYYY-MM-DD) and
+// amount are required; a negative amount is a debit unless a type column
+// (credit/debit or C/D) says otherwise. currency, reference and account are
+// optional.
+func parseCSV(r io.Reader) (*Statement, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("file is empty")
+		}
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, col := range []string{"transaction_id", "date", "amount"} {
+		if _, ok := columns[col]; !ok {
+			return nil, fmt.Errorf("missing column %q", col)
+		}
+	}
+
+	s := &Statement{}
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		get := func(col string) string {
+			if i, ok := columns[col]; ok && i < len(fields) {
+				return strings.TrimSpace(fields[i])
+			}
+			return ""
+		}
+
+		txn := Transaction{
+			ID:        get("transaction_id"),
+			Currency:  get("currency"),
+			Reference: get("reference"),
+			Account:   get("account"),
+		}
+		if txn.ID == "" {
+			return nil, fmt.Errorf("line %d: transaction_id is required", line)
+		}
+		if txn.BookingDate, err = time.Parse("2006-01-02", get("date")); err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", line, get("date"))
+		}
+		amount, err := strconv.ParseFloat(get("amount"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid amount %q", line, get("amount"))
+		}
+		txn.Credit = amount > 0
+		switch strings.ToLower(get("type")) {
+		case "":
+		case "c", "cr", "credit":
+			txn.Credit = true
+		case "d", "dr", "debit":
+			txn.Credit = false
+		default:
+			return nil, fmt.Errorf("line %d: invalid type %q", line, get("type"))
+		}
+		if amount < 0 {
+			amount = -amount
+		}
+		txn.Amount = amount
+		s.Transactions = append(s.Transactions, txn)
+	}
+	return s, nil
+}
@@ -0,0 +1,62 @@
+package bankstatement
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCSV(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		input   string
+		want    []Transaction
+		wantErr string
+	}{
+		{
+			name: "signed amounts",
+			input: "transaction_id,date,amount,currency,reference,account\n" +
+				"T1,2026-03-02,110000,IDR,Loan 12,8808000000123\n" +
+				"T2,2026-03-02,-5000,IDR,bank fee,\n",
+			want: []Transaction{
+				{ID: "T1", BookingDate: day, Amount: 110000, Credit: true, Currency: "IDR", Reference: "Loan 12", Account: "8808000000123"},
+				{ID: "T2", BookingDate: day, Amount: 5000, Currency: "IDR", Reference: "bank fee"},
+			},
+		},
+		{
+			name: "type column overrides the sign",
+			input: "\ufeffTransaction_ID, Date, Amount, Type\n" +
+				"T1,2026-03-02,250.50,CR\n" +
+				"T2,2026-03-02,100,debit\n",
+			want: []Transaction{
+				{ID: "T1", BookingDate: day, Amount: 250.50, Credit: true},
+				{ID: "T2", BookingDate: day, Amount: 100},
+			},
+		},
+		{name: "empty file", input: "", wantErr: "file is empty"},
+		{name: "missing column", input: "transaction_id,date\nT1,2026-03-02\n", wantErr: `missing column "amount"`},
+		{name: "missing id", input: "transaction_id,date,amount\n,2026-03-02,1\n", wantErr: "line 2: transaction_id is required"},
+		{name: "invalid date", input: "transaction_id,date,amount\nT1,02/03/2026,1\n", wantErr: `line 2: invalid date "02/03/2026"`},
+		{name: "invalid amount", input: "transaction_id,date,amount\nT1,2026-03-02,1.000.00\n", wantErr: `line 2: invalid amount "1.000.00"`},
+		{name: "invalid type", input: "transaction_id,date,amount,type\nT1,2026-03-02,1,X\n", wantErr: `line 2: invalid type "X"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCSV(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseCSV() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCSV() error = %v", err)
+			}
+			if !reflect.DeepEqual(s.Transactions, tt.want) {
+				t.Errorf("parseCSV() = %+v, want %+v", s.Transactions, tt.want)
+			}
+		})
+	}
+}
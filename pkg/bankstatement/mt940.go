@@ -0,0 +1,150 @@
+package bankstatement
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// statementLine matches the first line of an MT940 :61: field: value date,
+// optional entry date, debit/credit mark, funds code, amount, transaction
+// type, customer reference and optional bank reference. Only the bank
+// reference identifies the transaction.
+var statementLine = regexp.MustCompile(`^(\d{6})(\d{4})?(R?[CD])([A-Z])?(\d+,\d*)([NFS][A-Z0-9]{3})(.*?)(?://(.*))?$`)
+
+type mt940Field struct {
+	tag   string
+	value string
+}
+
+// parseMT940 reads one or more MT940 messages. The :86: field after a
+// statement line becomes its Reference.
+func parseMT940(r io.Reader) (*Statement, error) {
+	fields, err := mt940Fields(r)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Statement{}
+	var account, statementRef, currency string
+	seq := 0
+	var last *Transaction
+	for _, f := range fields {
+		switch f.tag {
+		case "20":
+			statementRef = strings.TrimSpace(f.value)
+			seq = 0
+		case "25":
+			account = strings.TrimSpace(f.value)
+			if s.Account == "" {
+				s.Account = account
+			}
+		case "60F", "60M":
+			// Mark, YYMMDD, then the currency.
+			if len(f.value) >= 10 {
+				currency = f.value[7:10]
+			}
+		case "61":
+			seq++
+			txn, err := parseStatementLine(f.value)
+			if err != nil {
+				return nil, err
+			}
+			txn.Currency = currency
+			if txn.ID == "" {
+				txn.ID = derivedID(account, statementRef, strconv.Itoa(seq), txn.Amount)
+			}
+			s.Transactions = append(s.Transactions, txn)
+			last = &s.Transactions[len(s.Transactions)-1]
+		case "86":
+			if last != nil {
+				last.Reference = strings.Join(strings.Fields(f.value), " ")
+				last = nil
+			}
+		default:
+			last = nil
+		}
+	}
+	return s, nil
+}
+
+// mt940Fields splits the message text into tagged fields, dropping the SWIFT
+// block wrappers. Lines that do not start a tag continue the previous field.
+func mt940Fields(r io.Reader) ([]mt940Field, error) {
+	var fields []mt940Field
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if i := strings.Index(line, "{4:"); i >= 0 {
+			line = line[i+3:]
+		}
+		if line == "" || line == "-}" || strings.HasPrefix(line, "{") {
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			if end := strings.Index(line[1:], ":"); end > 0 {
+				fields = append(fields, mt940Field{tag: line[1 : end+1], value: line[end+2:]})
+				continue
+			}
+		}
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("unexpected line %q before the first field", line)
+		}
+		fields[len(fields)-1].value += "\n" + line
+	}
+	return fields, scanner.Err()
+}
+
+func parseStatementLine(value string) (Transaction, error) {
+	first, _, _ := strings.Cut(value, "\n")
+	m := statementLine.FindStringSubmatch(strings.TrimSpace(first))
+	if m == nil {
+		return Transaction{}, fmt.Errorf("invalid :61: statement line %q", first)
+	}
+
+	valueDate, err := time.Parse("060102", m[1])
+	if err != nil {
+		return Transaction{}, fmt.Errorf("invalid :61: value date %q", m[1])
+	}
+	bookingDate := valueDate
+	if m[2] != "" {
+		if bookingDate, err = entryDate(valueDate, m[2]); err != nil {
+			return Transaction{}, err
+		}
+	}
+	amount, err := strconv.ParseFloat(strings.Replace(m[5], ",", ".", 1), 64)
+	if err != nil {
+		return Transaction{}, fmt.Errorf("invalid :61: amount %q", m[5])
+	}
+
+	txn := Transaction{
+		BookingDate: bookingDate,
+		Amount:      amount,
+		// A reversed debit is a credit and vice versa.
+		Credit: m[3] == "C" || m[3] == "RD",
+	}
+	if bankRef := strings.TrimSpace(m[8]); bankRef != "" && bankRef != "NONREF" {
+		txn.ID = bankRef
+	}
+	return txn, nil
+}
+
+// entryDate resolves the MMDD entry date against the value date's year; the
+// two can straddle a year end.
+func entryDate(valueDate time.Time, mmdd string) (time.Time, error) {
+	d, err := time.Parse("20060102", fmt.Sprintf("%04d%s", valueDate.Year(), mmdd))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid :61: entry date %q", mmdd)
+	}
+	switch {
+	case d.Sub(valueDate) > 180*24*time.Hour:
+		d = d.AddDate(-1, 0, 0)
+	case valueDate.Sub(d) > 180*24*time.Hour:
+		d = d.AddDate(1, 0, 0)
+	}
+	return d, nil
+}
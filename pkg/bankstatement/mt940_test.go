@@ -0,0 +1,93 @@
+package bankstatement
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMT940(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantAccount string
+		want        []Transaction
+		wantErr     string
+	}{
+		{
+			name: "bank references and narrative",
+			input: "{1:F01BANKIDJAXXXX0000000000}{2:O940}{4:\n" +
+				":20:STMT0302\n" +
+				":25:1234567890\n" +
+				":28C:1/1\n" +
+				":60F:C260301IDR1000000,00\n" +
+				":61:2603020302C110000,00NTRFLOAN12//BR0001\n" +
+				":86:VA 8808000000123\n" +
+				"  Loan 12 week 3\n" +
+				":61:260302D5000,00NCHGNONREF\n" +
+				":62F:C260302IDR1105000,00\n" +
+				"-}",
+			wantAccount: "1234567890",
+			want: []Transaction{
+				{ID: "BR0001", BookingDate: date(2026, 3, 2), Amount: 110000, Credit: true, Currency: "IDR", Reference: "VA 8808000000123 Loan 12 week 3"},
+				{ID: "1234567890:STMT0302/2:5000.00", BookingDate: date(2026, 3, 2), Amount: 5000, Currency: "IDR"},
+			},
+		},
+		{
+			name: "customer reference and NONREF are not identifiers",
+			input: ":20:S1\n:25:ACC1\n:60F:C260301IDR0,00\n" +
+				":61:260302C100,00NTRFPAYER-REF\n" +
+				":61:260302C100,00NTRFPAYER-REF//NONREF\n",
+			wantAccount: "ACC1",
+			want: []Transaction{
+				{ID: "ACC1:S1/1:100.00", BookingDate: date(2026, 3, 2), Amount: 100, Credit: true, Currency: "IDR"},
+				{ID: "ACC1:S1/2:100.00", BookingDate: date(2026, 3, 2), Amount: 100, Credit: true, Currency: "IDR"},
+			},
+		},
+		{
+			name: "position restarts with each statement and account",
+			input: ":20:S1\n:25:ACC1\n:60F:C260301IDR0,00\n:61:260302C100,00NTRFX\n" +
+				":20:S1\n:25:ACC2\n:60F:C260301USD0,00\n:61:260302C100,00NTRFX\n",
+			wantAccount: "ACC1",
+			want: []Transaction{
+				{ID: "ACC1:S1/1:100.00", BookingDate: date(2026, 3, 2), Amount: 100, Credit: true, Currency: "IDR"},
+				{ID: "ACC2:S1/1:100.00", BookingDate: date(2026, 3, 2), Amount: 100, Credit: true, Currency: "USD"},
+			},
+		},
+		{
+			name:        "reversal and entry date across the year end",
+			input:       ":20:S1\n:25:ACC1\n:60F:C251231IDR0,00\n:61:2512310102RD75,50NTRFX//BR9\n",
+			wantAccount: "ACC1",
+			want: []Transaction{
+				{ID: "BR9", BookingDate: date(2026, 1, 2), Amount: 75.50, Credit: true, Currency: "IDR"},
+			},
+		},
+		{name: "text before the first field", input: "hello\n:20:S1\n", wantErr: `unexpected line "hello"`},
+		{name: "malformed statement line", input: ":20:S1\n:61:garbage\n", wantErr: `invalid :61: statement line "garbage"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseMT940(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseMT940() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseMT940() error = %v", err)
+			}
+			if s.Account != tt.wantAccount {
+				t.Errorf("account = %q, want %q", s.Account, tt.wantAccount)
+			}
+			if !reflect.DeepEqual(s.Transactions, tt.want) {
+				t.Errorf("parseMT940() = %+v, want %+v", s.Transactions, tt.want)
+			}
+		})
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
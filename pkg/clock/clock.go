@@ -0,0 +1,81 @@
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Clock is the source of the current time for date-dependent business logic.
+type Clock interface {
+	Now() time.Time
+	Location() *time.Location
+}
+
+type realClock struct {
+	loc *time.Location
+}
+
+// New returns a Clock that reads the system time in the given business timezone.
+func New(loc *time.Location) Clock {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &realClock{loc: loc}
+}
+
+func (c *realClock) Now() time.Time {
+	return time.Now().In(c.loc)
+}
+
+func (c *realClock) Location() *time.Location {
+	return c.loc
+}
+
+type fixedClock struct {
+	t time.Time
+}
+
+// NewFixed returns a Clock that always reports t. Useful for tests and simulations.
+func NewFixed(t time.Time) Clock {
+	return &fixedClock{t: t}
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.t
+}
+
+func (c *fixedClock) Location() *time.Location {
+	return c.t.Location()
+}
+
+type asOfKey struct{}
+
+// WithAsOfDate returns a context that overrides the business date used by Today.
+func WithAsOfDate(ctx context.Context, date time.Time) context.Context {
+	return context.WithValue(ctx, asOfKey{}, Date(date))
+}
+
+// AsOfDate reports the business date override stored in ctx, if any.
+func AsOfDate(ctx context.Context) (time.Time, bool) {
+	date, ok := ctx.Value(asOfKey{}).(time.Time)
+	return date, ok
+}
+
+// Today returns the current business date. An override set with WithAsOfDate
+// takes precedence over the clock.
+//
+// The result is midnight UTC of the calendar date in the clock's timezone, which
+// is how DATE columns (e.g. installments.due_date) are scanned from PostgreSQL,
+// so the two can be compared directly.
+func Today(ctx context.Context, c Clock) time.Time {
+	if date, ok := AsOfDate(ctx); ok {
+		return date
+	}
+	return Date(c.Now().In(c.Location()))
+}
+
+// Date strips the time of day from t, keeping its calendar date in t's location.
+func Date(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,106 @@
+package directdebit
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var csvHeader = []string{
+	"end_to_end_id", "collection_date", "amount", "currency", "mandate_id", "mandate_signed",
+	"debtor_name", "debtor_account", "debtor_agent", "remittance",
+}
+
+func writeCSV(w io.Writer, b *Batch) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, in := range b.Instructions {
+		err := cw.Write([]string{
+			in.EndToEndID,
+			b.CollectionDate.Format("2006-01-02"),
+			amount(in.Amount),
+			b.Creditor.Currency,
+			in.MandateID,
+			in.MandateSigned.Format("2006-01-02"),
+			in.DebtorName,
+			in.DebtorAccount,
+			in.DebtorAgent,
+			in.Remittance,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// resultStatuses maps the status column of a CSV result file, which banks
+// fill with words or ISO 20022 transaction status codes.
+var resultStatuses = map[string]string{
+	"collected": ResultCollected,
+	"success":   ResultCollected,
+	"paid":      ResultCollected,
+	"acsc":      ResultCollected,
+	"accc":      ResultCollected,
+	"failed":    ResultFailed,
+	"rejected":  ResultFailed,
+	"returned":  ResultFailed,
+	"rjct":      ResultFailed,
+	"pending":   ResultPending,
+	"pdng":      ResultPending,
+}
+
+// parseResultCSV reads end_to_end_id and status columns, and optional
+// reason_code and reason.
+func parseResultCSV(r io.Reader) ([]Result, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("result file is empty")
+		}
+		return nil, err
+	}
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"end_to_end_id", "status"} {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("missing %s column", required)
+		}
+	}
+	field := func(rec []string, name string) string {
+		if i, ok := cols[name]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+
+	var results []Result
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return results, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		status, ok := resultStatuses[strings.ToLower(field(rec, "status"))]
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown status %q", line, field(rec, "status"))
+		}
+		results = append(results, Result{
+			EndToEndID: field(rec, "end_to_end_id"),
+			Status:     status,
+			ReasonCode: strings.ToUpper(field(rec, "reason_code")),
+			Reason:     field(rec, "reason"),
+		})
+	}
+}
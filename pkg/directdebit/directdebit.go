@@ -0,0 +1,97 @@
+// Package directdebit writes direct debit collection files for the bank and
+// reads the bank's result files back. Collections are exported as CSV or as
+// an ISO 20022 pain.008 customer direct debit initiation; results are read
+// from CSV or a pain.002 payment status report.
+package directdebit
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// File formats.
+const (
+	FormatCSV     = "csv"
+	FormatPain008 = "pain008"
+)
+
+// Result statuses. Pending results are not final and leave the instruction
+// open.
+const (
+	ResultCollected = "collected"
+	ResultFailed    = "failed"
+	ResultPending   = "pending"
+)
+
+var ErrUnknownFormat = errors.New("unknown collection file format")
+
+// Creditor is the lender collecting the payments.
+type Creditor struct {
+	Name    string
+	Account string
+	// Agent is the creditor bank's BIC or bank code.
+	Agent string
+	// SchemeID is the creditor identifier assigned for direct debits.
+	SchemeID string
+	Currency string
+}
+
+// Batch is one collection file: the instructions to collect on one date.
+type Batch struct {
+	MessageID      string
+	CreatedAt      time.Time
+	CollectionDate time.Time
+	Creditor       Creditor
+	Instructions   []Instruction
+}
+
+// Instruction asks the bank to debit one borrower's account.
+type Instruction struct {
+	EndToEndID    string
+	Amount        float64
+	MandateID     string
+	MandateSigned time.Time
+	DebtorName    string
+	DebtorAccount string
+	DebtorAgent   string
+	Remittance    string
+}
+
+// Result is the bank's outcome for one instruction.
+type Result struct {
+	EndToEndID string
+	Status     string
+	ReasonCode string
+	Reason     string
+}
+
+// Write writes the batch in the given format.
+func Write(format string, w io.Writer, b *Batch) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, b)
+	case FormatPain008:
+		return writePain008(w, b)
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+// ParseResults reads a result file, a pain.002 status report when it is XML
+// and CSV otherwise.
+func ParseResults(r io.Reader) ([]Result, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(512)
+	if bytes.HasPrefix(bytes.TrimSpace(bytes.TrimPrefix(head, []byte("\ufeff"))), []byte("<")) {
+		return parsePain002(br)
+	}
+	return parseResultCSV(br)
+}
+
+func amount(v float64) string {
+	return fmt.Sprintf("%.2f", v)
+}
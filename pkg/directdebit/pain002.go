@@ -0,0 +1,64 @@
+package directdebit
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// pain.002 elements, matched by local name so any schema version works.
+type pain002Document struct {
+	Payments []pain002PaymentInfo `xml:"CstmrPmtStsRpt>OrgnlPmtInfAndSts"`
+}
+
+type pain002PaymentInfo struct {
+	Transactions []pain002Transaction `xml:"TxInfAndSts"`
+}
+
+type pain002Transaction struct {
+	EndToEndID string `xml:"OrgnlEndToEndId"`
+	Status     string `xml:"TxSts"`
+	ReasonCode string `xml:"StsRsnInf>Rsn>Cd"`
+	Info       string `xml:"StsRsnInf>AddtlInf"`
+}
+
+// pain002Statuses maps ISO 20022 transaction statuses; the accepted-but-not-
+// settled ones stay pending.
+var pain002Statuses = map[string]string{
+	"ACSC": ResultCollected,
+	"ACCC": ResultCollected,
+	"RJCT": ResultFailed,
+	"PDNG": ResultPending,
+	"ACTC": ResultPending,
+	"ACCP": ResultPending,
+	"ACSP": ResultPending,
+	"ACWC": ResultPending,
+}
+
+func parsePain002(r io.Reader) ([]Result, error) {
+	var doc pain002Document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	var results []Result
+	for _, p := range doc.Payments {
+		for i, tx := range p.Transactions {
+			status, ok := pain002Statuses[strings.TrimSpace(tx.Status)]
+			if !ok {
+				return nil, fmt.Errorf("transaction %d: unknown status %q", i+1, tx.Status)
+			}
+			results = append(results, Result{
+				EndToEndID: strings.TrimSpace(tx.EndToEndID),
+				Status:     status,
+				ReasonCode: strings.TrimSpace(tx.ReasonCode),
+				Reason:     strings.TrimSpace(tx.Info),
+			})
+		}
+	}
+	if len(results) == 0 {
+		return nil, errors.New("no TxInfAndSts element found")
+	}
+	return results, nil
+}
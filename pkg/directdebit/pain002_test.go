@@ -0,0 +1,113 @@
+package directdebit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func pain002XML(transactions string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.002.001.03">
+  <CstmrPmtStsRpt>
+    <GrpHdr><MsgId>STS-1</MsgId></GrpHdr>
+    <OrgnlGrpInfAndSts><OrgnlMsgId>DD-RUN-7</OrgnlMsgId></OrgnlGrpInfAndSts>
+    <OrgnlPmtInfAndSts>
+      <OrgnlPmtInfId>DD-RUN-7</OrgnlPmtInfId>
+      ` + transactions + `
+    </OrgnlPmtInfAndSts>
+  </CstmrPmtStsRpt>
+</Document>`
+}
+
+func TestParsePain002(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Result
+		wantErr string
+	}{
+		{
+			name: "settled, rejected and pending",
+			input: pain002XML(`
+      <TxInfAndSts><OrgnlEndToEndId>DD7-1-1</OrgnlEndToEndId><TxSts>ACSC</TxSts></TxInfAndSts>
+      <TxInfAndSts>
+        <OrgnlEndToEndId> DD7-2-1 </OrgnlEndToEndId>
+        <TxSts>RJCT</TxSts>
+        <StsRsnInf><Rsn><Cd>AM04</Cd></Rsn><AddtlInf>Insufficient funds</AddtlInf></StsRsnInf>
+      </TxInfAndSts>
+      <TxInfAndSts><OrgnlEndToEndId>DD7-3-1</OrgnlEndToEndId><TxSts>ACSP</TxSts></TxInfAndSts>`),
+			want: []Result{
+				{EndToEndID: "DD7-1-1", Status: ResultCollected},
+				{EndToEndID: "DD7-2-1", Status: ResultFailed, ReasonCode: "AM04", Reason: "Insufficient funds"},
+				{EndToEndID: "DD7-3-1", Status: ResultPending},
+			},
+		},
+		{
+			name:    "unknown status",
+			input:   pain002XML(`<TxInfAndSts><OrgnlEndToEndId>DD7-1-1</OrgnlEndToEndId><TxSts>XXXX</TxSts></TxInfAndSts>`),
+			wantErr: `transaction 1: unknown status "XXXX"`,
+		},
+		{name: "no transactions", input: pain002XML(""), wantErr: "no TxInfAndSts element found"},
+		{name: "not XML", input: "<Document>", wantErr: "EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseResults(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseResults() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseResults() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseResults() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseResultCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Result
+		wantErr string
+	}{
+		{
+			name: "words and ISO codes",
+			input: "\ufeffEnd_To_End_ID,Status,Reason_Code,Reason\n" +
+				"DD7-1-1,collected,,\n" +
+				"DD7-2-1,RJCT,ac04,Account closed\n" +
+				"DD7-3-1,pending,,\n",
+			want: []Result{
+				{EndToEndID: "DD7-1-1", Status: ResultCollected},
+				{EndToEndID: "DD7-2-1", Status: ResultFailed, ReasonCode: "AC04", Reason: "Account closed"},
+				{EndToEndID: "DD7-3-1", Status: ResultPending},
+			},
+		},
+		{name: "empty file", input: "", wantErr: "result file is empty"},
+		{name: "missing status column", input: "end_to_end_id\nDD7-1-1\n", wantErr: "missing status column"},
+		{name: "unknown status", input: "end_to_end_id,status\nDD7-1-1,maybe\n", wantErr: `line 2: unknown status "maybe"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseResults(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseResults() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseResults() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseResults() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
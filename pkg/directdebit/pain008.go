@@ -0,0 +1,134 @@
+package directdebit
+
+import (
+	"encoding/xml"
+	"io"
+	"math"
+)
+
+const pain008Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.008.001.02"
+
+type pain008Document struct {
+	XMLName xml.Name       `xml:"Document"`
+	Xmlns   string         `xml:"xmlns,attr"`
+	Msg     pain008Message `xml:"CstmrDrctDbtInitn"`
+}
+
+type pain008Message struct {
+	GroupHeader pain008GroupHeader `xml:"GrpHdr"`
+	PaymentInfo pain008PaymentInfo `xml:"PmtInf"`
+}
+
+type pain008GroupHeader struct {
+	MessageID  string    `xml:"MsgId"`
+	CreatedAt  string    `xml:"CreDtTm"`
+	NumTxs     int       `xml:"NbOfTxs"`
+	ControlSum string    `xml:"CtrlSum"`
+	Initiating pain008ID `xml:"InitgPty"`
+}
+
+type pain008ID struct {
+	Name string `xml:"Nm"`
+}
+
+type pain008PaymentInfo struct {
+	ID             string               `xml:"PmtInfId"`
+	Method         string               `xml:"PmtMtd"`
+	NumTxs         int                  `xml:"NbOfTxs"`
+	ControlSum     string               `xml:"CtrlSum"`
+	CollectionDate string               `xml:"ReqdColltnDt"`
+	Creditor       pain008ID            `xml:"Cdtr"`
+	CreditorAcct   pain008Account       `xml:"CdtrAcct"`
+	CreditorAgent  pain008Agent         `xml:"CdtrAgt"`
+	SchemeID       *pain008SchemeID     `xml:"CdtrSchmeId,omitempty"`
+	Transactions   []pain008Transaction `xml:"DrctDbtTxInf"`
+}
+
+type pain008Account struct {
+	ID string `xml:"Id>Othr>Id"`
+}
+
+// pain008Agent carries a bank code; a BIC would go in FinInstnId/BIC, but
+// Othr accepts both.
+type pain008Agent struct {
+	ID string `xml:"FinInstnId>Othr>Id"`
+}
+
+type pain008SchemeID struct {
+	ID string `xml:"Id>PrvtId>Othr>Id"`
+}
+
+type pain008Amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type pain008Transaction struct {
+	EndToEndID    string         `xml:"PmtId>EndToEndId"`
+	Amount        pain008Amount  `xml:"InstdAmt"`
+	MandateID     string         `xml:"DrctDbtTx>MndtRltdInf>MndtId"`
+	MandateSigned string         `xml:"DrctDbtTx>MndtRltdInf>DtOfSgntr"`
+	DebtorAgent   pain008Agent   `xml:"DbtrAgt"`
+	Debtor        pain008ID      `xml:"Dbtr"`
+	DebtorAccount pain008Account `xml:"DbtrAcct"`
+	Remittance    string         `xml:"RmtInf>Ustrd,omitempty"`
+}
+
+// writePain008 writes the batch as one pain.008.001.02 payment information
+// block.
+func writePain008(w io.Writer, b *Batch) error {
+	var total float64
+	txs := make([]pain008Transaction, len(b.Instructions))
+	for i, in := range b.Instructions {
+		total += in.Amount
+		txs[i] = pain008Transaction{
+			EndToEndID:    in.EndToEndID,
+			Amount:        pain008Amount{Currency: b.Creditor.Currency, Value: amount(in.Amount)},
+			MandateID:     in.MandateID,
+			MandateSigned: in.MandateSigned.Format("2006-01-02"),
+			DebtorAgent:   pain008Agent{ID: in.DebtorAgent},
+			Debtor:        pain008ID{Name: in.DebtorName},
+			DebtorAccount: pain008Account{ID: in.DebtorAccount},
+			Remittance:    in.Remittance,
+		}
+	}
+	controlSum := amount(math.Round(total*100) / 100)
+
+	doc := pain008Document{
+		Xmlns: pain008Namespace,
+		Msg: pain008Message{
+			GroupHeader: pain008GroupHeader{
+				MessageID:  b.MessageID,
+				CreatedAt:  b.CreatedAt.UTC().Format("2006-01-02T15:04:05"),
+				NumTxs:     len(txs),
+				ControlSum: controlSum,
+				Initiating: pain008ID{Name: b.Creditor.Name},
+			},
+			PaymentInfo: pain008PaymentInfo{
+				ID:             b.MessageID,
+				Method:         "DD",
+				NumTxs:         len(txs),
+				ControlSum:     controlSum,
+				CollectionDate: b.CollectionDate.Format("2006-01-02"),
+				Creditor:       pain008ID{Name: b.Creditor.Name},
+				CreditorAcct:   pain008Account{ID: b.Creditor.Account},
+				CreditorAgent:  pain008Agent{ID: b.Creditor.Agent},
+				Transactions:   txs,
+			},
+		},
+	}
+	if b.Creditor.SchemeID != "" {
+		doc.Msg.PaymentInfo.SchemeID = &pain008SchemeID{ID: b.Creditor.SchemeID}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,112 @@
+package directdebit
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testBatch(schemeID string, amounts ...float64) *Batch {
+	b := &Batch{
+		MessageID:      "DD-RUN-7",
+		CreatedAt:      time.Date(2026, 3, 2, 8, 30, 0, 0, time.FixedZone("WIB", 7*3600)),
+		CollectionDate: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC),
+		Creditor: Creditor{
+			Name:     "Acme Finance",
+			Account:  "1234567890",
+			Agent:    "BANKIDJA",
+			SchemeID: schemeID,
+			Currency: "IDR",
+		},
+	}
+	for i, a := range amounts {
+		b.Instructions = append(b.Instructions, Instruction{
+			EndToEndID:    "DD7-" + string(rune('1'+i)) + "-1",
+			Amount:        a,
+			MandateID:     "MND-000" + string(rune('1'+i)),
+			MandateSigned: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC),
+			DebtorName:    "Budi Santoso",
+			DebtorAccount: "ID12BANK0001234567",
+			DebtorAgent:   "BANKIDJB",
+			Remittance:    "Loan 12 VA 8808000000123",
+		})
+	}
+	return b
+}
+
+func TestWritePain008(t *testing.T) {
+	tests := []struct {
+		name           string
+		batch          *Batch
+		wantNumTxs     int
+		wantControlSum string
+		wantSchemeID   string
+	}{
+		{name: "control sum is rounded to cents", batch: testBatch("ID98ZZZ0001", 0.1, 0.2, 110000.005), wantNumTxs: 3, wantControlSum: "110000.31", wantSchemeID: "ID98ZZZ0001"},
+		{name: "no scheme id", batch: testBatch("", 250000), wantNumTxs: 1, wantControlSum: "250000.00"},
+		{name: "empty batch", batch: testBatch(""), wantControlSum: "0.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(FormatPain008, &buf, tt.batch); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !strings.HasPrefix(buf.String(), xml.Header) {
+				t.Errorf("file does not start with the XML declaration")
+			}
+			if !strings.Contains(buf.String(), `<Document xmlns="`+pain008Namespace+`">`) {
+				t.Errorf("file does not declare the pain.008 namespace")
+			}
+
+			var doc pain008Document
+			if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+				t.Fatalf("file does not parse back: %v", err)
+			}
+			hdr, info := doc.Msg.GroupHeader, doc.Msg.PaymentInfo
+			if hdr.MessageID != "DD-RUN-7" || hdr.CreatedAt != "2026-03-02T01:30:00" {
+				t.Errorf("group header = %+v, want message DD-RUN-7 created 2026-03-02T01:30:00 UTC", hdr)
+			}
+			if hdr.NumTxs != tt.wantNumTxs || info.NumTxs != tt.wantNumTxs || len(info.Transactions) != tt.wantNumTxs {
+				t.Errorf("transactions = %d/%d/%d, want %d", hdr.NumTxs, info.NumTxs, len(info.Transactions), tt.wantNumTxs)
+			}
+			if hdr.ControlSum != tt.wantControlSum || info.ControlSum != tt.wantControlSum {
+				t.Errorf("control sums = %s/%s, want %s", hdr.ControlSum, info.ControlSum, tt.wantControlSum)
+			}
+			if info.Method != "DD" || info.CollectionDate != "2026-03-04" {
+				t.Errorf("payment info = %s on %s, want DD on 2026-03-04", info.Method, info.CollectionDate)
+			}
+			gotSchemeID := ""
+			if info.SchemeID != nil {
+				gotSchemeID = info.SchemeID.ID
+			}
+			if gotSchemeID != tt.wantSchemeID {
+				t.Errorf("scheme id = %q, want %q", gotSchemeID, tt.wantSchemeID)
+			}
+			for i, tx := range info.Transactions {
+				in := tt.batch.Instructions[i]
+				want := pain008Transaction{
+					EndToEndID:    in.EndToEndID,
+					Amount:        pain008Amount{Currency: "IDR", Value: amount(in.Amount)},
+					MandateID:     in.MandateID,
+					MandateSigned: "2026-01-05",
+					DebtorAgent:   pain008Agent{ID: "BANKIDJB"},
+					Debtor:        pain008ID{Name: "Budi Santoso"},
+					DebtorAccount: pain008Account{ID: "ID12BANK0001234567"},
+					Remittance:    "Loan 12 VA 8808000000123",
+				}
+				if tx != want {
+					t.Errorf("transaction %d = %+v, want %+v", i+1, tx, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := Write("mt101", &bytes.Buffer{}, testBatch("")); err != ErrUnknownFormat {
+		t.Errorf("Write() error = %v, want %v", err, ErrUnknownFormat)
+	}
+}
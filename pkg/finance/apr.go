@@ -0,0 +1,116 @@
+package finance
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNoIRR is returned when the cash flows have no internal rate of return,
+// e.g. when they never change sign.
+var ErrNoIRR = errors.New("cash flows have no internal rate of return")
+
+const (
+	irrTolerance     = 1e-10
+	irrMaxIterations = 200
+)
+
+// IRR returns the per-period internal rate of return of cashFlows, where
+// cashFlows[i] is the net flow at the end of period i. It uses Newton's method
+// and falls back to bisection when Newton does not converge.
+func IRR(cashFlows []float64) (float64, error) {
+	if !hasSignChange(cashFlows) {
+		return 0, ErrNoIRR
+	}
+
+	rate := 0.01
+	for i := 0; i < irrMaxIterations; i++ {
+		npv, dnpv := npvWithDerivative(cashFlows, rate)
+		if math.Abs(npv) < irrTolerance {
+			return rate, nil
+		}
+		if dnpv == 0 {
+			break
+		}
+		next := rate - npv/dnpv
+		if next <= -1 || math.IsNaN(next) || math.IsInf(next, 0) {
+			break
+		}
+		if math.Abs(next-rate) < irrTolerance {
+			return next, nil
+		}
+		rate = next
+	}
+	return bisectIRR(cashFlows)
+}
+
+// DisclosureRates computes the APR and effective annual rate, both as
+// percentages, for a loan that disburses principal minus upfrontFees and is
+// repaid by installments falling due once per period.
+//
+// The APR is the periodic IRR multiplied by periodsPerYear; the effective
+// annual rate compounds the periodic IRR over a year.
+func DisclosureRates(principal, upfrontFees float64, installments []float64, periodsPerYear int) (apr, effectiveRate float64, err error) {
+	cashFlows := make([]float64, 0, len(installments)+1)
+	cashFlows = append(cashFlows, -(principal - upfrontFees))
+	cashFlows = append(cashFlows, installments...)
+
+	rate, err := IRR(cashFlows)
+	if err != nil {
+		return 0, 0, err
+	}
+	apr = rate * float64(periodsPerYear) * 100
+	effectiveRate = (math.Pow(1+rate, float64(periodsPerYear)) - 1) * 100
+	return round(apr, 4), round(effectiveRate, 4), nil
+}
+
+func npvWithDerivative(cashFlows []float64, rate float64) (npv, dnpv float64) {
+	for t, cf := range cashFlows {
+		discount := math.Pow(1+rate, float64(t))
+		npv += cf / discount
+		dnpv -= float64(t) * cf / (discount * (1 + rate))
+	}
+	return npv, dnpv
+}
+
+func bisectIRR(cashFlows []float64) (float64, error) {
+	low, high := -0.9999, 1.0
+	npvLow, _ := npvWithDerivative(cashFlows, low)
+	npvHigh, _ := npvWithDerivative(cashFlows, high)
+	for npvLow*npvHigh > 0 {
+		high *= 2
+		if high > 1e6 {
+			return 0, ErrNoIRR
+		}
+		npvHigh, _ = npvWithDerivative(cashFlows, high)
+	}
+	for i := 0; i < 1000; i++ {
+		mid := (low + high) / 2
+		npvMid, _ := npvWithDerivative(cashFlows, mid)
+		if math.Abs(npvMid) < irrTolerance || (high-low)/2 < irrTolerance {
+			return mid, nil
+		}
+		if npvMid*npvLow < 0 {
+			high = mid
+		} else {
+			low, npvLow = mid, npvMid
+		}
+	}
+	return 0, ErrNoIRR
+}
+
+func hasSignChange(cashFlows []float64) bool {
+	var pos, neg bool
+	for _, cf := range cashFlows {
+		if cf > 0 {
+			pos = true
+		} else if cf < 0 {
+			neg = true
+		}
+	}
+	return pos && neg
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,99 @@
+package finance
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestIRR(t *testing.T) {
+	tests := []struct {
+		name      string
+		cashFlows []float64
+		want      float64
+		wantErr   error
+	}{
+		{name: "one period", cashFlows: []float64{-100, 110}, want: 0.10},
+		{name: "two periods, paid at the end", cashFlows: []float64{-100, 0, 121}, want: 0.10},
+		{name: "annuity", cashFlows: []float64{-1000, 576.19, 576.19}, want: 0.10},
+		{name: "zero rate", cashFlows: []float64{-100, 50, 50}, want: 0},
+		{name: "negative rate", cashFlows: []float64{-100, 90}, want: -0.10},
+		{name: "no sign change", cashFlows: []float64{100, 110}, wantErr: ErrNoIRR},
+		{name: "all zero", cashFlows: []float64{0, 0, 0}, wantErr: ErrNoIRR},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IRR(tt.cashFlows)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("IRR() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && math.Abs(got-tt.want) > 1e-4 {
+				t.Errorf("IRR() = %.6f, want %.6f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisclosureRates(t *testing.T) {
+	tests := []struct {
+		name           string
+		principal      float64
+		upfrontFees    float64
+		installments   []float64
+		periodsPerYear int
+		wantAPR        float64
+		wantEffective  float64
+		wantErr        error
+	}{
+		{
+			name:           "annual period",
+			principal:      1000,
+			installments:   []float64{1100},
+			periodsPerYear: 1,
+			wantAPR:        10,
+			wantEffective:  10,
+		},
+		{
+			name:           "monthly period compounds",
+			principal:      1000,
+			installments:   []float64{1100},
+			periodsPerYear: 12,
+			wantAPR:        120,
+			wantEffective:  213.8428,
+		},
+		{
+			name:           "upfront fees reduce the amount disbursed",
+			principal:      1100,
+			upfrontFees:    100,
+			installments:   []float64{1100},
+			periodsPerYear: 1,
+			wantAPR:        10,
+			wantEffective:  10,
+		},
+		{
+			name:           "fees take the whole principal",
+			principal:      1000,
+			upfrontFees:    1000,
+			installments:   []float64{1100},
+			periodsPerYear: 52,
+			wantErr:        ErrNoIRR,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apr, effective, err := DisclosureRates(tt.principal, tt.upfrontFees, tt.installments, tt.periodsPerYear)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DisclosureRates() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if math.Abs(apr-tt.wantAPR) > 1e-3 {
+				t.Errorf("apr = %.4f, want %.4f", apr, tt.wantAPR)
+			}
+			if math.Abs(effective-tt.wantEffective) > 1e-3 {
+				t.Errorf("effective rate = %.4f, want %.4f", effective, tt.wantEffective)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	UserIDHeader   = "X-User-ID"
+	UserRoleHeader = "X-User-Role"
+	UserKeyHeader  = "X-User-Key"
+)
+
+var (
+	ErrActorRequired   = apperror.New(apperror.Invalid, "user_id_required", "acting user required: send "+UserIDHeader+", or "+UserKeyHeader+" where user keys are configured")
+	ErrInvalidUserKey  = apperror.New(apperror.Unauthorized, "invalid_user_key", UserKeyHeader+" is not a known user key")
+	ErrUserKeyRequired = apperror.New(apperror.Forbidden, "user_key_required",
+		"this decision needs the acting user authenticated by "+UserKeyHeader+"; configure USER_KEYS")
+)
+
+// identity is a user authenticated by their key, with the role it was
+// issued for.
+type identity struct {
+	key    string
+	userID string
+	role   string
+}
+
+const identityKey = "middleware.identity"
+
+// Identify authenticates the acting user by X-User-Key when user keys are
+// configured. userKeys maps each user to "role:key"; entries without both
+// are ignored. A request with a valid key acts as that user in that role,
+// and the X-User-ID and X-User-Role headers are ignored; a request without a
+// key has no acting user. With no user keys, Actor and ActorRole trust the
+// headers as asserted by the upstream gateway.
+func Identify(userKeys map[string]string) gin.HandlerFunc {
+	var identities []identity
+	for userID, v := range userKeys {
+		role, key, ok := strings.Cut(v, ":")
+		if ok && role != "" && key != "" {
+			identities = append(identities, identity{key: key, userID: userID, role: role})
+		}
+	}
+	return func(c *gin.Context) {
+		if len(identities) == 0 {
+			c.Next()
+			return
+		}
+		var found identity
+		if got := c.GetHeader(UserKeyHeader); got != "" {
+			// Compare against every key so the time taken does not tell
+			// which one matched.
+			for _, id := range identities {
+				if subtle.ConstantTimeCompare([]byte(got), []byte(id.key)) == 1 {
+					found = id
+				}
+			}
+			if found.userID == "" {
+				abort(c, ErrInvalidUserKey)
+				return
+			}
+		}
+		c.Set(identityKey, found)
+		c.Next()
+	}
+}
+
+// RequireUserKey refuses the request unless Identify authenticated the
+// acting user by key. Maker-checker decisions use it, since users asserted
+// in headers could approve their own requests by sending another name.
+func RequireUserKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := c.Get(identityKey)
+		if !ok || id.(identity).userID == "" {
+			abort(c, ErrUserKeyRequired)
+			return
+		}
+		c.Next()
+	}
+}
+
+// Actor returns the user acting on the request: the one authenticated by
+// X-User-Key where user keys are configured, otherwise the one asserted by
+// the upstream gateway in the X-User-ID header.
+func Actor(c *gin.Context) string {
+	if id, ok := c.Get(identityKey); ok {
+		return id.(identity).userID
+	}
+	return c.GetHeader(UserIDHeader)
+}
+
+// ActorRole returns the acting user's role, from their key where user keys
+// are configured, otherwise from the X-User-Role header.
+func ActorRole(c *gin.Context) string {
+	if id, ok := c.Get(identityKey); ok {
+		return id.(identity).role
+	}
+	return c.GetHeader(UserRoleHeader)
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/evrintobing17/loan-billing-system/pkg/clock"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	AdminKeyHeader = "X-Admin-Key"
+	AsOfDateHeader = "X-As-Of-Date"
+)
+
+var ErrAdminRequired = apperror.New(apperror.Forbidden, "admin_required", "admin access required")
+
+// IsAdmin reports whether the request carries the configured admin key.
+// An empty adminKey disables admin access entirely.
+func IsAdmin(c *gin.Context, adminKey string) bool {
+	if adminKey == "" {
+		return false
+	}
+	got := c.GetHeader(AdminKeyHeader)
+	return subtle.ConstantTimeCompare([]byte(got), []byte(adminKey)) == 1
+}
+
+// RequireAdmin rejects requests that do not carry the admin key.
+func RequireAdmin(adminKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsAdmin(c, adminKey) {
+			abort(c, ErrAdminRequired)
+			return
+		}
+		c.Next()
+	}
+}
+
+// AsOfDate lets admins simulate a different business date by sending
+// X-As-Of-Date: YYYY-MM-DD. The date is stored on the request context and
+// picked up by clock.Today.
+func AsOfDate(adminKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.GetHeader(AsOfDateHeader)
+		if raw == "" {
+			c.Next()
+			return
+		}
+		if !IsAdmin(c, adminKey) {
+			abort(c, ErrAdminRequired.Withf("%s header requires admin access", AsOfDateHeader))
+			return
+		}
+		date, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			abort(c, apperror.Invalidf("invalid %s format, use YYYY-MM-DD", AsOfDateHeader))
+			return
+		}
+		c.Request = c.Request.WithContext(clock.WithAsOfDate(c.Request.Context(), date))
+		c.Next()
+	}
+}
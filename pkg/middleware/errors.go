@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/evrintobing17/loan-billing-system/pkg/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of error responses.
+const ProblemContentType = "application/problem+json"
+
+var statusByKind = map[apperror.Kind]int{
+	apperror.Internal:      http.StatusInternalServerError,
+	apperror.Invalid:       http.StatusBadRequest,
+	apperror.NotFound:      http.StatusNotFound,
+	apperror.Conflict:      http.StatusConflict,
+	apperror.Forbidden:     http.StatusForbidden,
+	apperror.Unprocessable: http.StatusUnprocessableEntity,
+	apperror.Unauthorized:  http.StatusUnauthorized,
+}
+
+// Errors renders the last error a handler attached with c.Error as an RFC
+// 7807 problem, unless a response has already been written. Errors without
+// a code are internal: they are logged and their detail is not exposed.
+func Errors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+		e, ok := apperror.Lookup(apperror.Default(err, apperror.Internal))
+		if !ok || e.Kind == apperror.Internal {
+			log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+			e = apperror.ErrInternal
+		}
+
+		// Members follow models.Problem; error fields cannot override them.
+		status := statusByKind[e.Kind]
+		problem := gin.H{}
+		for k, v := range e.Fields {
+			problem[k] = v
+		}
+		problem["type"] = "/problems/" + e.Code
+		problem["title"] = http.StatusText(status)
+		problem["status"] = status
+		problem["detail"] = e.Message
+		problem["instance"] = c.Request.URL.Path
+		problem["code"] = e.Code
+		c.Header("Content-Type", ProblemContentType)
+		c.JSON(status, problem)
+	}
+}
+
+// abort stops the chain with err, to be rendered by Errors.
+func abort(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
@@ -0,0 +1,48 @@
+// Package outbox records domain events in the transaction of the change they
+// describe. The event relay publishes them once that transaction commits, so
+// an event is never published for a change that rolled back, and never lost
+// for one that committed.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/evrintobing17/loan-billing-system/models"
+	"github.com/evrintobing17/loan-billing-system/pkg/postgres"
+)
+
+// LoanEvent builds an event about a loan with data marshalled as its payload.
+func LoanEvent(eventType string, version, loanID int, occurredAt time.Time, data any) (models.OutboxEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return models.OutboxEvent{}, fmt.Errorf("marshal %s event: %w", eventType, err)
+	}
+	return models.OutboxEvent{Event: models.Event{
+		Type:          eventType,
+		Version:       version,
+		AggregateType: "loan",
+		AggregateID:   strconv.Itoa(loanID),
+		OccurredAt:    occurredAt,
+		Data:          payload,
+	}}, nil
+}
+
+// Append writes the events to the outbox with tx. An event whose DedupKey is
+// taken is dropped.
+func Append(ctx context.Context, tx postgres.DBTX, events ...models.OutboxEvent) error {
+	for _, e := range events {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO outbox_events (event_type, version, aggregate_type, aggregate_id, occurred_at, data, dedup_key)
+             VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''))
+             ON CONFLICT (dedup_key) DO NOTHING`,
+			e.Type, e.Version, e.AggregateType, e.AggregateID, e.OccurredAt, []byte(e.Data), e.DedupKey)
+		if err != nil {
+			return fmt.Errorf("append %s event: %w", e.Type, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Package virtualaccount builds and checks the virtual account numbers
+// borrowers pay their loans into. A number is the bank's prefix, the loan ID
+// padded to 8 digits and a Luhn check digit, e.g. 8808 00000012 3, at most
+// MaxLength digits in all.
+package virtualaccount
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Length limits of a virtual account number, check digit included. The
+// loans.virtual_account column holds MaxLength characters.
+const (
+	MinLength = 10
+	MaxLength = 20
+	// MaxPrefixLength leaves room for an 8-digit loan ID and the check digit.
+	MaxPrefixLength = MaxLength - 8 - 1
+)
+
+// ErrTooLong is returned by New when the prefix and loan ID do not fit in
+// MaxLength digits.
+var ErrTooLong = errors.New("virtual account number is too long")
+
+// New returns the virtual account number of a loan. A loan ID above
+// 99999999 takes more digits, and fails if the number would exceed MaxLength.
+func New(prefix string, loanID int) (string, error) {
+	if loanID < 0 || !ValidPrefix(prefix) {
+		return "", fmt.Errorf("invalid prefix %q or loan ID %d", prefix, loanID)
+	}
+	base := fmt.Sprintf("%s%08d", prefix, loanID)
+	if len(base)+1 > MaxLength {
+		return "", fmt.Errorf("%w: prefix %s and loan %d need %d digits, at most %d fit", ErrTooLong, prefix, loanID, len(base)+1, MaxLength)
+	}
+	return fmt.Sprintf("%s%d", base, checkDigit(base)), nil
+}
+
+// ValidPrefix reports whether prefix is 1 to MaxPrefixLength digits, which
+// leaves room for an 8-digit loan ID in a number of at most MaxLength digits.
+func ValidPrefix(prefix string) bool {
+	return prefix != "" && digitsOnly(prefix) && len(prefix) <= MaxPrefixLength
+}
+
+// Valid reports whether number has the form of a virtual account number and
+// a correct check digit. Spaces and dashes people type are not accepted;
+// use Normalize first.
+func Valid(number string) bool {
+	if len(number) < MinLength || len(number) > MaxLength || !digitsOnly(number) {
+		return false
+	}
+	last := len(number) - 1
+	return int(number[last]-'0') == checkDigit(number[:last])
+}
+
+// Normalize removes the spaces and dashes a number is often written with.
+func Normalize(number string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(number))
+}
+
+// checkDigit computes the Luhn check digit of digits.
+func checkDigit(digits string) int {
+	sum := 0
+	double := true // the rightmost payload digit is doubled
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
+
+func digitsOnly(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package virtualaccount
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   int
+	}{
+		{digits: "7992739871", want: 3},
+		{digits: "0", want: 0},
+		{digits: "1", want: 8},
+		{digits: "880800000012", want: 3},
+		{digits: "424242424242424", want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.digits, func(t *testing.T) {
+			if got := checkDigit(tt.digits); got != tt.want {
+				t.Errorf("checkDigit(%q) = %d, want %d", tt.digits, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		loanID  int
+		want    string
+		wantErr error
+	}{
+		{prefix: "8808", loanID: 12, want: "8808000000123"},
+		{prefix: "8808", loanID: 1, want: "8808000000016"},
+		{prefix: "1", loanID: 12345678, want: "1123456780"},
+		{prefix: "8808", loanID: 123456789, want: "88081234567894"},
+		{prefix: "12345678901", loanID: 99999999, want: "12345678901999999993"},
+		{prefix: "12345678901", loanID: 100000000, wantErr: ErrTooLong},
+		{prefix: "8808", loanID: 1234567890123456, wantErr: ErrTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%d", tt.prefix, tt.loanID), func(t *testing.T) {
+			got, err := New(tt.prefix, tt.loanID)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("New(%q, %d) error = %v, want %v", tt.prefix, tt.loanID, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New(%q, %d) error = %v", tt.prefix, tt.loanID, err)
+			}
+			if got != tt.want {
+				t.Errorf("New(%q, %d) = %s, want %s", tt.prefix, tt.loanID, got, tt.want)
+			}
+			if !Valid(got) {
+				t.Errorf("Valid(%s) = false for a number New made", got)
+			}
+		})
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name   string
+		number string
+		want   bool
+	}{
+		{name: "correct check digit", number: "8808000000123", want: true},
+		{name: "wrong check digit", number: "8808000000124"},
+		{name: "transposed digits", number: "8808000000213"},
+		{name: "single digit changed", number: "8808000000133"},
+		{name: "too short", number: "112345678"},
+		{name: "too long", number: "123456789012345678903"},
+		{name: "not digits", number: "88080000001a3"},
+		{name: "written with spaces", number: "8808 00000012 3"},
+		{name: "empty", number: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Valid(tt.number); got != tt.want {
+				t.Errorf("Valid(%q) = %v, want %v", tt.number, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		number string
+		want   string
+	}{
+		{number: "8808 00000012 3", want: "8808000000123"},
+		{number: " 8808-0000-0012-3 ", want: "8808000000123"},
+		{number: "8808000000123", want: "8808000000123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			if got := Normalize(tt.number); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.number, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidPrefix(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   bool
+	}{
+		{prefix: "8808", want: true},
+		{prefix: "12345678901", want: true},
+		{prefix: "123456789012"},
+		{prefix: ""},
+		{prefix: "88a8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			if got := ValidPrefix(tt.prefix); got != tt.want {
+				t.Errorf("ValidPrefix(%q) = %v, want %v", tt.prefix, got, tt.want)
+			}
+		})
+	}
+}